@@ -4,6 +4,7 @@ package iec61850
 import "C"
 import (
 	"fmt"
+	"log/slog"
 	"sync/atomic"
 	"unsafe"
 )
@@ -11,6 +12,7 @@ import (
 type Client struct {
 	conn      C.IedConnection
 	tlsConfig C.TLSConfiguration
+	settings  Settings
 	connected *atomic.Bool
 	// closedHandlerId stores the callback id for the connection closed handler (if installed)
 	closedHandlerId int32
@@ -22,6 +24,10 @@ type Settings struct {
 	Port           int
 	ConnectTimeout uint // Connection timeout in milliseconds
 	RequestTimeout uint // Request timeout in milliseconds
+	// Logger receives diagnostic output of the client, including TLS events. Nothing is logged when nil.
+	Logger *slog.Logger
+	// Progress is called while long running discovery operations like GetDataModel advance.
+	Progress func(Progress)
 }
 
 func NewSettings() Settings {
@@ -47,7 +53,7 @@ func NewClient(settings Settings) (*Client, error) {
 }
 
 func newClient(settings Settings, tlsConfig *TLSConfig) (*Client, error) {
-	client := &Client{}
+	client := &Client{settings: settings}
 
 	if err := client.connect(settings, tlsConfig); err != nil {
 		return nil, fmt.Errorf("connect to %s:%d failed: %w", settings.Host, settings.Port, err)
@@ -61,16 +67,22 @@ func newClient(settings Settings, tlsConfig *TLSConfig) (*Client, error) {
 
 // WriteObject a single attribute value; Structure is not supported
 func (c *Client) WriteObject(objectRef string, fc FC, value interface{}) error {
-	mmsType, err := c.GetVariableSpecType(objectRef, fc)
+	spec, err := c.getVariableSpec(objectRef, fc)
 	if err != nil {
 		return fmt.Errorf("WriteObject get type %q fc=%s: %w", objectRef, fc, err)
 	}
+	defer C.MmsVariableSpecification_destroy(spec)
 	var (
 		mmsValue    *C.MmsValue
 		clientError C.IedClientError
 	)
 
-	mmsValue, err = toMmsValue(mmsType, value)
+	// bit strings, also those of structures, take the sizes of the variable
+	like := C.MmsValue_newDefaultValue(spec)
+	if like != nil {
+		defer C.MmsValue_delete(like)
+	}
+	mmsValue, err = toMmsValueLike(specMmsType(spec), value, like)
 	if err != nil {
 		return fmt.Errorf("WriteObject convert value for %q fc=%s: %w", objectRef, fc, err)
 	}
@@ -243,41 +255,54 @@ func (c *Client) Close() {
 
 // GetVariableSpecType gets the variable specification type
 func (c *Client) GetVariableSpecType(objectReference string, fc FC) (MmsType, error) {
+	spec, err := c.getVariableSpec(objectReference, fc)
+	if err != nil {
+		return 0, fmt.Errorf("GetVariableSpecType %q fc=%s: %w", objectReference, fc, err)
+	}
+	defer C.MmsVariableSpecification_destroy(spec)
+	return specMmsType(spec), nil
+}
+
+// getVariableSpec gets the variable specification, which has to be destroyed by the caller.
+func (c *Client) getVariableSpec(objectReference string, fc FC) (*C.MmsVariableSpecification, error) {
 	var clientError C.IedClientError
 	cObjectRef := C.CString(objectReference)
 	defer C.free(unsafe.Pointer(cObjectRef))
 
-	// Get type
 	spec := C.IedConnection_getVariableSpecification(c.conn, &clientError, cObjectRef, C.FunctionalConstraint(fc))
 	if err := GetIedClientError(clientError); err != nil {
-		return 0, fmt.Errorf("GetVariableSpecType %q fc=%s: %w", objectReference, fc, err)
+		return nil, err
 	}
-	defer C.MmsVariableSpecification_destroy(spec)
+	return spec, nil
+}
+
+// specMmsType returns the type of the variable specification, integers by their size.
+func specMmsType(spec *C.MmsVariableSpecification) MmsType {
 	mmsType := MmsType(C.MmsVariableSpecification_getType(spec))
 	switch mmsType {
 	case Integer:
 		i := int(spec.typeSpec[0])
 		switch i {
 		case 8:
-			return Int8, nil
+			return Int8
 		case 16:
-			return Int16, nil
+			return Int16
 		case 32:
-			return Int32, nil
+			return Int32
 		default:
-			return Int64, nil
+			return Int64
 		}
 	case Unsigned:
 		switch int(spec.typeSpec[0]) {
 		case 8:
-			return Uint8, nil
+			return Uint8
 		case 16:
-			return Uint16, nil
+			return Uint16
 		default:
-			return Uint32, nil
+			return Uint32
 		}
 	default:
-		return mmsType, nil
+		return mmsType
 	}
 }
 
@@ -314,6 +339,7 @@ func (c *Client) connect(settings Settings, tlsConfig *TLSConfig) error {
 		if err != nil {
 			return fmt.Errorf("create TLS configuration: %w", err)
 		}
+		setTLSEventLogger(_tlsConfig, settings.Logger)
 
 		c.tlsConfig = _tlsConfig
 		conn = C.IedConnection_createWithTlsSupport(_tlsConfig)
@@ -13,6 +13,7 @@ import "C"
 
 import (
 	"fmt"
+	"log/slog"
 	"runtime/cgo"
 	"unsafe"
 )
@@ -30,10 +31,12 @@ type VarSpecHandler func(invokeID uint32, spec *MmsVariableSpec, err error)
 // internal contexts stored as cgo.Handle to survive C roundtrip
 type nameListCtx struct {
 	handler NameListHandler
+	logger  *slog.Logger
 }
 
 type varSpecCtx struct {
 	handler VarSpecHandler
+	logger  *slog.Logger
 }
 
 // storeHandleInC allocates a small C memory block to hold the cgo.Handle value
@@ -57,14 +60,19 @@ func nameListCallbackFunctionBridge(invokeId C.uint32_t, parameter unsafe.Pointe
 	}
 
 	var handler NameListHandler
+	logger := discardLogger
 	if h != 0 {
 		if ctx, ok := h.Value().(nameListCtx); ok && ctx.handler != nil {
 			handler = ctx.handler
+			logger = ctx.logger
 		}
 	}
 
 	// Convert error
 	goErr := GetIedClientError(err)
+	if goErr != nil {
+		logger.Debug("async name list request failed", "invokeID", uint32(invokeId), "error", goErr)
+	}
 
 	// Convert LinkedList -> []string
 	names := make([]string, 0)
@@ -100,13 +108,18 @@ func varSpecCallbackFunctionBridge(invokeId C.uint32_t, parameter unsafe.Pointer
 		h = cgo.Handle(u)
 	}
 	var handler VarSpecHandler
+	logger := discardLogger
 	if h != 0 {
 		if ctx, ok := h.Value().(varSpecCtx); ok && ctx.handler != nil {
 			handler = ctx.handler
+			logger = ctx.logger
 		}
 	}
 
 	goErr := GetIedClientError(err)
+	if goErr != nil {
+		logger.Debug("async variable specification request failed", "invokeID", uint32(invokeId), "error", goErr)
+	}
 
 	var goSpec *MmsVariableSpec
 	if spec != nil {
@@ -191,7 +204,7 @@ func (c *Client) GetServerDirectoryAsync(continueAfter string, handler NameListH
 	}
 
 	// store handler in handle so we avoid race before registration
-	h := cgo.NewHandle(nameListCtx{handler: handler})
+	h := cgo.NewHandle(nameListCtx{handler: handler, logger: c.logger()})
 	param := storeHandleInC(h)
 	invokeId := C.IedConnection_getServerDirectoryAsync(c.conn, &clientError, cContinue, nil, (C.IedConnection_GetNameListHandler)(C.nameListCallbackBridge), param)
 	if err := GetIedClientError(clientError); err != nil {
@@ -201,6 +214,7 @@ func (c *Client) GetServerDirectoryAsync(continueAfter string, handler NameListH
 		}
 		return 0, fmt.Errorf("GetServerDirectoryAsync continueAfter=%q: %w", continueAfter, err)
 	}
+	c.logger().Debug("GetServerDirectoryAsync sent", "invokeID", uint32(invokeId))
 	return uint32(invokeId), nil
 }
 
@@ -214,7 +228,7 @@ func (c *Client) GetLogicalDeviceVariablesAsync(ldName, continueAfter string, ha
 		cContinue = Go2CStr(continueAfter)
 		defer C.free(unsafe.Pointer(cContinue))
 	}
-	h := cgo.NewHandle(nameListCtx{handler: handler})
+	h := cgo.NewHandle(nameListCtx{handler: handler, logger: c.logger()})
 	param := storeHandleInC(h)
 	invokeId := C.IedConnection_getLogicalDeviceVariablesAsync(c.conn, &clientError, cLd, cContinue, nil, (C.IedConnection_GetNameListHandler)(C.nameListCallbackBridge), param)
 	if err := GetIedClientError(clientError); err != nil {
@@ -224,6 +238,7 @@ func (c *Client) GetLogicalDeviceVariablesAsync(ldName, continueAfter string, ha
 		}
 		return 0, fmt.Errorf("GetLogicalDeviceVariablesAsync ld=%q continueAfter=%q: %w", ldName, continueAfter, err)
 	}
+	c.logger().Debug("GetLogicalDeviceVariablesAsync sent", "ref", ldName, "invokeID", uint32(invokeId))
 	return uint32(invokeId), nil
 }
 
@@ -237,7 +252,7 @@ func (c *Client) GetLogicalDeviceDataSetsAsync(ldName, continueAfter string, han
 		cContinue = Go2CStr(continueAfter)
 		defer C.free(unsafe.Pointer(cContinue))
 	}
-	h := cgo.NewHandle(nameListCtx{handler: handler})
+	h := cgo.NewHandle(nameListCtx{handler: handler, logger: c.logger()})
 	param := storeHandleInC(h)
 	invokeId := C.IedConnection_getLogicalDeviceDataSetsAsync(c.conn, &clientError, cLd, cContinue, nil, (C.IedConnection_GetNameListHandler)(C.nameListCallbackBridge), param)
 	if err := GetIedClientError(clientError); err != nil {
@@ -247,6 +262,7 @@ func (c *Client) GetLogicalDeviceDataSetsAsync(ldName, continueAfter string, han
 		}
 		return 0, fmt.Errorf("GetLogicalDeviceDataSetsAsync ld=%q continueAfter=%q: %w", ldName, continueAfter, err)
 	}
+	c.logger().Debug("GetLogicalDeviceDataSetsAsync sent", "ref", ldName, "invokeID", uint32(invokeId))
 	return uint32(invokeId), nil
 }
 
@@ -256,7 +272,7 @@ func (c *Client) GetVariableSpecificationAsync(dataAttributeReference string, fc
 	cRef := Go2CStr(dataAttributeReference)
 	defer C.free(unsafe.Pointer(cRef))
 
-	h := cgo.NewHandle(varSpecCtx{handler: handler})
+	h := cgo.NewHandle(varSpecCtx{handler: handler, logger: c.logger()})
 	param := storeHandleInC(h)
 	invokeId := C.IedConnection_getVariableSpecificationAsync(c.conn, &clientError, cRef, C.FunctionalConstraint(fc), (C.IedConnection_GetVariableSpecificationHandler)(C.varSpecCallbackBridge), param)
 	if err := GetIedClientError(clientError); err != nil {
@@ -266,5 +282,6 @@ func (c *Client) GetVariableSpecificationAsync(dataAttributeReference string, fc
 		}
 		return 0, fmt.Errorf("GetVariableSpecificationAsync ref=%q fc=%s: %w", dataAttributeReference, fc, err)
 	}
+	c.logger().Debug("GetVariableSpecificationAsync sent", "ref", dataAttributeReference, "fc", fc, "invokeID", uint32(invokeId))
 	return uint32(invokeId), nil
 }
@@ -0,0 +1,436 @@
+package iec61850
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dataNode is a data object or data attribute merged from the per-FC variable specifications of a data object.
+type dataNode struct {
+	name     string
+	isDO     bool
+	children []*dataNode
+	fc       FC
+	spec     *MmsVariableSpec
+	value    *MmsValue
+}
+
+func (n *dataNode) child(name string) *dataNode {
+	for _, child := range n.children {
+		if child.name == name {
+			return child
+		}
+	}
+	child := &dataNode{name: name}
+	n.children = append(n.children, child)
+	return child
+}
+
+// readDataObject reads the type, and with withValues the value, of a data object for each functional
+// constraint it has.
+func (c *Client) readDataObject(doRef string, withValues bool) (*dataNode, error) {
+	entries, err := c.GetDataDirectoryFC(doRef)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &dataNode{isDO: true}
+	var fcs []FC
+	for _, entry := range entries {
+		i := strings.LastIndex(entry, "[")
+		if i == -1 || !strings.HasSuffix(entry, "]") {
+			continue
+		}
+		node.child(entry[:i])
+		fc := FunctionalConstraintFromString(entry[i+1 : len(entry)-1])
+		if fc != NONE && !containsFC(fcs, fc) {
+			fcs = append(fcs, fc)
+		}
+	}
+
+	for _, fc := range fcs {
+		spec, err := c.GetVariableSpecification(doRef, fc)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%s]: %w", doRef, fc, err)
+		}
+		var value *MmsValue
+		if withValues && fc != CO && fc != SE {
+			// write-only or unreadable attributes are left without values
+			value, _ = c.ReadObject(doRef, fc)
+		}
+		node.merge(spec, value, fc)
+	}
+	return node, nil
+}
+
+func containsFC(fcs []FC, fc FC) bool {
+	for _, f := range fcs {
+		if f == fc {
+			return true
+		}
+	}
+	return false
+}
+
+// merge adds the elements of a data object specification for one FC to the node.
+func (n *dataNode) merge(spec *MmsVariableSpec, value *MmsValue, fc FC) {
+	if spec.Structure == nil {
+		return
+	}
+	var values []*MmsValue
+	if value != nil {
+		values, _ = value.Value.([]*MmsValue)
+	}
+	for i := range spec.Structure.Elements {
+		element := &spec.Structure.Elements[i]
+		var elementValue *MmsValue
+		if i < len(values) {
+			elementValue = values[i]
+		}
+		child := n.child(element.Name)
+		// data object names start upper case, data attribute names lower case
+		if element.Type == Structure && isUpperName(element.Name) {
+			child.isDO = true
+			child.merge(element, elementValue, fc)
+			continue
+		}
+		child.fc = fc
+		child.spec = element
+		child.value = elementValue
+	}
+}
+
+func isUpperName(name string) bool {
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z'
+}
+
+// basicType maps an MMS type to the SCL basic type of the attribute.
+func basicType(name string, spec *MmsVariableSpec) string {
+	switch spec.Type {
+	case Structure:
+		return "Struct"
+	case Boolean:
+		return "BOOLEAN"
+	case Integer:
+		switch {
+		case spec.IntegerBits <= 8:
+			return "INT8"
+		case spec.IntegerBits <= 16:
+			return "INT16"
+		case spec.IntegerBits <= 32:
+			return "INT32"
+		default:
+			return "INT64"
+		}
+	case Unsigned:
+		switch {
+		case spec.UnsignedBits <= 8:
+			return "INT8U"
+		case spec.UnsignedBits <= 16:
+			return "INT16U"
+		case spec.UnsignedBits <= 24:
+			return "INT24U"
+		default:
+			return "INT32U"
+		}
+	case Float:
+		if spec.FloatFormatWidth > 32 {
+			return "FLOAT64"
+		}
+		return "FLOAT32"
+	case BitString:
+		switch spec.BitStringSize {
+		case 2:
+			switch name {
+			case "Check":
+				return "Check"
+			case "ctlVal":
+				return "Tcmd"
+			default:
+				return "Dbpos"
+			}
+		case 6:
+			return "TrgOps"
+		case 10:
+			return "OptFlds"
+		default:
+			return "Quality"
+		}
+	case OctetString:
+		if spec.OctetStringSize == 8 {
+			return "EntryID"
+		}
+		return "Octet64"
+	case VisibleString:
+		switch {
+		case spec.VisibleStringSize <= 32 && spec.VisibleStringSize > 0:
+			return "VisString32"
+		case spec.VisibleStringSize <= 64 && spec.VisibleStringSize > 0:
+			return "VisString64"
+		case spec.VisibleStringSize == 65:
+			return "VisString65"
+		case spec.VisibleStringSize <= 129 && spec.VisibleStringSize > 0:
+			return "VisString129"
+		default:
+			return "VisString255"
+		}
+	case String:
+		return "Unicode255"
+	case UTCTime:
+		return "Timestamp"
+	case BinaryTime:
+		return "EntryTime"
+	default:
+		return "Struct"
+	}
+}
+
+func (v *MmsValue) valueElements() ([]*MmsValue, bool) {
+	if v == nil {
+		return nil, false
+	}
+	values, ok := v.Value.([]*MmsValue)
+	return values, ok
+}
+
+// inferCDC guesses the common data class of a data object from the names and types of its children.
+func inferCDC(node *dataNode) string {
+	das := make(map[string]*MmsVariableSpec)
+	sdos := make(map[string]bool)
+	for _, child := range node.children {
+		if child.isDO {
+			sdos[child.name] = true
+		} else if child.spec != nil {
+			das[child.name] = child.spec
+		}
+	}
+
+	typeOf := func(name string) MmsType {
+		if spec, ok := das[name]; ok {
+			return spec.Type
+		}
+		return -1
+	}
+
+	switch {
+	case sdos["phsA"] && sdos["phsB"] && sdos["phsC"]:
+		return "WYE"
+	case sdos["phsAB"]:
+		return "DEL"
+	case sdos["c1"] && sdos["c2"]:
+		return "SEQ"
+	case das["ctlVal"] != nil:
+		switch {
+		case typeOf("stVal") == BitString:
+			return "DPC"
+		case typeOf("ctlVal") == BitString && das["valWTr"] != nil:
+			return "BSC"
+		case typeOf("ctlVal") == Integer && das["valWTr"] != nil:
+			return "ISC"
+		case typeOf("ctlVal") == Integer:
+			return "INC"
+		case typeOf("ctlVal") == Structure || das["mxVal"] != nil:
+			return "APC"
+		default:
+			return "SPC"
+		}
+	case das["mxVal"] != nil:
+		return "APC"
+	case das["stVal"] != nil:
+		switch typeOf("stVal") {
+		case Boolean:
+			return "SPS"
+		case BitString:
+			return "DPS"
+		case VisibleString, String:
+			return "VSS"
+		default:
+			return "INS"
+		}
+	case das["actVal"] != nil:
+		return "BCR"
+	case das["cVal"] != nil:
+		return "CMV"
+	case das["mag"] != nil:
+		return "MV"
+	case das["instMag"] != nil:
+		return "SAV"
+	case das["setMag"] != nil:
+		return "ASG"
+	case das["setCharact"] != nil:
+		return "CURVE"
+	case das["setVal"] != nil:
+		switch typeOf("setVal") {
+		case Boolean:
+			return "SPG"
+		case VisibleString, String:
+			return "VSG"
+		default:
+			return "ING"
+		}
+	case das["vendor"] != nil && das["swRev"] != nil:
+		return "LPL"
+	case das["vendor"] != nil:
+		return "DPL"
+	default:
+		return ""
+	}
+}
+
+// readControlBlock reads a control block and returns its attributes keyed by their dotted name.
+func (c *Client) readControlBlock(ref string, fc FC) (controlBlock, error) {
+	spec, err := c.GetVariableSpecification(ref, fc)
+	if err != nil {
+		return nil, fmt.Errorf("%s[%s]: %w", ref, fc, err)
+	}
+	value, err := c.ReadObject(ref, fc)
+	if err != nil {
+		return nil, fmt.Errorf("%s[%s]: %w", ref, fc, err)
+	}
+	attributes := make(controlBlock)
+	var flatten func(prefix string, spec *MmsVariableSpec, value *MmsValue)
+	flatten = func(prefix string, spec *MmsVariableSpec, value *MmsValue) {
+		values, ok := value.valueElements()
+		if spec.Structure == nil || !ok {
+			return
+		}
+		for i := range spec.Structure.Elements {
+			if i >= len(values) {
+				return
+			}
+			name := prefix + spec.Structure.Elements[i].Name
+			attributes[name] = values[i]
+			flatten(name+".", &spec.Structure.Elements[i], values[i])
+		}
+	}
+	flatten("", spec, value)
+	return attributes, nil
+}
+
+// readControlBlockOrEmpty reads a control block like readControlBlock, a failed read is logged and
+// returns a control block without attributes, as servers may restrict reading control blocks.
+func (c *Client) readControlBlockOrEmpty(ref string, fc FC) controlBlock {
+	cb, err := c.readControlBlock(ref, fc)
+	if err != nil {
+		c.logger().Warn("reading control block failed", "ref", ref, "fc", fc, "error", err)
+		return controlBlock{}
+	}
+	return cb
+}
+
+// controlBlock holds the attributes of a control block keyed by their dotted name.
+type controlBlock map[string]*MmsValue
+
+func (cb controlBlock) getString(name string) string {
+	if v, ok := cb[name]; ok {
+		if s, ok := v.Value.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+func (cb controlBlock) getUint(name string) int64 {
+	if v, ok := cb[name]; ok {
+		switch n := v.Value.(type) {
+		case uint32:
+			return int64(n)
+		case int64:
+			return n
+		}
+	}
+	return 0
+}
+
+func (cb controlBlock) getBool(name string) bool {
+	if v, ok := cb[name]; ok {
+		if b, ok := v.Value.(bool); ok {
+			return b
+		}
+	}
+	return false
+}
+
+func (cb controlBlock) getBytes(name string) []byte {
+	if v, ok := cb[name]; ok {
+		if b, ok := v.Value.([]byte); ok {
+			return b
+		}
+	}
+	return nil
+}
+
+// dataObject converts a data object read by readDataObject.
+func (n *dataNode) dataObject(name, ref string) DO {
+	do := DO{Data: name, Ref: ref, CDC: inferCDC(n)}
+	for _, child := range n.children {
+		childRef := ref + "." + child.name
+		if child.isDO {
+			do.SDOs = append(do.SDOs, child.dataObject(child.name, childRef))
+		} else if child.spec != nil {
+			do.DAs = append(do.DAs, newDA(child.name, childRef, child.fc, child.spec))
+		}
+	}
+	return do
+}
+
+func newDA(name, ref string, fc FC, spec *MmsVariableSpec) DA {
+	da := DA{Data: name, Ref: ref, FC: fc, Type: spec.Type}
+	if spec.Type == Array && spec.Array != nil {
+		da.Count = spec.Array.ElementCount
+		spec = spec.Array.Element
+		da.Type = spec.Type
+	}
+	da.BType = basicType(name, spec)
+	if spec.Structure != nil {
+		for i := range spec.Structure.Elements {
+			element := &spec.Structure.Elements[i]
+			da.DAs = append(da.DAs, newDA(element.Name, ref+"."+element.Name, fc, element))
+		}
+	}
+	return da
+}
+
+// newDSRef splits a data set member like "LD/LN.DO.DA[FC]" into reference and FC.
+func newDSRef(member string) DSRef {
+	ref := DSRef{Data: member, Ref: member, FC: NONE}
+	if i := strings.LastIndex(member, "["); i != -1 && strings.HasSuffix(member, "]") {
+		ref.Ref = member[:i]
+		ref.FC = FunctionalConstraintFromString(member[i+1 : len(member)-1])
+	}
+	return ref
+}
+
+func (cb controlBlock) reportAttributes() ReportAttributes {
+	return ReportAttributes{
+		RptID:   cb.getString("RptID"),
+		DatSet:  cb.getString("DatSet"),
+		ConfRev: uint32(cb.getUint("ConfRev")),
+		RptEna:  cb.getBool("RptEna"),
+		BufTm:   uint32(cb.getUint("BufTm")),
+		IntgPd:  uint32(cb.getUint("IntgPd")),
+		TrgOps:  trgOpsFromBits(int(cb.getUint("TrgOps")) >> 1),
+		OptFlds: optFldsFromBits(int(cb.getUint("OptFlds")) >> 1),
+	}
+}
+
+func (cb controlBlock) phyComAddress() PhyComAddress {
+	address := PhyComAddress{
+		VlanPriority: uint8(cb.getUint("DstAddress.PRIORITY")),
+		VlanId:       uint16(cb.getUint("DstAddress.VID")),
+		AppId:        uint16(cb.getUint("DstAddress.APPID")),
+	}
+	copy(address.DstAddress[:], cb.getBytes("DstAddress.Addr"))
+	return address
+}
+
+func (cb controlBlock) svOptFlds() SVOptFlds {
+	optFlds := int(cb.getUint("OptFlds"))
+	return SVOptFlds{
+		RefreshTime:        IsBitSet(optFlds, 0),
+		SampleSynchronized: IsBitSet(optFlds, 1),
+		SampleRate:         IsBitSet(optFlds, 2),
+		DataSet:            IsBitSet(optFlds, 3),
+		Security:           IsBitSet(optFlds, 4),
+	}
+}
@@ -4,7 +4,6 @@ package iec61850
 import "C"
 import (
 	"fmt"
-	"log"
 	"strings"
 	"sync"
 	"unsafe"
@@ -12,13 +11,13 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-// GetVariableValues is an example of how to read variable structure and values from an IED
+// GetVariableValues reads the structure and values of all variables of the server.
+// Variables that cannot be read are returned with the error as value.
 func (c *Client) GetVariableValues() ([]VariableTypeValue, error) {
-	log.Printf("Loading data model")
+	c.logger().Debug("loading data model")
 	if err := c.GetDeviceModelFromServer(); err != nil {
 		return nil, err
 	}
-	log.Printf("Done")
 
 	// Use Go wrapper to fetch logical device names
 	ldNames, err := c.GetLogicalDeviceList()
@@ -49,27 +48,30 @@ func (c *Client) GetVariableValues() ([]VariableTypeValue, error) {
 	totCount := 0
 
 	for _, ldName := range ldNames {
-		fmt.Printf("Reading variables for LD %s\n", ldName)
+		c.logger().Debug("reading variables", "ld", ldName)
 		variables, err := c.GetLogicalDeviceVariablesHierarchical(ldName)
 		if err != nil {
 			return nil, err
 		}
-		totCount += len(variables)
+		for _, v := range variables {
+			totCount += len(v.FCVars)
+		}
 		q = append(q, qvars{ldName, variables})
 	}
-	progress := 0
+
+	progress := c.newProgress("GetVariableValues", totCount)
 	for _, qv := range q {
 		for _, v := range qv.vars {
-			progress++
-			fmt.Printf("\rReading object values: %d/%d - %s/%s", progress, totCount, qv.ld, v.LN)
-
 			ldName := qv.ld
-			for fc := range v.FCVars {
+			for fcName := range v.FCVars {
 				eg.Go(func() error {
 					dataRef := fmt.Sprintf("%s/%s", ldName, v.LN)
+					fc := FunctionalConstraintFromString(fcName)
+					defer progress.step(dataRef)
 
-					values, err := c.GetVariableTypeValues(dataRef, FunctionalConstraintFromString(fc))
+					values, err := c.GetVariableTypeValues(dataRef, fc)
 					if err != nil {
+						c.logger().Warn("reading object values failed", "ref", dataRef, "fc", fc, "error", err)
 						ch <- []VariableTypeValue{{
 							Type:  0,
 							Name:  v.LN,
@@ -79,13 +81,11 @@ func (c *Client) GetVariableValues() ([]VariableTypeValue, error) {
 						return nil
 					}
 
-					//fmt.Printf("  FC: %s - %s - [%s]\n    Value: %s\n", fc, dataRef, strings.Join(subVars, ", "), object)
 					ch <- values
 					return nil
 				})
 			}
 		}
-		fmt.Printf("\n")
 	}
 
 	err = eg.Wait()
@@ -95,113 +95,215 @@ func (c *Client) GetVariableValues() ([]VariableTypeValue, error) {
 	return ret, err
 }
 
+// DataModelOptions controls how GetDataModelWithOptions walks the server.
+type DataModelOptions struct {
+	// Concurrency is the number of logical nodes read in parallel, 4 when zero or negative.
+	Concurrency int
+}
+
+// GetDataModel reads the complete data model of the server. See GetDataModelWithOptions.
 func (c *Client) GetDataModel() (DataModel, error) {
+	return c.GetDataModelWithOptions(DataModelOptions{})
+}
+
+// GetDataModelWithOptions reads the complete data model of the server: data objects with the MMS and
+// basic type of their attributes and an inferred CDC, data sets with their members, report, log, GOOSE,
+// sampled value and setting group control blocks with their current attributes, and logs.
+// The logical nodes are read by a bounded pool of workers, the result keeps the order of the server.
+func (c *Client) GetDataModelWithOptions(opts DataModelOptions) (DataModel, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
 	if err := c.GetDeviceModelFromServer(); err != nil {
-		return DataModel{}, err
+		return DataModel{}, fmt.Errorf("GetDataModel: %w", err)
 	}
 
-	// Use Go wrapper to fetch logical device names
 	ldNames, err := c.GetLogicalDeviceList()
 	if err != nil {
-		return DataModel{}, err
+		return DataModel{}, fmt.Errorf("GetDataModel: %w", err)
 	}
 
 	var dataModel DataModel
-	for _, name := range ldNames {
-		var ld LD
-		ld.Data = name
+	for _, ldName := range ldNames {
+		lnNames, err := c.GetLogicalDeviceDirectory(ldName)
+		if err != nil {
+			return DataModel{}, fmt.Errorf("GetDataModel %q: %w", ldName, err)
+		}
+		ld := LD{Data: ldName, LNs: make([]LN, len(lnNames))}
+		for i, lnName := range lnNames {
+			ld.LNs[i] = LN{Data: lnName, Ref: fmt.Sprintf("%s/%s", ldName, lnName)}
+		}
 		dataModel.LDs = append(dataModel.LDs, ld)
 	}
 
-	for i, ld := range dataModel.LDs {
-		logicalNodes, err := c.GetLogicalDeviceDirectory(ld.Data)
-		if err != nil {
-			return DataModel{}, err
-		}
+	total := 0
+	for _, ld := range dataModel.LDs {
+		total += len(ld.LNs)
+	}
+	progress := c.newProgress("GetDataModel", total)
 
-		for _, lnName := range logicalNodes {
-			var ln LN
-			ln.Data = lnName
-			lnRef := fmt.Sprintf("%s/%s", ld.Data, lnName)
-			ln.Ref = lnRef
-			ld.LNs = append(ld.LNs, ln)
+	eg := errgroup.Group{}
+	eg.SetLimit(opts.Concurrency)
+	for i := range dataModel.LDs {
+		for j := range dataModel.LDs[i].LNs {
+			ln := &dataModel.LDs[i].LNs[j]
+			eg.Go(func() error {
+				if err := c.readLogicalNode(ln); err != nil {
+					return fmt.Errorf("GetDataModel %q: %w", ln.Ref, err)
+				}
+				progress.step(ln.Ref)
+				return nil
+			})
 		}
+	}
+	if err := eg.Wait(); err != nil {
+		return DataModel{}, err
+	}
+	return dataModel, nil
+}
 
-		for j, ln := range ld.LNs {
-			lnRef := ln.Ref
+// readLogicalNode fills the data objects, data sets, control blocks and logs of ln.
+// Control blocks the server refuses to read are listed with their name and reference only.
+func (c *Client) readLogicalNode(ln *LN) error {
+	dataObjects, err := c.GetLogicalNodeDirectory(ln.Ref, ACSI_CLASS_DATA_OBJECT)
+	if err != nil {
+		return err
+	}
+	for _, doName := range dataObjects {
+		doRef := fmt.Sprintf("%s.%s", ln.Ref, doName)
+		node, err := c.readDataObject(doRef, false)
+		if err != nil {
+			return err
+		}
+		ln.DOs = append(ln.DOs, node.dataObject(doName, doRef))
+	}
 
-			dataObjects, err := c.GetLogicalNodeDirectory(lnRef, ACSI_CLASS_DATA_OBJECT)
-			if err != nil {
-				return DataModel{}, err
-			}
+	dataSets, err := c.GetLogicalNodeDirectory(ln.Ref, ACSI_CLASS_DATA_SET)
+	if err != nil {
+		return err
+	}
+	for _, dsName := range dataSets {
+		dataSetMembers, isDeletable, err := c.GetDataSetDirectory(fmt.Sprintf("%s.%s", ln.Ref, dsName))
+		if err != nil {
+			return err
+		}
+		ds := DS{Data: dsName, IsDeletable: isDeletable}
+		for _, member := range dataSetMembers {
+			ds.DSRefs = append(ds.DSRefs, newDSRef(member))
+		}
+		ln.DSs = append(ln.DSs, ds)
+	}
 
-			for _, doName := range dataObjects {
-				var do DO
-				do.Data = doName
-				ln.DOs = append(ln.DOs, do)
-			}
+	reports, err := c.GetLogicalNodeDirectory(ln.Ref, ACSI_CLASS_URCB)
+	if err != nil {
+		return err
+	}
+	for _, name := range reports {
+		ref := fmt.Sprintf("%s.%s", ln.Ref, name)
+		cb := c.readControlBlockOrEmpty(ref, RP)
+		ln.URReports = append(ln.URReports, URReport{Data: name, Ref: ref, ReportAttributes: cb.reportAttributes()})
+	}
 
-			for k, do := range ln.DOs {
-				doRef := fmt.Sprintf("%s/%s.%s", ld.Data, ln.Data, do.Data)
+	reports, err = c.GetLogicalNodeDirectory(ln.Ref, ACSI_CLASS_BRCB)
+	if err != nil {
+		return err
+	}
+	for _, name := range reports {
+		ref := fmt.Sprintf("%s.%s", ln.Ref, name)
+		cb := c.readControlBlockOrEmpty(ref, BR)
+		ln.BRReports = append(ln.BRReports, BRReport{Data: name, Ref: ref, ReportAttributes: cb.reportAttributes()})
+	}
 
-				ln.DOs[k].DAs, err = c.GetDAs(doRef)
-				if err != nil {
-					return DataModel{}, err
-				}
-			}
+	lcbs, err := c.GetLogicalNodeDirectory(ln.Ref, ACSI_CLASS_LCB)
+	if err != nil {
+		return err
+	}
+	for _, name := range lcbs {
+		ref := fmt.Sprintf("%s.%s", ln.Ref, name)
+		cb := c.readControlBlockOrEmpty(ref, LG)
+		ln.LCBs = append(ln.LCBs, LCB{
+			Data:   name,
+			Ref:    ref,
+			DatSet: cb.getString("DatSet"),
+			LogRef: cb.getString("LogRef"),
+			LogEna: cb.getBool("LogEna"),
+			IntgPd: uint32(cb.getUint("IntgPd")),
+			TrgOps: trgOpsFromBits(int(cb.getUint("TrgOps")) >> 1),
+		})
+	}
 
-			// Use wrapper to get logical node directory for DATA_SET
-			dataSets, err := c.GetLogicalNodeDirectory(lnRef, ACSI_CLASS_DATA_SET)
-			if err != nil {
-				return DataModel{}, err
-			}
-			for _, dsName := range dataSets {
-				var ds DS
-				ds.Data = dsName
-				dataSetRef := fmt.Sprintf("%s.%s", lnRef, ds.Data)
-				// Use wrapper to get dataset members and deletable flag
-				dataSetMembers, isDeletable, err := c.GetDataSetDirectory(dataSetRef)
-				if err != nil {
-					return DataModel{}, err
-				}
-				ds.IsDeletable = isDeletable
-				for _, member := range dataSetMembers {
-					var dsRef DSRef
-					dsRef.Data = member
-					ds.DSRefs = append(ds.DSRefs, dsRef)
-				}
-				ln.DSs = append(ln.DSs, ds)
-			}
+	gocbs, err := c.GetLogicalNodeDirectory(ln.Ref, ACSI_CLASS_GoCB)
+	if err != nil {
+		return err
+	}
+	for _, name := range gocbs {
+		ref := fmt.Sprintf("%s.%s", ln.Ref, name)
+		cb := c.readControlBlockOrEmpty(ref, GO)
+		ln.GoCBs = append(ln.GoCBs, GoCB{
+			Data:       name,
+			Ref:        ref,
+			GoID:       cb.getString("GoID"),
+			DatSet:     cb.getString("DatSet"),
+			ConfRev:    uint32(cb.getUint("ConfRev")),
+			GoEna:      cb.getBool("GoEna"),
+			NdsCom:     cb.getBool("NdsCom"),
+			FixedOffs:  cb.getBool("FixedOffs"),
+			MinTime:    uint32(cb.getUint("MinTime")),
+			MaxTime:    uint32(cb.getUint("MaxTime")),
+			DstAddress: cb.phyComAddress(),
+		})
+	}
 
-			// Use wrapper for URCB directory
-			reports, err := c.GetLogicalNodeDirectory(lnRef, ACSI_CLASS_URCB)
-			if err != nil {
-				return DataModel{}, err
-			}
-			for _, name := range reports {
-				var r URReport
-				r.Data = name
-				r.Ref = fmt.Sprintf("%s.%s", lnRef, r.Data)
-				ln.URReports = append(ln.URReports, r)
-			}
+	for _, class := range []ACSIClass{ACSI_CLASS_MSVCB, ACSI_CLASS_USVCB} {
+		svcbs, err := c.GetLogicalNodeDirectory(ln.Ref, class)
+		if err != nil {
+			return err
+		}
+		fc, idName := MS, "MsvID"
+		if class == ACSI_CLASS_USVCB {
+			fc, idName = US, "UsvID"
+		}
+		for _, name := range svcbs {
+			ref := fmt.Sprintf("%s.%s", ln.Ref, name)
+			cb := c.readControlBlockOrEmpty(ref, fc)
+			ln.SVCBs = append(ln.SVCBs, SVCB{
+				Data:       name,
+				Ref:        ref,
+				Multicast:  fc == MS,
+				SvID:       cb.getString(idName),
+				DatSet:     cb.getString("DatSet"),
+				ConfRev:    uint32(cb.getUint("ConfRev")),
+				SvEna:      cb.getBool("SvEna"),
+				SmpRate:    uint32(cb.getUint("SmpRate")),
+				SmpMod:     uint32(cb.getUint("SmpMod")),
+				NoASDU:     uint32(cb.getUint("noASDU")),
+				OptFlds:    cb.svOptFlds(),
+				DstAddress: cb.phyComAddress(),
+			})
+		}
+	}
 
-			// Use wrapper for BRCB directory
-			reports, err = c.GetLogicalNodeDirectory(lnRef, ACSI_CLASS_BRCB)
-			if err != nil {
-				return DataModel{}, err
-			}
-			for _, name := range reports {
-				var r BRReport
-				r.Data = name
-				r.Ref = fmt.Sprintf("%s.%s", lnRef, r.Data)
-				ln.BRReports = append(ln.BRReports, r)
-			}
+	sgcbs, err := c.GetLogicalNodeDirectory(ln.Ref, ACSI_CLASS_SGCB)
+	if err != nil {
+		return err
+	}
+	for _, name := range sgcbs {
+		ref := fmt.Sprintf("%s.%s", ln.Ref, name)
+		cb := c.readControlBlockOrEmpty(ref, SP)
+		ln.SGCB = &SGCB{Data: name, Ref: ref, SettingGroup: SettingGroup{
+			NumOfSG: int(cb.getUint("NumOfSG")),
+			ActSG:   int(cb.getUint("ActSG")),
+			EditSG:  int(cb.getUint("EditSG")),
+			CnfEdit: cb.getBool("CnfEdit"),
+		}}
+	}
 
-			ld.LNs[j] = ln
-		}
-		dataModel.LDs[i] = ld
+	// servers without log service reject the LOG directory request
+	logs, _ := c.GetLogicalNodeDirectory(ln.Ref, ACSI_CLASS_LOG)
+	for _, name := range logs {
+		ln.Logs = append(ln.Logs, Log{Data: name, Ref: fmt.Sprintf("%s.%s", ln.Ref, name)})
 	}
-	return dataModel, nil
+	return nil
 }
 
 func (c *Client) GetDAs(doRef string) ([]DA, error) {
@@ -95,7 +95,16 @@ func (c *Client) getRCBResv(rcb C.ClientReportControlBlock) bool {
 
 func (c *Client) getOptFlds(rcb C.ClientReportControlBlock) OptFlds {
 	optFlds := C.ClientReportControlBlock_getOptFlds(rcb)
-	g := int(optFlds)
+	return optFldsFromBits(int(optFlds))
+}
+
+func (c *Client) getTrgOps(rcb C.ClientReportControlBlock) TrgOps {
+	trgOps := C.ClientReportControlBlock_getTrgOps(rcb)
+	return trgOpsFromBits(int(trgOps))
+}
+
+// optFldsFromBits converts OptFlds without the reserved first bit of the MMS bit string.
+func optFldsFromBits(g int) OptFlds {
 	return OptFlds{
 		SequenceNumber:     IsBitSet(g, 0),
 		TimeOfEntry:        IsBitSet(g, 1),
@@ -108,9 +117,8 @@ func (c *Client) getOptFlds(rcb C.ClientReportControlBlock) OptFlds {
 	}
 }
 
-func (c *Client) getTrgOps(rcb C.ClientReportControlBlock) TrgOps {
-	trgOps := C.ClientReportControlBlock_getTrgOps(rcb)
-	g := int(trgOps)
+// trgOpsFromBits converts TrgOps without the reserved first bit of the MMS bit string.
+func trgOpsFromBits(g int) TrgOps {
 	return TrgOps{
 		DataChange:            IsBitSet(g, 0),
 		QualityChange:         IsBitSet(g, 1),
@@ -0,0 +1,943 @@
+package iec61850
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SCLExportOptions controls how ExportSCL names the exported IED.
+type SCLExportOptions struct {
+	// IEDName is the name of the IED. When empty it is derived from the common prefix of the
+	// logical device names, or "TEMPLATE" if the server has a single logical device.
+	IEDName string
+	// APName is the name of the access point, "AP1" when empty.
+	APName string
+}
+
+// ExportSCL walks the connected server and writes its data model as an IID/ICD file to w.
+// See ExportSCLWithOptions.
+func (c *Client) ExportSCL(w io.Writer) error {
+	return c.ExportSCLWithOptions(w, SCLExportOptions{})
+}
+
+// ExportSCLWithOptions walks the connected server and writes its data model as an IID/ICD file to w.
+//
+// The file contains the LDevice/LN instances with the current values of the readable attributes
+// as DOI/DAI, data sets, report, log, GOOSE, sampled value and setting group control blocks,
+// and LNodeType/DOType/DAType templates synthesized from the MMS variable specifications.
+// MMS does not transport enumeration definitions or CDC names, so enumerations are exported
+// as INT8 and the CDC of a data object type is inferred from its attributes.
+// Dynamically created (deletable) data sets are not exported.
+func (c *Client) ExportSCLWithOptions(w io.Writer, opts SCLExportOptions) error {
+	if err := c.GetDeviceModelFromServer(); err != nil {
+		return fmt.Errorf("ExportSCL: %w", err)
+	}
+	ldNames, err := c.GetLogicalDeviceList()
+	if err != nil {
+		return fmt.Errorf("ExportSCL: %w", err)
+	}
+
+	if opts.IEDName == "" {
+		opts.IEDName = sclIEDName(ldNames)
+	}
+	if opts.APName == "" {
+		opts.APName = "AP1"
+	}
+
+	e := &sclExporter{
+		c:       c,
+		iedName: opts.IEDName,
+		typeIds: make(map[string]string),
+		usedIds: make(map[string]bool),
+	}
+
+	doc := &sclDoc{
+		Xmlns:    "http://www.iec.ch/61850/2003/SCL",
+		Version:  "2007",
+		Revision: "B",
+		Header:   sclHeader{Id: opts.IEDName, NameStructure: "IEDName"},
+		IED: sclIED{
+			Name:          opts.IEDName,
+			ConfigVersion: "1.0",
+			AccessPoint: sclAccessPoint{
+				Name:   opts.APName,
+				Server: sclServer{Authentication: sclAuthentication{None: true}},
+			},
+		},
+	}
+
+	connectedAP := &sclConnectedAP{
+		IedName: opts.IEDName,
+		APName:  opts.APName,
+		Address: &sclAddress{P: []sclP{{Type: "IP", Value: c.settings.Host}}},
+	}
+
+	for _, ldName := range ldNames {
+		ld, err := e.exportLD(ldName, connectedAP)
+		if err != nil {
+			return fmt.Errorf("ExportSCL %q: %w", ldName, err)
+		}
+		doc.IED.AccessPoint.Server.LDevices = append(doc.IED.AccessPoint.Server.LDevices, ld)
+	}
+
+	doc.Communication = &sclCommunication{
+		SubNetwork: sclSubNetwork{Name: "SubNetwork1", Type: "8-MMS", ConnectedAP: connectedAP},
+	}
+	doc.DataTypeTemplates = e.templates
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("ExportSCL: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("ExportSCL: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("ExportSCL: %w", err)
+	}
+	return nil
+}
+
+// sclIEDName derives the IED name from the common prefix of the logical device names,
+// leaving a non-empty instance name for every logical device.
+func sclIEDName(ldNames []string) string {
+	if len(ldNames) < 2 {
+		return "TEMPLATE"
+	}
+	prefix := ldNames[0]
+	for _, name := range ldNames {
+		for !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if prefix == "" {
+		return "TEMPLATE"
+	}
+	return prefix
+}
+
+type sclExporter struct {
+	c         *Client
+	iedName   string
+	templates sclTemplates
+	// typeIds maps the signature of a synthesized type to its id
+	typeIds map[string]string
+	usedIds map[string]bool
+}
+
+// ldInst returns the SCL instance name of a logical device.
+func (e *sclExporter) ldInst(ldName string) string {
+	if inst := strings.TrimPrefix(ldName, e.iedName); inst != "" && inst != ldName {
+		return inst
+	}
+	return ldName
+}
+
+// typeId returns the id of the type with the given signature, registering it with add if it is new.
+func (e *sclExporter) typeId(base, signature string, add func(id string)) string {
+	if id, ok := e.typeIds[signature]; ok {
+		return id
+	}
+	id := base
+	for n := 2; e.usedIds[id]; n++ {
+		id = fmt.Sprintf("%s_%d", base, n)
+	}
+	e.typeIds[signature] = id
+	e.usedIds[id] = true
+	add(id)
+	return id
+}
+
+func (e *sclExporter) exportLD(ldName string, connectedAP *sclConnectedAP) (*sclLDevice, error) {
+	ld := &sclLDevice{Inst: e.ldInst(ldName)}
+
+	lnNames, err := e.c.GetLogicalDeviceDirectory(ldName)
+	if err != nil {
+		return nil, err
+	}
+	for _, lnName := range lnNames {
+		ln, err := e.exportLN(ldName, lnName, connectedAP)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", lnName, err)
+		}
+		if ln.LnClass == "LLN0" {
+			ln.XMLName = xml.Name{Local: "LN0"}
+			ld.LN0 = ln
+		} else {
+			ln.XMLName = xml.Name{Local: "LN"}
+			ld.LNs = append(ld.LNs, ln)
+		}
+	}
+	if ld.LN0 == nil {
+		return nil, fmt.Errorf("missing LLN0")
+	}
+	return ld, nil
+}
+
+func (e *sclExporter) exportLN(ldName, lnName string, connectedAP *sclConnectedAP) (*sclLN, error) {
+	lnRef := ldName + "/" + lnName
+	prefix, lnClass, inst := splitLNName(lnName)
+	ln := &sclLN{Prefix: prefix, LnClass: lnClass, Inst: inst}
+
+	doNames, err := e.c.GetLogicalNodeDirectory(lnRef, ACSI_CLASS_DATA_OBJECT)
+	if err != nil {
+		return nil, err
+	}
+	lnType := &sclLNodeType{LnClass: lnClass}
+	signature := "LN:" + lnClass
+	for _, doName := range doNames {
+		node, err := e.c.readDataObject(lnRef+"."+doName, true)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", doName, err)
+		}
+		node.name = doName
+		doType := e.doTypeId(node)
+		lnType.DOs = append(lnType.DOs, sclDODef{Name: doName, Type: doType})
+		signature += ";" + doName + ":" + doType
+		if doi := node.instance("DOI"); doi != nil {
+			ln.DOIs = append(ln.DOIs, doi)
+		}
+	}
+	ln.LnType = e.typeId(lnClass, signature, func(id string) {
+		lnType.Id = id
+		e.templates.LNodeTypes = append(e.templates.LNodeTypes, lnType)
+	})
+
+	if err := e.exportDataSets(ln, lnRef); err != nil {
+		return nil, err
+	}
+	if err := e.exportReportControls(ln, lnRef); err != nil {
+		return nil, err
+	}
+	if err := e.exportLogControls(ln, ldName, lnRef); err != nil {
+		return nil, err
+	}
+	// the log directory is always requested from the server, which fails on servers without log service
+	logs, _ := e.c.GetLogicalNodeDirectory(lnRef, ACSI_CLASS_LOG)
+	for _, name := range logs {
+		ln.Logs = append(ln.Logs, sclLog{Name: name})
+	}
+	if err := e.exportGSEControls(ln, ldName, lnRef, connectedAP); err != nil {
+		return nil, err
+	}
+	if err := e.exportSMVControls(ln, ldName, lnRef, connectedAP); err != nil {
+		return nil, err
+	}
+	if lnClass == "LLN0" {
+		if err := e.exportSettingControl(ln, lnRef); err != nil {
+			return nil, err
+		}
+	}
+	return ln, nil
+}
+
+// splitLNName splits a logical node name like "MMXU1" or "Q0XCBR1" into prefix, class and instance.
+func splitLNName(name string) (prefix, lnClass, inst string) {
+	if name == "LLN0" {
+		return "", "LLN0", ""
+	}
+	end := len(name)
+	for end > 0 && name[end-1] >= '0' && name[end-1] <= '9' {
+		end--
+	}
+	start := end - 4
+	if start < 0 {
+		start = 0
+	}
+	return name[:start], name[start:end], name[end:]
+}
+
+// doTypeId returns the id of the DOType describing node.
+func (e *sclExporter) doTypeId(node *dataNode) string {
+	doType := &sclDOType{Cdc: inferCDC(node)}
+	signature := "DO:" + doType.Cdc
+	for _, child := range node.children {
+		if child.isDO {
+			sdo := &sclTypeElement{XMLName: xml.Name{Local: "SDO"}, Name: child.name, Type: e.doTypeId(child)}
+			doType.Elements = append(doType.Elements, sdo)
+			signature += ";SDO " + child.name + ":" + sdo.Type
+			continue
+		}
+		if child.spec == nil {
+			continue
+		}
+		da := e.typeElement("DA", child.name, child.spec)
+		da.Fc = child.fc.String()
+		if child.fc == ST || child.fc == MX {
+			switch child.name {
+			case "q":
+				da.Qchg = true
+			case "t":
+			default:
+				da.Dchg = true
+			}
+		}
+		doType.Elements = append(doType.Elements, da)
+		signature += ";" + da.signature()
+	}
+
+	base := doType.Cdc
+	if base == "" {
+		base = "DO_" + node.name
+	}
+	return e.typeId(base, signature, func(id string) {
+		doType.Id = id
+		e.templates.DOTypes = append(e.templates.DOTypes, doType)
+	})
+}
+
+// typeElement creates a DA or BDA definition, synthesizing a DAType for constructed attributes.
+func (e *sclExporter) typeElement(element, name string, spec *MmsVariableSpec) *sclTypeElement {
+	el := &sclTypeElement{XMLName: xml.Name{Local: element}, Name: name}
+	if spec.Type == Array && spec.Array != nil && spec.Array.Element != nil {
+		el.Count = spec.Array.ElementCount
+		spec = spec.Array.Element
+	}
+	el.BType = basicType(name, spec)
+	if spec.Type == Structure && spec.Structure != nil {
+		el.Type = e.daTypeId(name, spec)
+	}
+	return el
+}
+
+func (e *sclExporter) daTypeId(name string, spec *MmsVariableSpec) string {
+	daType := &sclDAType{}
+	signature := "DA"
+	for i := range spec.Structure.Elements {
+		element := &spec.Structure.Elements[i]
+		bda := e.typeElement("BDA", element.Name, element)
+		daType.BDAs = append(daType.BDAs, bda)
+		signature += ";" + bda.signature()
+	}
+	return e.typeId("DA_"+name, signature, func(id string) {
+		daType.Id = id
+		e.templates.DATypes = append(e.templates.DATypes, daType)
+	})
+}
+
+// sclValue formats a value the way the SCL parser reads it back for the given basic type.
+func sclValue(bType string, value *MmsValue) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+	switch v := value.Value.(type) {
+	case bool:
+		if bType == "BOOLEAN" {
+			return strconv.FormatBool(v), true
+		}
+	case int64:
+		if strings.HasPrefix(bType, "INT") {
+			return strconv.FormatInt(v, 10), true
+		}
+	case uint32:
+		if strings.HasPrefix(bType, "INT") {
+			return strconv.FormatUint(uint64(v), 10), true
+		}
+	case float32:
+		if strings.HasPrefix(bType, "FLOAT") {
+			return strconv.FormatFloat(float64(v), 'g', -1, 32), true
+		}
+	case string:
+		if strings.HasPrefix(bType, "VisString") || bType == "Unicode255" {
+			return v, true
+		}
+	case []byte:
+		if bType == "Octet64" {
+			return base64.StdEncoding.EncodeToString(v), true
+		}
+	}
+	return "", false
+}
+
+// instance returns the DOI/SDI/DAI element holding the values of node, or nil if it has none.
+func (n *dataNode) instance(element string) *sclInstance {
+	inst := &sclInstance{XMLName: xml.Name{Local: element}, Name: n.name}
+	if !n.isDO && n.spec != nil && n.spec.Type != Structure {
+		if n.spec.Type == Array {
+			return nil
+		}
+		val, ok := sclValue(basicType(n.name, n.spec), n.value)
+		if !ok {
+			return nil
+		}
+		inst.Val = &sclVal{Value: val}
+		return inst
+	}
+
+	if !n.isDO && n.spec != nil {
+		// constructed attribute, its elements are not part of the merged tree
+		values, _ := n.value.valueElements()
+		for i := range n.spec.Structure.Elements {
+			element := &dataNode{name: n.spec.Structure.Elements[i].Name, spec: &n.spec.Structure.Elements[i]}
+			if i < len(values) {
+				element.value = values[i]
+			}
+			if child := element.instance(element.childElement()); child != nil {
+				inst.Children = append(inst.Children, child)
+			}
+		}
+	} else {
+		for _, child := range n.children {
+			if childInst := child.instance(child.childElement()); childInst != nil {
+				inst.Children = append(inst.Children, childInst)
+			}
+		}
+	}
+	if len(inst.Children) == 0 {
+		return nil
+	}
+	return inst
+}
+
+func (n *dataNode) childElement() string {
+	if n.isDO || (n.spec != nil && n.spec.Type == Structure) {
+		return "SDI"
+	}
+	return "DAI"
+}
+
+// dataSetName returns the name of a data set referenced as "LD/LN$name" or "LD/LN.name".
+func dataSetName(ref string) string {
+	if i := strings.LastIndexAny(ref, "$."); i != -1 {
+		return ref[i+1:]
+	}
+	return ref
+}
+
+func (e *sclExporter) exportDataSets(ln *sclLN, lnRef string) error {
+	names, err := e.c.GetLogicalNodeDirectory(lnRef, ACSI_CLASS_DATA_SET)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		members, isDeletable, err := e.c.GetDataSetDirectory(lnRef + "." + name)
+		if err != nil {
+			return fmt.Errorf("data set %s: %w", name, err)
+		}
+		if isDeletable {
+			continue
+		}
+		ds := sclDataSet{Name: name}
+		for _, member := range members {
+			ds.FCDAs = append(ds.FCDAs, e.fcda(member))
+		}
+		ln.DataSets = append(ln.DataSets, ds)
+	}
+	return nil
+}
+
+// fcda converts a data set member reference like "LD/LN.DO.DA[FC]" into an FCDA.
+func (e *sclExporter) fcda(member string) sclFCDA {
+	var fcda sclFCDA
+	if i := strings.LastIndex(member, "["); i != -1 && strings.HasSuffix(member, "]") {
+		fcda.Fc = member[i+1 : len(member)-1]
+		member = member[:i]
+	}
+	ldName, rest, _ := strings.Cut(member, "/")
+	fcda.LdInst = e.ldInst(ldName)
+	parts := strings.Split(rest, ".")
+	fcda.Prefix, fcda.LnClass, fcda.LnInst = splitLNName(parts[0])
+
+	var doParts, daParts []string
+	for _, part := range parts[1:] {
+		if len(daParts) == 0 && isUpperName(part) {
+			doParts = append(doParts, part)
+		} else {
+			daParts = append(daParts, part)
+		}
+	}
+	fcda.DoName = strings.Join(doParts, ".")
+	fcda.DaName = strings.Join(daParts, ".")
+	return fcda
+}
+
+// rcbInstances groups indexed report control block instances like "EventsRCB01" by their base name.
+func rcbInstances(names []string) (bases []string, count map[string]int, indexed map[string]bool) {
+	count = make(map[string]int)
+	indexed = make(map[string]bool)
+	for _, name := range names {
+		base := name
+		isIndexed := false
+		if n := len(name); n > 2 && name[n-1] >= '0' && name[n-1] <= '9' && name[n-2] >= '0' && name[n-2] <= '9' {
+			base = name[:n-2]
+			isIndexed = true
+		}
+		if count[base] == 0 {
+			bases = append(bases, base)
+			indexed[base] = isIndexed
+		}
+		count[base]++
+	}
+	return bases, count, indexed
+}
+
+func toSCLTrgOps(bits int64) *sclTrgOps {
+	return &sclTrgOps{
+		Dchg:   IsBitSet(int(bits), 1),
+		Qchg:   IsBitSet(int(bits), 2),
+		Dupd:   IsBitSet(int(bits), 3),
+		Period: IsBitSet(int(bits), 4),
+		Gi:     IsBitSet(int(bits), 5),
+	}
+}
+
+func (e *sclExporter) exportReportControls(ln *sclLN, lnRef string) error {
+	for _, class := range []ACSIClass{ACSI_CLASS_URCB, ACSI_CLASS_BRCB} {
+		names, err := e.c.GetLogicalNodeDirectory(lnRef, class)
+		if err != nil {
+			return err
+		}
+		fc := RP
+		if class == ACSI_CLASS_BRCB {
+			fc = BR
+		}
+
+		bases, count, indexed := rcbInstances(names)
+		for _, base := range bases {
+			instance := base
+			if indexed[base] {
+				instance = base + "01"
+			}
+			cb, err := e.c.readControlBlock(lnRef+"."+instance, fc)
+			if err != nil {
+				return err
+			}
+			optFlds := int(cb.getUint("OptFlds"))
+			rc := sclReportControl{
+				Name:     base,
+				DatSet:   dataSetName(cb.getString("DatSet")),
+				IntgPd:   cb.getUint("IntgPd"),
+				RptID:    cb.getString("RptID"),
+				ConfRev:  cb.getUint("ConfRev"),
+				Buffered: fc == BR,
+				BufTime:  cb.getUint("BufTm"),
+				Indexed:  indexed[base],
+				TrgOps:   toSCLTrgOps(cb.getUint("TrgOps")),
+				OptFields: &sclOptFields{
+					SeqNum:     IsBitSet(optFlds, 1),
+					TimeStamp:  IsBitSet(optFlds, 2),
+					ReasonCode: IsBitSet(optFlds, 3),
+					DataSet:    IsBitSet(optFlds, 4),
+					DataRef:    IsBitSet(optFlds, 5),
+					BufOvfl:    IsBitSet(optFlds, 6),
+					EntryID:    IsBitSet(optFlds, 7),
+					ConfigRef:  IsBitSet(optFlds, 8),
+				},
+				RptEnabled: &sclRptEnabled{Max: count[base]},
+			}
+			ln.ReportControls = append(ln.ReportControls, rc)
+		}
+	}
+	return nil
+}
+
+func (e *sclExporter) exportLogControls(ln *sclLN, ldName, lnRef string) error {
+	names, err := e.c.GetLogicalNodeDirectory(lnRef, ACSI_CLASS_LCB)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		cb, err := e.c.readControlBlock(lnRef+"."+name, LG)
+		if err != nil {
+			return err
+		}
+		lc := sclLogControl{
+			Name:       name,
+			DatSet:     dataSetName(cb.getString("DatSet")),
+			IntgPd:     cb.getUint("IntgPd"),
+			LogEna:     cb.getBool("LogEna"),
+			ReasonCode: true,
+			TrgOps:     toSCLTrgOps(cb.getUint("TrgOps")),
+		}
+		if logRef := cb.getString("LogRef"); logRef != "" {
+			lc.LogName = dataSetName(logRef)
+			logLD, logLN, _ := strings.Cut(strings.TrimSuffix(logRef, "$"+lc.LogName), "/")
+			if logLD != ldName || logLN != strings.TrimPrefix(lnRef, ldName+"/") {
+				lc.LdInst = e.ldInst(logLD)
+				lc.Prefix, lc.LnClass, lc.LnInst = splitLNName(logLN)
+			}
+		}
+		ln.LogControls = append(ln.LogControls, lc)
+	}
+	return nil
+}
+
+// physicalAddress converts the DstAddress of a GOOSE or sampled value control block into address parameters.
+func physicalAddress(cb controlBlock) *sclAddress {
+	address := &sclAddress{}
+	if mac := cb.getBytes("DstAddress.Addr"); len(mac) == 6 {
+		parts := make([]string, len(mac))
+		for i, b := range mac {
+			parts[i] = fmt.Sprintf("%02X", b)
+		}
+		address.P = append(address.P, sclP{Type: "MAC-Address", Value: strings.Join(parts, "-")})
+	}
+	address.P = append(address.P,
+		sclP{Type: "APPID", Value: fmt.Sprintf("%04X", cb.getUint("DstAddress.APPID"))},
+		sclP{Type: "VLAN-ID", Value: fmt.Sprintf("%03X", cb.getUint("DstAddress.VID"))},
+		sclP{Type: "VLAN-PRIORITY", Value: strconv.FormatInt(cb.getUint("DstAddress.PRIORITY"), 10)},
+	)
+	return address
+}
+
+func (e *sclExporter) exportGSEControls(ln *sclLN, ldName, lnRef string, connectedAP *sclConnectedAP) error {
+	names, err := e.c.GetLogicalNodeDirectory(lnRef, ACSI_CLASS_GoCB)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		cb, err := e.c.readControlBlock(lnRef+"."+name, GO)
+		if err != nil {
+			return err
+		}
+		ln.GSEControls = append(ln.GSEControls, sclGSEControl{
+			Name:      name,
+			DatSet:    dataSetName(cb.getString("DatSet")),
+			ConfRev:   cb.getUint("ConfRev"),
+			AppID:     cb.getString("GoID"),
+			FixedOffs: cb.getBool("FixedOffs"),
+			Type:      "GOOSE",
+		})
+
+		gse := sclGSE{LdInst: e.ldInst(ldName), CbName: name, Address: physicalAddress(cb)}
+		if _, ok := cb["MinTime"]; ok {
+			gse.MinTime = &sclTime{Unit: "s", Multiplier: "m", Value: cb.getUint("MinTime")}
+			gse.MaxTime = &sclTime{Unit: "s", Multiplier: "m", Value: cb.getUint("MaxTime")}
+		}
+		connectedAP.GSEs = append(connectedAP.GSEs, gse)
+	}
+	return nil
+}
+
+func (e *sclExporter) exportSMVControls(ln *sclLN, ldName, lnRef string, connectedAP *sclConnectedAP) error {
+	for _, class := range []ACSIClass{ACSI_CLASS_MSVCB, ACSI_CLASS_USVCB} {
+		names, err := e.c.GetLogicalNodeDirectory(lnRef, class)
+		if err != nil {
+			return err
+		}
+		fc, idName := MS, "MsvID"
+		if class == ACSI_CLASS_USVCB {
+			fc, idName = US, "UsvID"
+		}
+		for _, name := range names {
+			cb, err := e.c.readControlBlock(lnRef+"."+name, fc)
+			if err != nil {
+				return err
+			}
+			optFlds := int(cb.getUint("OptFlds"))
+			smpMod := "SmpPerPeriod"
+			switch cb.getUint("SmpMod") {
+			case 1:
+				smpMod = "SmpPerSec"
+			case 2:
+				smpMod = "SecPerSmp"
+			}
+			ln.SMVControls = append(ln.SMVControls, sclSMVControl{
+				Name:      name,
+				DatSet:    dataSetName(cb.getString("DatSet")),
+				ConfRev:   cb.getUint("ConfRev"),
+				SmvID:     cb.getString(idName),
+				Multicast: fc == MS,
+				SmpRate:   cb.getUint("SmpRate"),
+				NofASDU:   cb.getUint("noASDU"),
+				SmpMod:    smpMod,
+				SmvOpts: &sclSmvOpts{
+					RefreshTime:        IsBitSet(optFlds, 0),
+					SampleSynchronized: IsBitSet(optFlds, 1),
+					SampleRate:         IsBitSet(optFlds, 2),
+					DataSet:            IsBitSet(optFlds, 3),
+					Security:           IsBitSet(optFlds, 4),
+				},
+			})
+			connectedAP.SMVs = append(connectedAP.SMVs, sclSMV{LdInst: e.ldInst(ldName), CbName: name, Address: physicalAddress(cb)})
+		}
+	}
+	return nil
+}
+
+func (e *sclExporter) exportSettingControl(ln *sclLN, lnRef string) error {
+	names, err := e.c.GetLogicalNodeDirectory(lnRef, ACSI_CLASS_SGCB)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		cb, err := e.c.readControlBlock(lnRef+"."+name, SP)
+		if err != nil {
+			return err
+		}
+		ln.SettingControl = &sclSettingControl{NumOfSGs: cb.getUint("NumOfSG"), ActSG: cb.getUint("ActSG")}
+	}
+	return nil
+}
+
+// XML elements of the exported SCL file, in the element order required by the SCL schema.
+
+type sclDoc struct {
+	XMLName           xml.Name          `xml:"SCL"`
+	Xmlns             string            `xml:"xmlns,attr"`
+	Version           string            `xml:"version,attr"`
+	Revision          string            `xml:"revision,attr"`
+	Header            sclHeader         `xml:"Header"`
+	Communication     *sclCommunication `xml:"Communication,omitempty"`
+	IED               sclIED            `xml:"IED"`
+	DataTypeTemplates sclTemplates      `xml:"DataTypeTemplates"`
+}
+
+type sclHeader struct {
+	Id            string `xml:"id,attr"`
+	NameStructure string `xml:"nameStructure,attr"`
+}
+
+type sclCommunication struct {
+	SubNetwork sclSubNetwork `xml:"SubNetwork"`
+}
+
+type sclSubNetwork struct {
+	Name        string          `xml:"name,attr"`
+	Type        string          `xml:"type,attr"`
+	ConnectedAP *sclConnectedAP `xml:"ConnectedAP"`
+}
+
+type sclConnectedAP struct {
+	IedName string      `xml:"iedName,attr"`
+	APName  string      `xml:"apName,attr"`
+	Address *sclAddress `xml:"Address,omitempty"`
+	GSEs    []sclGSE    `xml:"GSE"`
+	SMVs    []sclSMV    `xml:"SMV"`
+}
+
+type sclAddress struct {
+	P []sclP `xml:"P"`
+}
+
+type sclP struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type sclGSE struct {
+	LdInst  string      `xml:"ldInst,attr"`
+	CbName  string      `xml:"cbName,attr"`
+	Address *sclAddress `xml:"Address"`
+	MinTime *sclTime    `xml:"MinTime,omitempty"`
+	MaxTime *sclTime    `xml:"MaxTime,omitempty"`
+}
+
+type sclTime struct {
+	Unit       string `xml:"unit,attr"`
+	Multiplier string `xml:"multiplier,attr"`
+	Value      int64  `xml:",chardata"`
+}
+
+type sclSMV struct {
+	LdInst  string      `xml:"ldInst,attr"`
+	CbName  string      `xml:"cbName,attr"`
+	Address *sclAddress `xml:"Address"`
+}
+
+type sclIED struct {
+	Name          string         `xml:"name,attr"`
+	ConfigVersion string         `xml:"configVersion,attr"`
+	AccessPoint   sclAccessPoint `xml:"AccessPoint"`
+}
+
+type sclAccessPoint struct {
+	Name   string    `xml:"name,attr"`
+	Server sclServer `xml:"Server"`
+}
+
+type sclServer struct {
+	Authentication sclAuthentication `xml:"Authentication"`
+	LDevices       []*sclLDevice     `xml:"LDevice"`
+}
+
+type sclAuthentication struct {
+	None bool `xml:"none,attr"`
+}
+
+type sclLDevice struct {
+	Inst string   `xml:"inst,attr"`
+	LN0  *sclLN   `xml:"LN0"`
+	LNs  []*sclLN `xml:"LN"`
+}
+
+type sclLN struct {
+	XMLName        xml.Name
+	Prefix         string             `xml:"prefix,attr,omitempty"`
+	LnClass        string             `xml:"lnClass,attr"`
+	Inst           string             `xml:"inst,attr"`
+	LnType         string             `xml:"lnType,attr"`
+	DataSets       []sclDataSet       `xml:"DataSet"`
+	ReportControls []sclReportControl `xml:"ReportControl"`
+	LogControls    []sclLogControl    `xml:"LogControl"`
+	DOIs           []*sclInstance     `xml:"DOI"`
+	Logs           []sclLog           `xml:"Log"`
+	GSEControls    []sclGSEControl    `xml:"GSEControl"`
+	SMVControls    []sclSMVControl    `xml:"SampledValueControl"`
+	SettingControl *sclSettingControl `xml:"SettingControl,omitempty"`
+}
+
+type sclDataSet struct {
+	Name  string    `xml:"name,attr"`
+	FCDAs []sclFCDA `xml:"FCDA"`
+}
+
+type sclFCDA struct {
+	LdInst  string `xml:"ldInst,attr"`
+	Prefix  string `xml:"prefix,attr,omitempty"`
+	LnClass string `xml:"lnClass,attr"`
+	LnInst  string `xml:"lnInst,attr,omitempty"`
+	DoName  string `xml:"doName,attr,omitempty"`
+	DaName  string `xml:"daName,attr,omitempty"`
+	Fc      string `xml:"fc,attr"`
+}
+
+type sclTrgOps struct {
+	Dchg   bool `xml:"dchg,attr"`
+	Qchg   bool `xml:"qchg,attr"`
+	Dupd   bool `xml:"dupd,attr"`
+	Period bool `xml:"period,attr"`
+	Gi     bool `xml:"gi,attr"`
+}
+
+type sclOptFields struct {
+	SeqNum     bool `xml:"seqNum,attr"`
+	TimeStamp  bool `xml:"timeStamp,attr"`
+	DataSet    bool `xml:"dataSet,attr"`
+	ReasonCode bool `xml:"reasonCode,attr"`
+	DataRef    bool `xml:"dataRef,attr"`
+	EntryID    bool `xml:"entryID,attr"`
+	ConfigRef  bool `xml:"configRef,attr"`
+	BufOvfl    bool `xml:"bufOvfl,attr"`
+}
+
+type sclRptEnabled struct {
+	Max int `xml:"max,attr"`
+}
+
+type sclReportControl struct {
+	Name       string         `xml:"name,attr"`
+	DatSet     string         `xml:"datSet,attr,omitempty"`
+	IntgPd     int64          `xml:"intgPd,attr"`
+	RptID      string         `xml:"rptID,attr"`
+	ConfRev    int64          `xml:"confRev,attr"`
+	Buffered   bool           `xml:"buffered,attr"`
+	BufTime    int64          `xml:"bufTime,attr"`
+	Indexed    bool           `xml:"indexed,attr"`
+	TrgOps     *sclTrgOps     `xml:"TrgOps"`
+	OptFields  *sclOptFields  `xml:"OptFields"`
+	RptEnabled *sclRptEnabled `xml:"RptEnabled"`
+}
+
+type sclLogControl struct {
+	Name       string     `xml:"name,attr"`
+	DatSet     string     `xml:"datSet,attr,omitempty"`
+	IntgPd     int64      `xml:"intgPd,attr"`
+	LdInst     string     `xml:"ldInst,attr,omitempty"`
+	Prefix     string     `xml:"prefix,attr,omitempty"`
+	LnClass    string     `xml:"lnClass,attr,omitempty"`
+	LnInst     string     `xml:"lnInst,attr,omitempty"`
+	LogName    string     `xml:"logName,attr"`
+	LogEna     bool       `xml:"logEna,attr"`
+	ReasonCode bool       `xml:"reasonCode,attr"`
+	TrgOps     *sclTrgOps `xml:"TrgOps"`
+}
+
+type sclLog struct {
+	Name string `xml:"name,attr"`
+}
+
+type sclGSEControl struct {
+	Name      string `xml:"name,attr"`
+	DatSet    string `xml:"datSet,attr,omitempty"`
+	ConfRev   int64  `xml:"confRev,attr"`
+	AppID     string `xml:"appID,attr"`
+	FixedOffs bool   `xml:"fixedOffs,attr"`
+	Type      string `xml:"type,attr"`
+}
+
+type sclSmvOpts struct {
+	RefreshTime        bool `xml:"refreshTime,attr"`
+	SampleSynchronized bool `xml:"sampleSynchronized,attr"`
+	SampleRate         bool `xml:"sampleRate,attr"`
+	DataSet            bool `xml:"dataSet,attr"`
+	Security           bool `xml:"security,attr"`
+}
+
+type sclSMVControl struct {
+	Name      string      `xml:"name,attr"`
+	DatSet    string      `xml:"datSet,attr,omitempty"`
+	ConfRev   int64       `xml:"confRev,attr"`
+	SmvID     string      `xml:"smvID,attr"`
+	Multicast bool        `xml:"multicast,attr"`
+	SmpRate   int64       `xml:"smpRate,attr"`
+	NofASDU   int64       `xml:"nofASDU,attr"`
+	SmpMod    string      `xml:"smpMod,attr"`
+	SmvOpts   *sclSmvOpts `xml:"SmvOpts"`
+}
+
+type sclSettingControl struct {
+	NumOfSGs int64 `xml:"numOfSGs,attr"`
+	ActSG    int64 `xml:"actSG,attr"`
+}
+
+// sclInstance is a DOI, SDI or DAI element.
+type sclInstance struct {
+	XMLName  xml.Name
+	Name     string         `xml:"name,attr"`
+	Val      *sclVal        `xml:"Val,omitempty"`
+	Children []*sclInstance `xml:"DAI"`
+}
+
+type sclVal struct {
+	Value string `xml:",chardata"`
+}
+
+type sclTemplates struct {
+	LNodeTypes []*sclLNodeType `xml:"LNodeType"`
+	DOTypes    []*sclDOType    `xml:"DOType"`
+	DATypes    []*sclDAType    `xml:"DAType"`
+}
+
+type sclLNodeType struct {
+	Id      string     `xml:"id,attr"`
+	LnClass string     `xml:"lnClass,attr"`
+	DOs     []sclDODef `xml:"DO"`
+}
+
+type sclDODef struct {
+	Name string `xml:"name,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type sclDOType struct {
+	Id       string            `xml:"id,attr"`
+	Cdc      string            `xml:"cdc,attr"`
+	Elements []*sclTypeElement `xml:"DA"`
+}
+
+type sclDAType struct {
+	Id   string            `xml:"id,attr"`
+	BDAs []*sclTypeElement `xml:"BDA"`
+}
+
+// sclTypeElement is a DA, BDA or SDO element of a type template.
+type sclTypeElement struct {
+	XMLName xml.Name
+	Name    string `xml:"name,attr"`
+	Fc      string `xml:"fc,attr,omitempty"`
+	BType   string `xml:"bType,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Count   int    `xml:"count,attr,omitempty"`
+	Dchg    bool   `xml:"dchg,attr,omitempty"`
+	Qchg    bool   `xml:"qchg,attr,omitempty"`
+}
+
+func (el *sclTypeElement) signature() string {
+	return fmt.Sprintf("%s %s/%s/%s/%s/%d/%t/%t", el.XMLName.Local, el.Name, el.Fc, el.BType, el.Type, el.Count, el.Dchg, el.Qchg)
+}
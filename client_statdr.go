@@ -5,7 +5,6 @@ import "C"
 
 import (
 	"fmt"
-	"log"
 	"strings"
 )
 
@@ -30,7 +29,7 @@ func sameDataSet(a, b string) bool {
 func (c *Client) PickAndEnableStatDRBRCB(ld, ln string, datasetRef string) (string, func() error, error) {
 	lnRef := fmt.Sprintf("%s/%s", ld, ln)
 
-	log.Printf("Picking free StatDR BRCB for %s", lnRef)
+	c.logger().Debug("picking free StatDR BRCB", "ref", lnRef)
 
 	// List all BRCB names for LLN0
 	names, err := c.GetLogicalNodeDirectory(fmt.Sprintf("%s/%s", ld, ln), ACSI_CLASS_BRCB)
@@ -41,27 +40,27 @@ func (c *Client) PickAndEnableStatDRBRCB(ld, ln string, datasetRef string) (stri
 	// Try all RCBs with prefix rcbStatDR
 	for _, name := range names {
 		if !strings.HasPrefix(name, "rcbStatDR") { // exact, case-sensitive prefix match
-			log.Printf("Note: Skipping %s (not a rcbStatDRxx)", name)
+			c.logger().Debug("skipping BRCB, not a rcbStatDRxx", "ref", fmt.Sprintf("%s.%s", lnRef, name))
 			continue
 		}
 		rcbRef := fmt.Sprintf("%s.BR.%s", lnRef, name)
 
-		log.Printf("Trying %s", rcbRef)
+		c.logger().Debug("trying StatDR BRCB", "ref", rcbRef)
 
 		// ReadObject current values
 		rcb, err := c.GetRCBValues(rcbRef)
 		if err != nil || rcb == nil {
-			log.Printf("Note: GetRCBValues for %s failed, will continue to next rcb. Error was: %s", rcbRef, err)
+			c.logger().Info("GetRCBValues failed, continuing with next rcb", "ref", rcbRef, "error", err)
 			continue
 		}
 
 		if !sameDataSet(rcb.DatSet, datasetRef) {
 			// Dataset mismatch - try next candidate
-			log.Printf("Note: Dataset mismatch for %s, will continue to next rcb. Expected %s, got %s", rcbRef, datasetRef, rcb.DatSet)
+			c.logger().Debug("data set mismatch, continuing with next rcb", "ref", rcbRef, "expected", datasetRef, "datSet", rcb.DatSet)
 			continue
 		}
 
-		log.Printf("Found free StatDR BRCB %s related to DS=%s", rcbRef, datasetRef)
+		c.logger().Debug("found free StatDR BRCB", "ref", rcbRef, "datSet", datasetRef)
 
 		// If already enabled, try to disable first to take ownership
 		if rcb.Ena {
@@ -72,15 +71,15 @@ func (c *Client) PickAndEnableStatDRBRCB(ld, ln string, datasetRef string) (stri
 			// re-read to confirm disabled
 			rcb, err = c.GetRCBValues(rcbRef)
 			if err != nil {
-				log.Printf("Note: RptEna=true for %s, will continue to next rcb. Error was: %s", rcbRef, err)
+				c.logger().Info("RptEna still set, continuing with next rcb", "ref", rcbRef, "error", err)
 				continue
 			}
 			if rcb == nil {
-				log.Printf("Note: RptEna=true for %s, will continue to next rcb. Rcb is nil.", rcbRef)
+				c.logger().Info("RptEna still set, continuing with next rcb", "ref", rcbRef)
 				continue
 			}
 			if rcb.Ena {
-				log.Printf("Note: RptEna=true for %s, will continue to next rcb. RptEna=true.", rcbRef)
+				c.logger().Info("RptEna still set, continuing with next rcb", "ref", rcbRef)
 				continue
 			}
 		}
@@ -89,22 +88,22 @@ func (c *Client) PickAndEnableStatDRBRCB(ld, ln string, datasetRef string) (stri
 		ops := TrgOps{DataChange: true, TriggeredPeriodically: false, Gi: true}
 		if err := c.SetTrgOps(rcbRef, ops); err != nil {
 			// some IEDs may restrict changes, still continue
-			log.Printf("Note: SetTrgOps failed for %s, will ignore. Error was: %s", rcbRef, err)
+			c.logger().Info("SetTrgOps failed, ignoring", "ref", rcbRef, "error", err)
 		}
 		// BufTm and IntgPd typical for StatDR
 		if err := c.SetBufTm(rcbRef, 50); err != nil {
-			log.Printf("Note: SetBufTm failed for %s, will ignore. Error was: %s", rcbRef, err)
+			c.logger().Info("SetBufTm failed, ignoring", "ref", rcbRef, "error", err)
 		}
 		/*if err := c.SetIntgPd(rcbRef, 10000); err != nil {
-			log.Printf("Note: SetIntgPd failed for %s, will ignore. Error was: %s", rcbRef, err)
+			c.logger().Info("SetIntgPd failed, ignoring", "ref", rcbRef, "error", err)
 		}*/
 		if err := c.SetGI(rcbRef, true); err != nil {
-			log.Printf("Note: SetGI failed for %s, will ignore. Error was: %s", rcbRef, err)
+			c.logger().Info("SetGI failed, ignoring", "ref", rcbRef, "error", err)
 		}
 
 		// Enable
 		if err := c.SetRptEna(rcbRef, true); err != nil {
-			log.Printf("Note: SetRptEna failed for %s, will continue to next rcb. Error was: %s", rcbRef, err)
+			c.logger().Info("SetRptEna failed, continuing with next rcb", "ref", rcbRef, "error", err)
 			// try next candidate when enabling fails
 			continue
 		}
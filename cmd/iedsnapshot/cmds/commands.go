@@ -0,0 +1,83 @@
+package cmds
+
+import (
+	"fmt"
+	"github.com/marrasen/iec61850"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+var (
+	host         string
+	port         int
+	outFile      string
+	ignoreValues bool
+	ignore       []string
+)
+
+func New() *cobra.Command {
+	rootCommand := &cobra.Command{
+		Use:   "iedsnapshot",
+		Short: "iedsnapshot records the data model and values of an IED and detects configuration drift",
+	}
+
+	saveCommand := &cobra.Command{
+		Use:   "save",
+		Short: "Connect to an IED and save a snapshot as JSON, or as YAML for .yaml/.yml files",
+		Args:  cobra.NoArgs,
+		RunE:  runSave,
+	}
+	saveCommand.Flags().StringVar(&host, "host", "localhost", "IED host")
+	saveCommand.Flags().IntVarP(&port, "port", "p", 102, "IED port")
+	saveCommand.Flags().StringVarP(&outFile, "out", "o", "snapshot.json", "Output file")
+
+	diffCommand := &cobra.Command{
+		Use:   "diff <old snapshot> <new snapshot>",
+		Short: "Report the differences between two snapshots, exits with status 1 if there are any",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runDiff,
+	}
+	diffCommand.Flags().BoolVar(&ignoreValues, "ignore-values", false, "Only compare the data models")
+	diffCommand.Flags().StringSliceVar(&ignore, "ignore", nil, "Ignore references matching the pattern, e.g. \"*/*.t\"")
+
+	rootCommand.AddCommand(saveCommand, diffCommand)
+
+	return rootCommand
+}
+
+func runSave(cmd *cobra.Command, args []string) error {
+	settings := iec61850.NewSettings()
+	settings.Host = host
+	settings.Port = port
+	client, err := iec61850.NewClient(settings)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	snapshot, err := client.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	return snapshot.Save(outFile)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	old, err := iec61850.LoadSnapshot(args[0])
+	if err != nil {
+		return err
+	}
+	new, err := iec61850.LoadSnapshot(args[1])
+	if err != nil {
+		return err
+	}
+
+	changes := iec61850.DiffSnapshots(old, new, iec61850.DiffOptions{IgnoreValues: ignoreValues, Ignore: ignore})
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if len(changes) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
@@ -24,6 +24,7 @@ type (
 
 	Quality  uint16
 	Validity uint16
+	Dbpos    int
 )
 
 const (
@@ -52,6 +53,14 @@ const (
 	VALIDITY_QUESTIONABLE
 )
 
+// Dbpos values of double point status attributes like the stVal of DPS and DPC.
+const (
+	DBPOS_INTERMEDIATE_STATE Dbpos = iota
+	DBPOS_OFF
+	DBPOS_ON
+	DBPOS_BAD_STATE
+)
+
 func (receiver Quality) GetValidity() Validity {
 	return Validity(receiver & 0x3)
 }
@@ -123,6 +132,12 @@ func (receiver *Timestamp) GetSubSecondPrecision() int {
 	return int(C.Timestamp_getSubsecondPrecision(&receiver.cTimestamp))
 }
 
+// SetSubSecondPrecision sets the number of significant bits of the fraction of second.
+func (receiver *Timestamp) SetSubSecondPrecision(value int) *Timestamp {
+	C.Timestamp_setSubsecondPrecision(&receiver.cTimestamp, C.int(value))
+	return receiver
+}
+
 func (receiver *Timestamp) SetTime(time time.Time) *Timestamp {
 	C.Timestamp_setTimeInNanoseconds(&receiver.cTimestamp, C.nsSinceEpoch(time.UnixNano()))
 	return receiver
@@ -18,16 +18,107 @@ type LN struct {
 	DSs       []DS
 	URReports []URReport
 	BRReports []BRReport
+	LCBs      []LCB
+	GoCBs     []GoCB
+	SVCBs     []SVCB
+	SGCB      *SGCB // setting group control block, only present in LLN0 of logical devices with setting groups
+	Logs      []Log
+}
+
+// ReportAttributes are the attributes of a report control block
+type ReportAttributes struct {
+	RptID   string
+	DatSet  string
+	ConfRev uint32
+	RptEna  bool
+	BufTm   uint32 // Buffer time (ms)
+	IntgPd  uint32 // Integrity period (ms)
+	TrgOps  TrgOps
+	OptFlds OptFlds
 }
 
 // URReport are Unbuffer Reports
 type URReport struct {
-	Data string
-	Ref  string
+	Data             string
+	Ref              string
+	ReportAttributes `yaml:",inline"`
 }
 
 // BRReport are Buffered Reports
 type BRReport struct {
+	Data             string
+	Ref              string
+	ReportAttributes `yaml:",inline"`
+}
+
+// LCB is a Log Control Block
+type LCB struct {
+	Data   string
+	Ref    string
+	DatSet string
+	LogRef string
+	LogEna bool
+	IntgPd uint32 // Integrity period (ms)
+	TrgOps TrgOps
+}
+
+// GoCB is a GOOSE Control Block
+type GoCB struct {
+	Data       string
+	Ref        string
+	GoID       string
+	DatSet     string
+	ConfRev    uint32
+	GoEna      bool
+	NdsCom     bool
+	FixedOffs  bool
+	MinTime    uint32 // Minimum retransmission time (ms), 0 if the control block has none
+	MaxTime    uint32 // Maximum retransmission time (ms), 0 if the control block has none
+	DstAddress PhyComAddress
+}
+
+// SVCB is a Sampled Value Control Block, multicast (MSVCB) or unicast (USVCB)
+type SVCB struct {
+	Data       string
+	Ref        string
+	Multicast  bool
+	SvID       string
+	DatSet     string
+	ConfRev    uint32
+	SvEna      bool
+	SmpRate    uint32
+	SmpMod     uint32 // 0 samples per period, 1 samples per second, 2 seconds per sample
+	NoASDU     uint32
+	OptFlds    SVOptFlds
+	DstAddress PhyComAddress
+}
+
+// SVOptFlds are the optional fields of sampled value messages
+type SVOptFlds struct {
+	RefreshTime        bool
+	SampleSynchronized bool
+	SampleRate         bool
+	DataSet            bool
+	Security           bool
+}
+
+// PhyComAddress is the destination address of GOOSE and sampled value messages
+type PhyComAddress struct {
+	VlanPriority uint8
+	VlanId       uint16
+	AppId        uint16
+	DstAddress   [6]uint8
+}
+
+// SGCB is a Setting Group Control Block
+type SGCB struct {
+	Data         string
+	Ref          string
+	SettingGroup `yaml:",inline"`
+}
+
+// Log is a log of a logical node
+type Log struct {
 	Data string
 	Ref  string
 }
@@ -39,20 +130,29 @@ type DS struct {
 	IsDeletable bool
 }
 
+// DSRef is a data set member
 type DSRef struct {
-	Data string
+	Data string // member as returned by the server, e.g. "LD/LN.DO.DA[FC]"
+	Ref  string // object reference without functional constraint
+	FC   FC
 }
 
 // DO represents a Data Object
 type DO struct {
 	Data string
+	Ref  string
+	CDC  string // common data class, inferred from the attributes since MMS does not transport it
 	DAs  []DA
+	SDOs []DO
 }
 
 // DA represents a Data Attribute
 type DA struct {
-	Data string
-	DAs  []DA
-	Ref  string
-	FC   FC
+	Data  string
+	DAs   []DA
+	Ref   string
+	FC    FC
+	Type  MmsType // MMS type, the element type for arrays
+	BType string  // basic type as used in SCL, e.g. "FLOAT32" or "Quality"
+	Count int     // number of elements of an array, 0 otherwise
 }
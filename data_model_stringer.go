@@ -1,6 +1,7 @@
 package iec61850
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -57,6 +58,21 @@ func (ln LN) writeTo(b *strings.Builder, level int) {
 	for _, r := range ln.BRReports {
 		r.writeTo(b, level+1)
 	}
+	for _, cb := range ln.LCBs {
+		cb.writeTo(b, level+1)
+	}
+	for _, cb := range ln.GoCBs {
+		cb.writeTo(b, level+1)
+	}
+	for _, cb := range ln.SVCBs {
+		cb.writeTo(b, level+1)
+	}
+	if ln.SGCB != nil {
+		ln.SGCB.writeTo(b, level+1)
+	}
+	for _, l := range ln.Logs {
+		l.writeTo(b, level+1)
+	}
 }
 
 func (r URReport) String() string {
@@ -66,7 +82,7 @@ func (r URReport) String() string {
 }
 
 func (r URReport) writeTo(b *strings.Builder, level int) {
-	b.WriteString(indent(level) + "URReport: " + r.Data + " Ref: " + r.Ref + "\n")
+	b.WriteString(indent(level) + "URReport: " + r.Data + " Ref: " + r.Ref + r.ReportAttributes.String() + "\n")
 }
 
 func (r BRReport) String() string {
@@ -76,7 +92,73 @@ func (r BRReport) String() string {
 }
 
 func (r BRReport) writeTo(b *strings.Builder, level int) {
-	b.WriteString(indent(level) + "BRReport: " + r.Data + " Ref: " + r.Ref + "\n")
+	b.WriteString(indent(level) + "BRReport: " + r.Data + " Ref: " + r.Ref + r.ReportAttributes.String() + "\n")
+}
+
+func (r ReportAttributes) String() string {
+	return fmt.Sprintf(" RptID: %s DatSet: %s ConfRev: %d RptEna: %t", r.RptID, r.DatSet, r.ConfRev, r.RptEna)
+}
+
+func (cb LCB) String() string {
+	var b strings.Builder
+	cb.writeTo(&b, 0)
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (cb LCB) writeTo(b *strings.Builder, level int) {
+	fmt.Fprintf(b, "%sLCB: %s Ref: %s DatSet: %s LogRef: %s LogEna: %t\n", indent(level), cb.Data, cb.Ref, cb.DatSet, cb.LogRef, cb.LogEna)
+}
+
+func (cb GoCB) String() string {
+	var b strings.Builder
+	cb.writeTo(&b, 0)
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (cb GoCB) writeTo(b *strings.Builder, level int) {
+	fmt.Fprintf(b, "%sGoCB: %s Ref: %s GoID: %s DatSet: %s ConfRev: %d GoEna: %t %s\n",
+		indent(level), cb.Data, cb.Ref, cb.GoID, cb.DatSet, cb.ConfRev, cb.GoEna, cb.DstAddress)
+}
+
+func (cb SVCB) String() string {
+	var b strings.Builder
+	cb.writeTo(&b, 0)
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (cb SVCB) writeTo(b *strings.Builder, level int) {
+	kind := "USVCB"
+	if cb.Multicast {
+		kind = "MSVCB"
+	}
+	fmt.Fprintf(b, "%s%s: %s Ref: %s SvID: %s DatSet: %s ConfRev: %d SvEna: %t %s\n",
+		indent(level), kind, cb.Data, cb.Ref, cb.SvID, cb.DatSet, cb.ConfRev, cb.SvEna, cb.DstAddress)
+}
+
+func (a PhyComAddress) String() string {
+	return fmt.Sprintf("Addr: %02X-%02X-%02X-%02X-%02X-%02X APPID: %04X VID: %03X Priority: %d",
+		a.DstAddress[0], a.DstAddress[1], a.DstAddress[2], a.DstAddress[3], a.DstAddress[4], a.DstAddress[5],
+		a.AppId, a.VlanId, a.VlanPriority)
+}
+
+func (cb SGCB) String() string {
+	var b strings.Builder
+	cb.writeTo(&b, 0)
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (cb SGCB) writeTo(b *strings.Builder, level int) {
+	fmt.Fprintf(b, "%sSGCB: %s Ref: %s NumOfSG: %d ActSG: %d EditSG: %d\n", indent(level), cb.Data, cb.Ref, cb.NumOfSG, cb.ActSG, cb.EditSG)
+}
+
+func (l Log) String() string {
+	var b strings.Builder
+	l.writeTo(&b, 0)
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (l Log) writeTo(b *strings.Builder, level int) {
+	b.WriteString(indent(level) + "Log: " + l.Data + " Ref: " + l.Ref + "\n")
 }
 
 func (ds DS) String() string {
@@ -114,10 +196,17 @@ func (d DO) String() string {
 }
 
 func (d DO) writeTo(b *strings.Builder, level int) {
-	b.WriteString(indent(level) + "DO: " + d.Data + "\n")
+	b.WriteString(indent(level) + "DO: " + d.Data)
+	if d.CDC != "" {
+		b.WriteString(" CDC: " + d.CDC)
+	}
+	b.WriteString("\n")
 	for _, da := range d.DAs {
 		da.writeTo(b, level+1)
 	}
+	for _, sdo := range d.SDOs {
+		sdo.writeTo(b, level+1)
+	}
 }
 
 func (da DA) String() string {
@@ -135,6 +224,15 @@ func (da DA) writeTo(b *strings.Builder, level int) {
 	b.WriteString(da.Ref)
 	b.WriteString(" FC: ")
 	b.WriteString(da.FC.String())
+	if da.BType != "" {
+		b.WriteString(" Type: ")
+		b.WriteString(da.Type.String())
+		b.WriteString(" BType: ")
+		b.WriteString(da.BType)
+		if da.Count > 0 {
+			fmt.Fprintf(b, "[%d]", da.Count)
+		}
+	}
 	b.WriteString("\n")
 	for _, child := range da.DAs {
 		child.writeTo(b, level+1)
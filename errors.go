@@ -38,6 +38,9 @@ var (
 	ControlSelectFail                 = errors.New("select control fail")
 	UnSupportedOperation              = errors.New("unsupported operation")
 	ReadDataAccessError               = errors.New("data access error")
+	TLSNotEnabled                     = errors.New("the instance was not created with TLS support")
+	IPv6NotSupported                  = errors.New("libiec61850 servers listen on IPv4 addresses only")
+	CorruptLogFile                    = errors.New("the log file has an unknown or inconsistent record")
 )
 
 func GetIedClientError(err C.IedClientError) error {
@@ -56,16 +56,16 @@ func main() {
 	server.SetWriteAccessPolicy(iec61850.DC, iec61850.ACCESS_POLICY_ALLOW)
 
 	// Install connection indication handler
-	server.SetConnectionIndicationHandler(func(s *iec61850.IedServer, connected bool) {
+	server.SetConnectionIndicationHandler(func(s *iec61850.IedServer, connection *iec61850.ClientConnection, connected bool) {
 		if connected {
-			fmt.Println("Connection opened")
+			fmt.Printf("Connection opened (%s)\n", connection.PeerAddress)
 		} else {
-			fmt.Println("Connection closed")
+			fmt.Printf("Connection closed (%s)\n", connection.PeerAddress)
 		}
 	})
 
 	// Install RCB event handler (prints like the C sample)
-	server.SetRCBEventHandler(func(rcb *iec61850.ReportControlBlock, event iec61850.RCBEventType, parameterName string, serviceError iec61850.MmsDataAccessError) {
+	server.SetRCBEventHandler(func(rcb *iec61850.ReportControlBlock, connection *iec61850.ClientConnection, event iec61850.RCBEventType, parameterName string, serviceError iec61850.MmsDataAccessError) {
 		fmt.Printf("RCB: %s event: %d\n", rcb.GetName(), event)
 		if event == iec61850.RCB_EVENT_SET_PARAMETER || event == iec61850.RCB_EVENT_GET_PARAMETER {
 			fmt.Printf("  param:  %s\n", parameterName)
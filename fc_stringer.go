@@ -8,3 +8,14 @@ import "C"
 func (f FC) String() string {
 	return C.GoString(C.FunctionalConstraint_toString(C.FunctionalConstraint(f)))
 }
+
+// MarshalText encodes the FC as its abbreviation, so serialized data models stay readable.
+func (f FC) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText decodes an abbreviation written by MarshalText.
+func (f *FC) UnmarshalText(text []byte) error {
+	*f = FunctionalConstraintFromString(string(text))
+	return nil
+}
@@ -0,0 +1,360 @@
+package iec61850
+
+/*
+#include <stdlib.h>
+#include <iec61850_server.h>
+#include <logging_api.h>
+
+extern uint64_t logStorageAddEntryBridge(LogStorage self, uint64_t timestamp);
+extern bool logStorageAddEntryDataBridge(LogStorage self, uint64_t entryID, char* dataRef, uint8_t* data, int dataSize, uint8_t reasonCode);
+extern bool logStorageGetEntriesBridge(LogStorage self, uint64_t startingTime, uint64_t endingTime,
+        LogEntryCallback entryCallback, LogEntryDataCallback entryDataCallback, void* parameter);
+extern bool logStorageGetEntriesAfterBridge(LogStorage self, uint64_t startingTime, uint64_t entryID,
+        LogEntryCallback entryCallback, LogEntryDataCallback entryDataCallback, void* parameter);
+extern bool logStorageGetOldestAndNewestEntriesBridge(LogStorage self, uint64_t* newEntry, uint64_t* newEntryTime,
+        uint64_t* oldEntry, uint64_t* oldEntryTime);
+
+static bool callLogEntryCallback(LogEntryCallback callback, void* parameter, uint64_t timestamp, uint64_t entryID, bool moreFollow) {
+    if (callback == NULL)
+        return true;
+    return callback(parameter, timestamp, entryID, moreFollow);
+}
+
+static bool callLogEntryDataCallback(LogEntryDataCallback callback, void* parameter, char* dataRef, uint8_t* data, int dataSize,
+        uint8_t reasonCode, bool moreFollow) {
+    if (callback == NULL)
+        return true;
+    return callback(parameter, dataRef, data, dataSize, reasonCode, moreFollow);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"unsafe"
+)
+
+// DEFAULT_MAX_LOG_ENTRIES is the capacity of log storages created with maxEntries 0.
+const DEFAULT_MAX_LOG_ENTRIES = 1000
+
+// LogEntryData is a value of a log entry.
+type LogEntryData struct {
+	DataRef    string // like "simpleIOGenericIO/GGIO1$ST$Ind1$stVal"
+	Data       []byte // the value in MMS encoding, see Value
+	ReasonCode uint8  // trigger of the entry, bits like TRG_OPT_DATA_CHANGED
+}
+
+// LogEntry is an entry of a log with the values logged together.
+type LogEntry struct {
+	EntryID   uint64
+	Timestamp uint64 // ms since epoch
+	Data      []LogEntryData
+}
+
+// LogStorage stores the entries of a log of the server, which are written by the log control blocks
+// and read by the QueryLog services of the clients. The methods are called by the threads of the
+// server and must be safe for concurrent use.
+type LogStorage interface {
+	// AddEntry adds an empty entry and returns its ID, IDs are increasing.
+	AddEntry(timestamp uint64) (uint64, error)
+	// AddEntryData adds a value to the entry.
+	AddEntryData(entryID uint64, data LogEntryData) error
+	// GetEntries calls fn for the entries with a timestamp between startingTime and endingTime including
+	// both, in order of the IDs. endingTime 0 has no limit. It stops when fn returns false.
+	GetEntries(startingTime, endingTime uint64, fn func(entry LogEntry) bool) error
+	// GetEntriesAfter calls fn for the entries after the entry with the ID with a timestamp of at least
+	// startingTime, in order of the IDs. It stops when fn returns false.
+	GetEntriesAfter(startingTime, entryID uint64, fn func(entry LogEntry) bool) error
+	// GetOldestAndNewestEntries returns the oldest and the newest entry without data, ok is false
+	// when the log is empty.
+	GetOldestAndNewestEntries() (oldest, newest LogEntry, ok bool)
+}
+
+// Value decodes the MMS encoded value.
+func (d LogEntryData) Value() (*MmsValue, error) {
+	if len(d.Data) == 0 {
+		return nil, fmt.Errorf("LogEntryData %q: no data", d.DataRef)
+	}
+	buffer := C.CBytes(d.Data)
+	defer C.free(buffer)
+	var endBufPos C.int
+	value := C.MmsValue_decodeMmsData((*C.uint8_t)(buffer), 0, C.int(len(d.Data)), &endBufPos)
+	if value == nil {
+		return nil, fmt.Errorf("LogEntryData %q: invalid data", d.DataRef)
+	}
+	defer C.MmsValue_delete(value)
+
+	mmsType := MmsType(C.MmsValue_getType(value))
+	goValue, err := toGoValue(value, mmsType)
+	if err != nil {
+		return nil, fmt.Errorf("LogEntryData %q: %w", d.DataRef, err)
+	}
+	return &MmsValue{Type: mmsType, Value: goValue}, nil
+}
+
+type logStorageCallback struct {
+	storage LogStorage
+	logger  *slog.Logger
+}
+
+var logStorageCallbacks = make(map[int32]*logStorageCallback)
+
+// SetLogStorage attaches the storage to the log, like "GenericIO/LLN0$EventLog" with the name of the
+// logical device without the IED name. It must be called before Start. The server keeps no entries
+// of logs without storage.
+func (is *IedServer) SetLogStorage(logRef string, storage LogStorage) {
+	callbackId := callbackIdGen.Add(1)
+	logStorageCallbacks[callbackId] = &logStorageCallback{
+		storage: storage,
+		logger:  is.logger(),
+	}
+
+	cStorage := (C.LogStorage)(C.calloc(1, C.sizeof_struct_sLogStorage))
+	cStorage.instanceData = intToPointerBug58625(callbackId)
+	cStorage.addEntry = (*[0]byte)(C.logStorageAddEntryBridge)
+	cStorage.addEntryData = (*[0]byte)(C.logStorageAddEntryDataBridge)
+	cStorage.getEntries = (*[0]byte)(C.logStorageGetEntriesBridge)
+	cStorage.getEntriesAfter = (*[0]byte)(C.logStorageGetEntriesAfterBridge)
+	cStorage.getOldestAndNewestEntries = (*[0]byte)(C.logStorageGetOldestAndNewestEntriesBridge)
+	is.logStorages = append(is.logStorages, cStorage)
+
+	is.apply(func() {
+		cLogRef := C.CString(logRef)
+		defer C.free(unsafe.Pointer(cLogRef))
+		C.IedServer_setLogStorage(is.server, cLogRef, cStorage)
+	})
+}
+
+// destroyLogStorages frees the C side of the log storages after the server is destroyed.
+func (is *IedServer) destroyLogStorages() {
+	for _, cStorage := range is.logStorages {
+		delete(logStorageCallbacks, int32(uintptr(cStorage.instanceData)))
+		C.free(unsafe.Pointer(cStorage))
+	}
+	is.logStorages = nil
+}
+
+func logStorageCallbackOf(self C.LogStorage) *logStorageCallback {
+	return logStorageCallbacks[int32(uintptr(self.instanceData))]
+}
+
+//export logStorageAddEntryBridge
+func logStorageAddEntryBridge(self C.LogStorage, timestamp C.uint64_t) C.uint64_t {
+	call := logStorageCallbackOf(self)
+	if call == nil {
+		return 0
+	}
+	entryID, err := call.storage.AddEntry(uint64(timestamp))
+	if err != nil {
+		call.logger.Error("log entry not stored", "error", err)
+		return 0
+	}
+	return C.uint64_t(entryID)
+}
+
+//export logStorageAddEntryDataBridge
+func logStorageAddEntryDataBridge(self C.LogStorage, entryID C.uint64_t, dataRef *C.char, data *C.uint8_t, dataSize C.int, reasonCode C.uint8_t) C.bool {
+	call := logStorageCallbackOf(self)
+	if call == nil {
+		return false
+	}
+	entryData := LogEntryData{
+		DataRef:    C.GoString(dataRef),
+		Data:       C.GoBytes(unsafe.Pointer(data), dataSize),
+		ReasonCode: uint8(reasonCode),
+	}
+	if err := call.storage.AddEntryData(uint64(entryID), entryData); err != nil {
+		call.logger.Error("log entry data not stored", "ref", entryData.DataRef, "error", err)
+		return false
+	}
+	return true
+}
+
+// logEntryWriter passes the entries of a query to the callbacks of libiec61850.
+func logEntryWriter(entryCallback C.LogEntryCallback, entryDataCallback C.LogEntryDataCallback, parameter unsafe.Pointer) func(entry LogEntry) bool {
+	return func(entry LogEntry) bool {
+		if !C.callLogEntryCallback(entryCallback, parameter, C.uint64_t(entry.Timestamp), C.uint64_t(entry.EntryID), true) {
+			return false
+		}
+		for _, data := range entry.Data {
+			cDataRef := C.CString(data.DataRef)
+			cData := C.CBytes(data.Data)
+			more := C.callLogEntryDataCallback(entryDataCallback, parameter, cDataRef, (*C.uint8_t)(cData), C.int(len(data.Data)),
+				C.uint8_t(data.ReasonCode), true)
+			C.free(cData)
+			C.free(unsafe.Pointer(cDataRef))
+			if !more {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+//export logStorageGetEntriesBridge
+func logStorageGetEntriesBridge(self C.LogStorage, startingTime C.uint64_t, endingTime C.uint64_t,
+	entryCallback C.LogEntryCallback, entryDataCallback C.LogEntryDataCallback, parameter unsafe.Pointer) C.bool {
+	call := logStorageCallbackOf(self)
+	if call == nil {
+		return false
+	}
+	err := call.storage.GetEntries(uint64(startingTime), uint64(endingTime), logEntryWriter(entryCallback, entryDataCallback, parameter))
+	C.callLogEntryCallback(entryCallback, parameter, 0, 0, false)
+	if err != nil {
+		call.logger.Error("log query failed", "error", err)
+		return false
+	}
+	return true
+}
+
+//export logStorageGetEntriesAfterBridge
+func logStorageGetEntriesAfterBridge(self C.LogStorage, startingTime C.uint64_t, entryID C.uint64_t,
+	entryCallback C.LogEntryCallback, entryDataCallback C.LogEntryDataCallback, parameter unsafe.Pointer) C.bool {
+	call := logStorageCallbackOf(self)
+	if call == nil {
+		return false
+	}
+	err := call.storage.GetEntriesAfter(uint64(startingTime), uint64(entryID), logEntryWriter(entryCallback, entryDataCallback, parameter))
+	C.callLogEntryCallback(entryCallback, parameter, 0, 0, false)
+	if err != nil {
+		call.logger.Error("log query failed", "error", err)
+		return false
+	}
+	return true
+}
+
+//export logStorageGetOldestAndNewestEntriesBridge
+func logStorageGetOldestAndNewestEntriesBridge(self C.LogStorage, newEntry *C.uint64_t, newEntryTime *C.uint64_t,
+	oldEntry *C.uint64_t, oldEntryTime *C.uint64_t) C.bool {
+	call := logStorageCallbackOf(self)
+	if call == nil {
+		return false
+	}
+	oldest, newest, ok := call.storage.GetOldestAndNewestEntries()
+	if !ok {
+		return false
+	}
+	*newEntry = C.uint64_t(newest.EntryID)
+	*newEntryTime = C.uint64_t(newest.Timestamp)
+	*oldEntry = C.uint64_t(oldest.EntryID)
+	*oldEntryTime = C.uint64_t(oldest.Timestamp)
+	return true
+}
+
+// MemoryLogStorage keeps the newest entries of a log in memory, the oldest entry is dropped when
+// a new entry exceeds the capacity.
+type MemoryLogStorage struct {
+	mu      sync.Mutex
+	entries []LogEntry // ring buffer
+	first   int        // index of the oldest entry
+	count   int
+	nextID  uint64
+}
+
+// NewMemoryLogStorage creates an empty storage for maxEntries entries, DEFAULT_MAX_LOG_ENTRIES when 0.
+func NewMemoryLogStorage(maxEntries int) *MemoryLogStorage {
+	if maxEntries <= 0 {
+		maxEntries = DEFAULT_MAX_LOG_ENTRIES
+	}
+	return &MemoryLogStorage{
+		entries: make([]LogEntry, maxEntries),
+		nextID:  1,
+	}
+}
+
+func (m *MemoryLogStorage) AddEntry(timestamp uint64) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entryID := m.nextID
+	m.push(LogEntry{EntryID: entryID, Timestamp: timestamp})
+	return entryID, nil
+}
+
+func (m *MemoryLogStorage) AddEntryData(entryID uint64, data LogEntryData) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := m.entry(entryID)
+	if entry == nil {
+		return fmt.Errorf("AddEntryData %d: no such entry", entryID)
+	}
+	entry.Data = append(entry.Data, data)
+	return nil
+}
+
+func (m *MemoryLogStorage) GetEntries(startingTime, endingTime uint64, fn func(entry LogEntry) bool) error {
+	entries := m.selectEntries(func(entry *LogEntry) bool {
+		return entry.Timestamp >= startingTime && (endingTime == 0 || entry.Timestamp <= endingTime)
+	})
+	for _, entry := range entries {
+		if !fn(entry) {
+			break
+		}
+	}
+	return nil
+}
+
+func (m *MemoryLogStorage) GetEntriesAfter(startingTime, entryID uint64, fn func(entry LogEntry) bool) error {
+	entries := m.selectEntries(func(entry *LogEntry) bool {
+		return entry.EntryID > entryID && entry.Timestamp >= startingTime
+	})
+	for _, entry := range entries {
+		if !fn(entry) {
+			break
+		}
+	}
+	return nil
+}
+
+func (m *MemoryLogStorage) GetOldestAndNewestEntries() (oldest, newest LogEntry, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.count == 0 {
+		return LogEntry{}, LogEntry{}, false
+	}
+	oldest = m.entries[m.first]
+	newest = m.entries[(m.first+m.count-1)%len(m.entries)]
+	return LogEntry{EntryID: oldest.EntryID, Timestamp: oldest.Timestamp},
+		LogEntry{EntryID: newest.EntryID, Timestamp: newest.Timestamp}, true
+}
+
+// push appends the entry and overwrites the oldest entry when the storage is full, it returns true
+// when an entry was dropped. Entry IDs have to be consecutive.
+func (m *MemoryLogStorage) push(entry LogEntry) bool {
+	m.nextID = entry.EntryID + 1
+	if m.count < len(m.entries) {
+		m.entries[(m.first+m.count)%len(m.entries)] = entry
+		m.count++
+		return false
+	}
+	m.entries[m.first] = entry
+	m.first = (m.first + 1) % len(m.entries)
+	return true
+}
+
+// entry returns the stored entry with the ID, or nil.
+func (m *MemoryLogStorage) entry(entryID uint64) *LogEntry {
+	if m.count == 0 {
+		return nil
+	}
+	oldestID := m.entries[m.first].EntryID
+	if entryID < oldestID || entryID-oldestID >= uint64(m.count) {
+		return nil
+	}
+	return &m.entries[(m.first+int(entryID-oldestID))%len(m.entries)]
+}
+
+// selectEntries copies the matching entries, so queries don't block new entries.
+func (m *MemoryLogStorage) selectEntries(match func(entry *LogEntry) bool) []LogEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var entries []LogEntry
+	for i := 0; i < m.count; i++ {
+		entry := &m.entries[(m.first+i)%len(m.entries)]
+		if match(entry) {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries
+}
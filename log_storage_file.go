@@ -0,0 +1,225 @@
+package iec61850
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// records of the log file
+const (
+	logRecordEntry = 'E' // entryID, timestamp
+	logRecordData  = 'D' // entryID, reasonCode, dataRef, data
+)
+
+// FileLogStorage keeps the newest entries of a log like MemoryLogStorage and appends them to a file,
+// so the log survives a restart of the server. The file is compacted when the dropped entries reach
+// the capacity.
+type FileLogStorage struct {
+	mu      sync.Mutex
+	name    string
+	file    *os.File
+	memory  *MemoryLogStorage
+	dropped int // entries in the file which are no longer in memory
+}
+
+// NewFileLogStorage opens the log file, which is created if it doesn't exist, for maxEntries
+// entries, DEFAULT_MAX_LOG_ENTRIES when 0.
+func NewFileLogStorage(name string, maxEntries int) (*FileLogStorage, error) {
+	file, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("NewFileLogStorage %q: %w", name, err)
+	}
+	f := &FileLogStorage{
+		name:   name,
+		file:   file,
+		memory: NewMemoryLogStorage(maxEntries),
+	}
+	if err := f.load(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("NewFileLogStorage %q: %w", name, err)
+	}
+	return f, nil
+}
+
+// load reads the entries of the file. An incomplete last record, when the process stopped while
+// writing, is removed. Unknown and inconsistent records fail with CorruptLogFile and the file is
+// left unchanged.
+func (f *FileLogStorage) load() error {
+	data, err := io.ReadAll(f.file)
+	if err != nil {
+		return err
+	}
+	pos := 0
+	for pos < len(data) {
+		n, err := f.replay(data[pos:])
+		if err != nil {
+			return fmt.Errorf("record at offset %d: %w", pos, err)
+		}
+		if n == 0 {
+			// the record exceeds the file, so it is the last one
+			return f.file.Truncate(int64(pos))
+		}
+		pos += n
+	}
+	return nil
+}
+
+// replay adds the record at the beginning of data to the memory and returns its length, 0 if the
+// record is incomplete.
+func (f *FileLogStorage) replay(data []byte) (int, error) {
+	m := f.memory
+	switch data[0] {
+	case logRecordEntry:
+		if len(data) < 17 {
+			return 0, nil
+		}
+		entry := LogEntry{EntryID: binary.BigEndian.Uint64(data[1:]), Timestamp: binary.BigEndian.Uint64(data[9:])}
+		// entry IDs are consecutive, the first one of the file follows the compacted entries
+		if m.count > 0 && entry.EntryID != m.nextID {
+			return 0, fmt.Errorf("entry %d after entry %d: %w", entry.EntryID, m.nextID-1, CorruptLogFile)
+		}
+		if m.push(entry) {
+			f.dropped++
+		}
+		return 17, nil
+	case logRecordData:
+		if len(data) < 12 {
+			return 0, nil
+		}
+		refEnd := 12 + int(binary.BigEndian.Uint16(data[10:]))
+		if len(data) < refEnd+4 {
+			return 0, nil
+		}
+		dataEnd := refEnd + 4 + int(binary.BigEndian.Uint32(data[refEnd:]))
+		if len(data) < dataEnd {
+			return 0, nil
+		}
+		entryID := binary.BigEndian.Uint64(data[1:])
+		if m.count == 0 || entryID >= m.nextID {
+			return 0, fmt.Errorf("data of entry %d before the entry: %w", entryID, CorruptLogFile)
+		}
+		// the entry may be dropped already
+		if entry := m.entry(entryID); entry != nil {
+			entry.Data = append(entry.Data, LogEntryData{
+				DataRef:    string(data[12:refEnd]),
+				Data:       append([]byte(nil), data[refEnd+4:dataEnd]...),
+				ReasonCode: data[9],
+			})
+		}
+		return dataEnd, nil
+	}
+	return 0, fmt.Errorf("unknown record type 0x%02x: %w", data[0], CorruptLogFile)
+}
+
+func appendLogEntryRecord(b []byte, entry LogEntry) []byte {
+	b = append(b, logRecordEntry)
+	b = binary.BigEndian.AppendUint64(b, entry.EntryID)
+	return binary.BigEndian.AppendUint64(b, entry.Timestamp)
+}
+
+func appendLogDataRecord(b []byte, entryID uint64, data LogEntryData) []byte {
+	b = append(b, logRecordData)
+	b = binary.BigEndian.AppendUint64(b, entryID)
+	b = append(b, data.ReasonCode)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(data.DataRef)))
+	b = append(b, data.DataRef...)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(data.Data)))
+	return append(b, data.Data...)
+}
+
+func (f *FileLogStorage) AddEntry(timestamp uint64) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return 0, fmt.Errorf("AddEntry: %w", os.ErrClosed)
+	}
+
+	m := f.memory
+	m.mu.Lock()
+	entry := LogEntry{EntryID: m.nextID, Timestamp: timestamp}
+	if m.push(entry) {
+		f.dropped++
+	}
+	m.mu.Unlock()
+
+	if _, err := f.file.Write(appendLogEntryRecord(nil, entry)); err != nil {
+		return 0, fmt.Errorf("AddEntry: %w", err)
+	}
+	if f.dropped >= len(m.entries) {
+		if err := f.compact(); err != nil {
+			return 0, fmt.Errorf("AddEntry: %w", err)
+		}
+	}
+	return entry.EntryID, nil
+}
+
+func (f *FileLogStorage) AddEntryData(entryID uint64, data LogEntryData) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return fmt.Errorf("AddEntryData: %w", os.ErrClosed)
+	}
+	if err := f.memory.AddEntryData(entryID, data); err != nil {
+		return err
+	}
+	if _, err := f.file.Write(appendLogDataRecord(nil, entryID, data)); err != nil {
+		return fmt.Errorf("AddEntryData: %w", err)
+	}
+	return nil
+}
+
+func (f *FileLogStorage) GetEntries(startingTime, endingTime uint64, fn func(entry LogEntry) bool) error {
+	return f.memory.GetEntries(startingTime, endingTime, fn)
+}
+
+func (f *FileLogStorage) GetEntriesAfter(startingTime, entryID uint64, fn func(entry LogEntry) bool) error {
+	return f.memory.GetEntriesAfter(startingTime, entryID, fn)
+}
+
+func (f *FileLogStorage) GetOldestAndNewestEntries() (oldest, newest LogEntry, ok bool) {
+	return f.memory.GetOldestAndNewestEntries()
+}
+
+// compact replaces the file by a file with the entries in memory.
+func (f *FileLogStorage) compact() error {
+	var b []byte
+	for _, entry := range f.memory.selectEntries(func(entry *LogEntry) bool { return true }) {
+		b = appendLogEntryRecord(b, entry)
+		for _, data := range entry.Data {
+			b = appendLogDataRecord(b, entry.EntryID, data)
+		}
+	}
+	tmpName := f.name + ".tmp"
+	if err := os.WriteFile(tmpName, b, 0o644); err != nil {
+		return err
+	}
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+	if err := os.Rename(tmpName, f.name); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.name, os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	f.file = file
+	f.dropped = 0
+	return nil
+}
+
+// Close closes the file, the storage must not be used by a server anymore.
+func (f *FileLogStorage) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
@@ -0,0 +1,116 @@
+package iec61850
+
+/*
+#include <tls_config.h>
+
+extern void tlsEventHandlerBridge(void* parameter, TLSEventLevel eventLevel, int eventCode, char* message, TLSConnection con);
+*/
+import "C"
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"unsafe"
+)
+
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// logger returns the configured logger of the client, or a logger discarding everything.
+func (c *Client) logger() *slog.Logger {
+	if c.settings.Logger != nil {
+		return c.settings.Logger
+	}
+	return discardLogger
+}
+
+// logger returns the configured logger of the server, or a logger discarding everything.
+func (is *IedServer) logger() *slog.Logger {
+	if is.serverConfig.Logger != nil {
+		return is.serverConfig.Logger
+	}
+	return discardLogger
+}
+
+// Progress reports the advance of a long running discovery operation like GetDataModel.
+type Progress struct {
+	Operation string // name of the client method
+	Done      int
+	Total     int
+	Ref       string // reference of the last finished item
+}
+
+// progressReporter counts finished items and calls the progress callback of the client in order.
+type progressReporter struct {
+	mu        sync.Mutex
+	fn        func(Progress)
+	operation string
+	done      int
+	total     int
+}
+
+func (c *Client) newProgress(operation string, total int) *progressReporter {
+	return &progressReporter{fn: c.settings.Progress, operation: operation, total: total}
+}
+
+func (p *progressReporter) step(ref string) {
+	if p.fn == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.fn(Progress{Operation: p.operation, Done: p.done, Total: p.total, Ref: ref})
+}
+
+// TLS events of libiec61850 are logged to the logger of the client or server owning the TLS configuration.
+// Loggers are registered once and never removed, applications typically use very few of them.
+var (
+	tlsEventLoggersMu sync.RWMutex
+	tlsEventLoggers   = make(map[int32]*slog.Logger)
+	tlsEventLoggerIds = make(map[*slog.Logger]int32)
+)
+
+func setTLSEventLogger(tlsConfig C.TLSConfiguration, logger *slog.Logger) {
+	if logger == nil {
+		return
+	}
+	tlsEventLoggersMu.Lock()
+	callbackId, ok := tlsEventLoggerIds[logger]
+	if !ok {
+		callbackId = callbackIdGen.Add(1)
+		tlsEventLoggerIds[logger] = callbackId
+		tlsEventLoggers[callbackId] = logger
+	}
+	tlsEventLoggersMu.Unlock()
+
+	cPtr := intToPointerBug58625(callbackId)
+	C.TLSConfiguration_setEventHandler(tlsConfig, (*[0]byte)(C.tlsEventHandlerBridge), cPtr)
+}
+
+//export tlsEventHandlerBridge
+func tlsEventHandlerBridge(parameter unsafe.Pointer, eventLevel C.TLSEventLevel, eventCode C.int, message *C.char, con C.TLSConnection) {
+	callbackId := int32(uintptr(parameter))
+	tlsEventLoggersMu.RLock()
+	logger, ok := tlsEventLoggers[callbackId]
+	tlsEventLoggersMu.RUnlock()
+	if !ok {
+		return
+	}
+
+	level := slog.LevelInfo
+	switch eventLevel {
+	case C.TLS_SEC_EVT_WARNING:
+		level = slog.LevelWarn
+	case C.TLS_SEC_EVT_INCIDENT:
+		level = slog.LevelError
+	}
+
+	attrs := []any{"code", int(eventCode)}
+	if con != nil {
+		var peerAddress [64]C.char
+		C.TLSConnection_getPeerAddress(con, &peerAddress[0])
+		attrs = append(attrs, "peer", C.GoString(&peerAddress[0]), "tlsVersion", int(C.TLSConnection_getTLSVersion(con)))
+	}
+	logger.Log(context.Background(), level, "TLS event: "+C.GoString(message), attrs...)
+}
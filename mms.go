@@ -10,6 +10,13 @@ import (
 )
 
 func toMmsValue(mmsType MmsType, value interface{}) (*C.MmsValue, error) {
+	return toMmsValueLike(mmsType, value, nil)
+}
+
+// toMmsValueLike creates the value with the sizes of like, the current value of the target. Bit strings
+// take the size of like, the elements of structures and arrays the sizes of its elements. Bit strings
+// without target have 32 bits.
+func toMmsValueLike(mmsType MmsType, value interface{}, like *C.MmsValue) (*C.MmsValue, error) {
 	var (
 		mmsValue *C.MmsValue
 		err      error
@@ -60,11 +67,53 @@ func toMmsValue(mmsType MmsType, value interface{}) (*C.MmsValue, error) {
 		if err != nil {
 			return nil, err
 		}
-	case Int64:
+	case Int64, Integer:
 		mmsValue, err = toInt64MmsValue(value)
 		if err != nil {
 			return nil, err
 		}
+	case Unsigned:
+		mmsValue, err = toUint32MmsValue(value)
+		if err != nil {
+			return nil, err
+		}
+	case VisibleString:
+		mmsValue, err = toVisibleStringMmsValue(value)
+		if err != nil {
+			return nil, err
+		}
+	case OctetString:
+		mmsValue, err = toOctetStringMmsValue(value)
+		if err != nil {
+			return nil, err
+		}
+	case BitString:
+		size := 32
+		if like != nil && C.MmsValue_getType(like) == C.MMS_BIT_STRING {
+			size = int(C.MmsValue_getBitStringSize(like))
+		}
+		mmsValue, err = toBitStringMmsValue(value, size)
+		if err != nil {
+			return nil, err
+		}
+	case UTCTime:
+		v, err := cast.ToUint32E(value)
+		if err != nil {
+			return nil, err
+		}
+		mmsValue = C.MmsValue_newUtcTime(C.uint32_t(v))
+	case BinaryTime:
+		v, err := cast.ToUint64E(value)
+		if err != nil {
+			return nil, err
+		}
+		mmsValue = C.MmsValue_newBinaryTime(C.bool(false))
+		C.MmsValue_setBinaryTime(mmsValue, C.uint64_t(v))
+	case Structure, Array:
+		mmsValue, err = toStructureMmsValue(mmsType, value, like)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, UnSupportedOperation
 	}
@@ -238,3 +287,68 @@ func toStringMmsValue(value interface{}) (*C.MmsValue, error) {
 	mmsValue := C.MmsValue_newMmsString(stringValue)
 	return mmsValue, nil
 }
+
+func toVisibleStringMmsValue(value interface{}) (*C.MmsValue, error) {
+	v, err := cast.ToStringE(value)
+	if err != nil {
+		return nil, err
+	}
+	stringValue := C.CString(v)
+	defer C.free(unsafe.Pointer(stringValue))
+	return C.MmsValue_newVisibleString(stringValue), nil
+}
+
+func toOctetStringMmsValue(value interface{}) (*C.MmsValue, error) {
+	v, ok := value.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("octet string requires []byte, got %T", value)
+	}
+	mmsValue := C.MmsValue_newOctetString(0, C.int(len(v)))
+	if len(v) > 0 {
+		C.MmsValue_setOctetString(mmsValue, (*C.uint8_t)(unsafe.Pointer(&v[0])), C.int(len(v)))
+	}
+	return mmsValue, nil
+}
+
+// toBitStringMmsValue creates a bit string of size bits, bit 0 is the least significant bit of value.
+func toBitStringMmsValue(value interface{}, size int) (*C.MmsValue, error) {
+	v, err := cast.ToUint32E(value)
+	if err != nil {
+		return nil, err
+	}
+	mmsValue := C.MmsValue_newBitString(C.int(size))
+	C.MmsValue_setBitStringFromInteger(mmsValue, C.uint32_t(v))
+	return mmsValue, nil
+}
+
+// toStructureMmsValue creates a structure or array of the elements of value, as returned by toGoStructure.
+// The elements take the sizes of the elements of like.
+func toStructureMmsValue(mmsType MmsType, value interface{}, like *C.MmsValue) (*C.MmsValue, error) {
+	elements, ok := value.([]*MmsValue)
+	if !ok {
+		return nil, fmt.Errorf("structure requires []*MmsValue, got %T", value)
+	}
+	var mmsValue *C.MmsValue
+	if mmsType == Array {
+		mmsValue = C.MmsValue_createEmptyArray(C.int(len(elements)))
+	} else {
+		mmsValue = C.MmsValue_createEmptyStructure(C.int(len(elements)))
+	}
+	for i, element := range elements {
+		if element == nil {
+			C.MmsValue_delete(mmsValue)
+			return nil, fmt.Errorf("element %d is nil", i)
+		}
+		var elementLike *C.MmsValue
+		if like != nil && C.MmsValue_getType(like) == C.MmsValue_getType(mmsValue) && i < int(C.MmsValue_getArraySize(like)) {
+			elementLike = C.MmsValue_getElement(like, C.int(i))
+		}
+		elementValue, err := toMmsValueLike(element.Type, element.Value, elementLike)
+		if err != nil {
+			C.MmsValue_delete(mmsValue)
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		C.MmsValue_setElement(mmsValue, C.int(i), elementValue)
+	}
+	return mmsValue, nil
+}
@@ -5,8 +5,12 @@ package iec61850
 import "C"
 
 import (
+	"fmt"
 	"os"
+	"time"
 	"unsafe"
+
+	"github.com/spf13/cast"
 )
 
 type IedModel struct {
@@ -110,43 +114,6 @@ type DataObject struct {
 	object *C.DataObject
 }
 
-// ENS: EnumerationString
-// VSS: Visible String Setting
-// SAV: Sampled Value
-// APC: Analogue Process Control
-
-func (n *LogicalNode) CreateDataObjectCDC_ENS(name string) *DataObject {
-	cname := C.CString(name)
-	defer C.free(unsafe.Pointer(cname))
-	return &DataObject{
-		object: C.CDC_ENS_create(cname, (*C.ModelNode)(n.node), 0),
-	}
-}
-
-func (n *LogicalNode) CreateDataObjectCDC_VSS(name string) *DataObject {
-	cname := C.CString(name)
-	defer C.free(unsafe.Pointer(cname))
-	return &DataObject{
-		object: C.CDC_VSS_create(cname, (*C.ModelNode)(n.node), 0),
-	}
-}
-
-func (n *LogicalNode) CreateDataObjectCDC_SAV(name string, isInteger bool) *DataObject {
-	cname := C.CString(name)
-	defer C.free(unsafe.Pointer(cname))
-	return &DataObject{
-		object: C.CDC_SAV_create(cname, (*C.ModelNode)(n.node), 0, C.bool(isInteger)),
-	}
-}
-
-func (n *LogicalNode) CreateDataObjectCDC_APC(name string, ctlModel int) *DataObject {
-	cname := C.CString(name)
-	defer C.free(unsafe.Pointer(cname))
-	return &DataObject{
-		object: C.CDC_APC_create(cname, (*C.ModelNode)(n.node), 0, C.uint(ctlModel), C.bool(false)),
-	}
-}
-
 type DataAttribute struct {
 	attribute *C.DataAttribute
 }
@@ -185,3 +152,262 @@ func (ds *DataSet) AddDataSetEntry(ref string) {
 
 	C.DataSetEntry_create(ds.dataSet, cRef, -1, nil)
 }
+
+// DataAttributeType is the IEC 61850 type of a data attribute in the server data model.
+// Values must match the C enum ordering.
+type DataAttributeType int
+
+const (
+	DA_TYPE_BOOLEAN DataAttributeType = iota
+	DA_TYPE_INT8
+	DA_TYPE_INT16
+	DA_TYPE_INT32
+	DA_TYPE_INT64
+	DA_TYPE_INT128
+	DA_TYPE_INT8U
+	DA_TYPE_INT16U
+	DA_TYPE_INT24U
+	DA_TYPE_INT32U
+	DA_TYPE_FLOAT32
+	DA_TYPE_FLOAT64
+	DA_TYPE_ENUMERATED
+	DA_TYPE_OCTET_STRING_64
+	DA_TYPE_OCTET_STRING_6
+	DA_TYPE_OCTET_STRING_8
+	DA_TYPE_VISIBLE_STRING_32
+	DA_TYPE_VISIBLE_STRING_64
+	DA_TYPE_VISIBLE_STRING_65
+	DA_TYPE_VISIBLE_STRING_129
+	DA_TYPE_VISIBLE_STRING_255
+	DA_TYPE_UNICODE_STRING_255
+	DA_TYPE_TIMESTAMP
+	DA_TYPE_QUALITY
+	DA_TYPE_CHECK
+	DA_TYPE_CODEDENUM
+	DA_TYPE_GENERIC_BITSTRING
+	DA_TYPE_CONSTRUCTED
+	DA_TYPE_ENTRY_TIME
+	DA_TYPE_PHYCOMADDR
+	DA_TYPE_CURRENCY
+	DA_TYPE_OPTFLDS
+	DA_TYPE_TRGOPS
+)
+
+// CreateDataObject creates a data object without predefined attributes, arrayElements is 0 for non-array objects.
+func (n *LogicalNode) CreateDataObject(name string, arrayElements int) *DataObject {
+	return createDataObject(name, (*C.ModelNode)(unsafe.Pointer(n.node)), arrayElements)
+}
+
+// CreateDataObject creates a sub data object.
+func (do *DataObject) CreateDataObject(name string, arrayElements int) *DataObject {
+	return createDataObject(name, (*C.ModelNode)(unsafe.Pointer(do.object)), arrayElements)
+}
+
+func createDataObject(name string, parent *C.ModelNode, arrayElements int) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.DataObject_create(cname, parent, C.int(arrayElements)),
+	}
+}
+
+// CreateDataAttribute creates a data attribute of the data object.
+// Only the DataChange, QualityChange, DataUpdate and Transient trigger options apply to data attributes.
+// Array attributes of constructed type get their sub attributes once, they describe every element.
+func (do *DataObject) CreateDataAttribute(name string, daType DataAttributeType, fc FC, trgOps TrgOps, arrayElements int, sAddr uint32) *DataAttribute {
+	return createDataAttribute(name, (*C.ModelNode)(unsafe.Pointer(do.object)), daType, fc, trgOps, arrayElements, sAddr)
+}
+
+// CreateDataAttribute creates a sub attribute of a constructed data attribute.
+func (da *DataAttribute) CreateDataAttribute(name string, daType DataAttributeType, fc FC, trgOps TrgOps, arrayElements int, sAddr uint32) *DataAttribute {
+	return createDataAttribute(name, (*C.ModelNode)(unsafe.Pointer(da.attribute)), daType, fc, trgOps, arrayElements, sAddr)
+}
+
+func createDataAttribute(name string, parent *C.ModelNode, daType DataAttributeType, fc FC, trgOps TrgOps, arrayElements int, sAddr uint32) *DataAttribute {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataAttribute{
+		attribute: C.DataAttribute_create(cname, parent, C.DataAttributeType(daType), C.FunctionalConstraint(fc),
+			C.uint8_t(trgOps.bits()), C.int(arrayElements), C.uint32_t(sAddr)),
+	}
+}
+
+// bits returns the trigger options as libiec61850 TRG_OPT_* bit mask.
+func (t TrgOps) bits() uint8 {
+	var bits uint8
+	if t.DataChange {
+		bits |= C.TRG_OPT_DATA_CHANGED
+	}
+	if t.QualityChange {
+		bits |= C.TRG_OPT_QUALITY_CHANGED
+	}
+	if t.DataUpdate {
+		bits |= C.TRG_OPT_DATA_UPDATE
+	}
+	if t.TriggeredPeriodically {
+		bits |= C.TRG_OPT_INTEGRITY
+	}
+	if t.Gi {
+		bits |= C.TRG_OPT_GI
+	}
+	if t.Transient {
+		bits |= C.TRG_OPT_TRANSIENT
+	}
+	return bits
+}
+
+// trgOpsFromServerBits converts a libiec61850 TRG_OPT_* bit mask of the server model, the reverse of bits.
+func trgOpsFromServerBits(bits uint8) TrgOps {
+	return TrgOps{
+		DataChange:            bits&C.TRG_OPT_DATA_CHANGED != 0,
+		QualityChange:         bits&C.TRG_OPT_QUALITY_CHANGED != 0,
+		DataUpdate:            bits&C.TRG_OPT_DATA_UPDATE != 0,
+		TriggeredPeriodically: bits&C.TRG_OPT_INTEGRITY != 0,
+		Gi:                    bits&C.TRG_OPT_GI != 0,
+		Transient:             bits&C.TRG_OPT_TRANSIENT != 0,
+	}
+}
+
+// GetType returns the IEC 61850 type of the data attribute.
+func (da *DataAttribute) GetType() DataAttributeType {
+	return DataAttributeType(C.DataAttribute_getType(da.attribute))
+}
+
+// SetValue sets the initial value of a basic data attribute while the model is built.
+// The value is converted according to the attribute type: integers for INT*, ENUMERATED and CODEDENUM,
+// float32/float64 for FLOAT*, strings for VISIBLE_STRING_*, UNICODE_STRING_255 and CURRENCY,
+// []byte for OCTET_STRING_*, Quality or an integer for QUALITY and time.Time or milliseconds since epoch
+// for TIMESTAMP and ENTRY_TIME.
+// Use the Update methods of IedServer to change values of a running server.
+func (da *DataAttribute) SetValue(value any) error {
+	mmsValue, err := newDataAttributeValue(da.GetType(), value)
+	if err != nil {
+		return fmt.Errorf("SetValue %v: %w", value, err)
+	}
+	defer C.MmsValue_delete(mmsValue)
+	C.DataAttribute_setValue(da.attribute, mmsValue)
+	return nil
+}
+
+func newDataAttributeValue(daType DataAttributeType, value any) (*C.MmsValue, error) {
+	switch daType {
+	case DA_TYPE_BOOLEAN:
+		v, err := cast.ToBoolE(value)
+		if err != nil {
+			return nil, err
+		}
+		return C.MmsValue_newBoolean(C.bool(v)), nil
+	case DA_TYPE_INT8, DA_TYPE_INT16, DA_TYPE_INT32, DA_TYPE_ENUMERATED:
+		v, err := cast.ToInt32E(value)
+		if err != nil {
+			return nil, err
+		}
+		return C.MmsValue_newIntegerFromInt32(C.int32_t(v)), nil
+	case DA_TYPE_INT64:
+		v, err := cast.ToInt64E(value)
+		if err != nil {
+			return nil, err
+		}
+		return C.MmsValue_newIntegerFromInt64(C.int64_t(v)), nil
+	case DA_TYPE_INT8U, DA_TYPE_INT16U, DA_TYPE_INT24U, DA_TYPE_INT32U:
+		v, err := cast.ToUint32E(value)
+		if err != nil {
+			return nil, err
+		}
+		return C.MmsValue_newUnsignedFromUint32(C.uint32_t(v)), nil
+	case DA_TYPE_FLOAT32:
+		v, err := cast.ToFloat32E(value)
+		if err != nil {
+			return nil, err
+		}
+		return C.MmsValue_newFloat(C.float(v)), nil
+	case DA_TYPE_FLOAT64:
+		v, err := cast.ToFloat64E(value)
+		if err != nil {
+			return nil, err
+		}
+		return C.MmsValue_newDouble(C.double(v)), nil
+	case DA_TYPE_VISIBLE_STRING_32, DA_TYPE_VISIBLE_STRING_64, DA_TYPE_VISIBLE_STRING_65, DA_TYPE_VISIBLE_STRING_129,
+		DA_TYPE_VISIBLE_STRING_255, DA_TYPE_CURRENCY:
+		v, err := cast.ToStringE(value)
+		if err != nil {
+			return nil, err
+		}
+		cv := C.CString(v)
+		defer C.free(unsafe.Pointer(cv))
+		return C.MmsValue_newVisibleString(cv), nil
+	case DA_TYPE_UNICODE_STRING_255:
+		v, err := cast.ToStringE(value)
+		if err != nil {
+			return nil, err
+		}
+		cv := C.CString(v)
+		defer C.free(unsafe.Pointer(cv))
+		return C.MmsValue_newMmsString(cv), nil
+	case DA_TYPE_OCTET_STRING_64, DA_TYPE_OCTET_STRING_6, DA_TYPE_OCTET_STRING_8:
+		v, ok := value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("octet string requires []byte, got %T", value)
+		}
+		maxSize := map[DataAttributeType]int{DA_TYPE_OCTET_STRING_64: 64, DA_TYPE_OCTET_STRING_6: 6, DA_TYPE_OCTET_STRING_8: 8}[daType]
+		if len(v) > maxSize {
+			return nil, fmt.Errorf("octet string of %d bytes exceeds %d bytes", len(v), maxSize)
+		}
+		mmsValue := C.MmsValue_newOctetString(0, C.int(maxSize))
+		if len(v) > 0 {
+			C.MmsValue_setOctetString(mmsValue, (*C.uint8_t)(unsafe.Pointer(&v[0])), C.int(len(v)))
+		}
+		return mmsValue, nil
+	case DA_TYPE_CODEDENUM:
+		v, err := cast.ToUint32E(value)
+		if err != nil {
+			return nil, err
+		}
+		mmsValue := C.MmsValue_newBitString(2)
+		C.MmsValue_setBitStringFromIntegerBigEndian(mmsValue, C.uint32_t(v))
+		return mmsValue, nil
+	case DA_TYPE_QUALITY:
+		if q, ok := value.(Quality); ok {
+			value = uint16(q)
+		}
+		v, err := cast.ToUint32E(value)
+		if err != nil {
+			return nil, err
+		}
+		mmsValue := C.MmsValue_newBitString(13)
+		C.MmsValue_setBitStringFromInteger(mmsValue, C.uint32_t(v))
+		return mmsValue, nil
+	case DA_TYPE_TIMESTAMP, DA_TYPE_ENTRY_TIME:
+		if t, ok := value.(time.Time); ok {
+			value = t.UnixMilli()
+		}
+		v, err := cast.ToUint64E(value)
+		if err != nil {
+			return nil, err
+		}
+		if daType == DA_TYPE_ENTRY_TIME {
+			mmsValue := C.MmsValue_newBinaryTime(C.bool(false))
+			C.MmsValue_setBinaryTime(mmsValue, C.uint64_t(v))
+			return mmsValue, nil
+		}
+		return C.MmsValue_newUtcTimeByMsTime(C.uint64_t(v)), nil
+	default:
+		return nil, fmt.Errorf("unsupported data attribute type %d", daType)
+	}
+}
+
+// AddDataSetEntryEx adds a data set member given as MMS variable name like "GGIO1$ST$Ind1", relative to the
+// logical device of the data set or prefixed by another one as "GenericIO/GGIO1$ST$Ind1", optionally selecting
+// an array element and a component of it.
+// index is -1 and component empty to refer to the variable itself.
+func (ds *DataSet) AddDataSetEntryEx(variable string, index int, component string) {
+	cVariable := C.CString(variable)
+	defer C.free(unsafe.Pointer(cVariable))
+
+	var cComponent *C.char
+	if component != "" {
+		cComponent = C.CString(component)
+		defer C.free(unsafe.Pointer(cComponent))
+	}
+	C.DataSetEntry_create(ds.dataSet, cVariable, C.int(index), cComponent)
+}
@@ -0,0 +1,454 @@
+package iec61850
+
+// #include <iec61850_dynamic_model.h>
+import "C"
+
+import "unsafe"
+
+// CDCOption selects optional data attributes of data objects created from common data classes.
+// Options not defined for a CDC are ignored.
+type CDCOption uint32
+
+const (
+	CDC_OPTION_PICS_SUBST      CDCOption = C.CDC_OPTION_PICS_SUBST
+	CDC_OPTION_BLK_ENA         CDCOption = C.CDC_OPTION_BLK_ENA
+	CDC_OPTION_DESC            CDCOption = C.CDC_OPTION_DESC         // d (description)
+	CDC_OPTION_DESC_UNICODE    CDCOption = C.CDC_OPTION_DESC_UNICODE // dU (unicode description)
+	CDC_OPTION_AC_DLNDA        CDCOption = C.CDC_OPTION_AC_DLNDA     // cdcNs and cdcName of extended CDCs
+	CDC_OPTION_AC_DLN          CDCOption = C.CDC_OPTION_AC_DLN       // dataNs of extended CDCs
+	CDC_OPTION_UNIT            CDCOption = C.CDC_OPTION_UNIT
+	CDC_OPTION_FROZEN_VALUE    CDCOption = C.CDC_OPTION_FROZEN_VALUE
+	CDC_OPTION_ADDR            CDCOption = C.CDC_OPTION_ADDR
+	CDC_OPTION_ADDINFO         CDCOption = C.CDC_OPTION_ADDINFO
+	CDC_OPTION_INST_MAG        CDCOption = C.CDC_OPTION_INST_MAG
+	CDC_OPTION_RANGE           CDCOption = C.CDC_OPTION_RANGE
+	CDC_OPTION_UNIT_MULTIPLIER CDCOption = C.CDC_OPTION_UNIT_MULTIPLIER
+	CDC_OPTION_AC_SCAV         CDCOption = C.CDC_OPTION_AC_SCAV
+	CDC_OPTION_MIN             CDCOption = C.CDC_OPTION_MIN
+	CDC_OPTION_MAX             CDCOption = C.CDC_OPTION_MAX
+	CDC_OPTION_AC_CLC_O        CDCOption = C.CDC_OPTION_AC_CLC_O
+	CDC_OPTION_RANGE_ANG       CDCOption = C.CDC_OPTION_RANGE_ANG
+	CDC_OPTION_PHASE_A         CDCOption = C.CDC_OPTION_PHASE_A
+	CDC_OPTION_PHASE_B         CDCOption = C.CDC_OPTION_PHASE_B
+	CDC_OPTION_PHASE_C         CDCOption = C.CDC_OPTION_PHASE_C
+	CDC_OPTION_PHASE_NEUT      CDCOption = C.CDC_OPTION_PHASE_NEUT
+	CDC_OPTION_PHASES_ABC      CDCOption = C.CDC_OPTION_PHASES_ABC
+	CDC_OPTION_PHASES_ALL      CDCOption = C.CDC_OPTION_PHASES_ALL
+	CDC_OPTION_STEP_SIZE       CDCOption = C.CDC_OPTION_STEP_SIZE
+	CDC_OPTION_ANGLE_REF       CDCOption = C.CDC_OPTION_ANGLE_REF
+
+	// options only valid for DPL
+	CDC_OPTION_DPL_HWREV    CDCOption = C.CDC_OPTION_DPL_HWREV
+	CDC_OPTION_DPL_SWREV    CDCOption = C.CDC_OPTION_DPL_SWREV
+	CDC_OPTION_DPL_SERNUM   CDCOption = C.CDC_OPTION_DPL_SERNUM
+	CDC_OPTION_DPL_MODEL    CDCOption = C.CDC_OPTION_DPL_MODEL
+	CDC_OPTION_DPL_LOCATION CDCOption = C.CDC_OPTION_DPL_LOCATION
+
+	// mandatory data attributes for LLN0, like configRev of LPL
+	CDC_OPTION_AC_LN0_M  CDCOption = C.CDC_OPTION_AC_LN0_M
+	CDC_OPTION_AC_LN0_EX CDCOption = C.CDC_OPTION_AC_LN0_EX
+	CDC_OPTION_AC_DLD_M  CDCOption = C.CDC_OPTION_AC_DLD_M
+)
+
+// CDC61400Option selects optional data attributes of the IEC 61400-25 common data classes.
+type CDC61400Option uint32
+
+const (
+	CDC_OPTION_61400_MIN_MX_VAL       CDC61400Option = C.CDC_OPTION_61400_MIN_MX_VAL
+	CDC_OPTION_61400_MAX_MX_VAL       CDC61400Option = C.CDC_OPTION_61400_MAX_MX_VAL
+	CDC_OPTION_61400_TOT_AV_VAL       CDC61400Option = C.CDC_OPTION_61400_TOT_AV_VAL
+	CDC_OPTION_61400_SDV_VAL          CDC61400Option = C.CDC_OPTION_61400_SDV_VAL
+	CDC_OPTION_61400_INC_RATE         CDC61400Option = C.CDC_OPTION_61400_INC_RATE
+	CDC_OPTION_61400_DEC_RATE         CDC61400Option = C.CDC_OPTION_61400_DEC_RATE
+	CDC_OPTION_61400_SP_ACS           CDC61400Option = C.CDC_OPTION_61400_SP_ACS
+	CDC_OPTION_61400_CHA_PER_RS       CDC61400Option = C.CDC_OPTION_61400_CHA_PER_RS
+	CDC_OPTION_61400_CM_ACS           CDC61400Option = C.CDC_OPTION_61400_CM_ACS
+	CDC_OPTION_61400_TM_TOT           CDC61400Option = C.CDC_OPTION_61400_TM_TOT
+	CDC_OPTION_61400_COUNTING_DAILY   CDC61400Option = C.CDC_OPTION_61400_COUNTING_DAILY
+	CDC_OPTION_61400_COUNTING_MONTHLY CDC61400Option = C.CDC_OPTION_61400_COUNTING_MONTHLY
+	CDC_OPTION_61400_COUNTING_YEARLY  CDC61400Option = C.CDC_OPTION_61400_COUNTING_YEARLY
+	CDC_OPTION_61400_COUNTING_TOTAL   CDC61400Option = C.CDC_OPTION_61400_COUNTING_TOTAL
+	CDC_OPTION_61400_COUNTING_ALL     CDC61400Option = C.CDC_OPTION_61400_COUNTING_ALL
+)
+
+// CDCControlOption selects optional data attributes of controllable data objects.
+type CDCControlOption uint32
+
+const (
+	CDC_CTL_MODEL_HAS_CANCEL        CDCControlOption = C.CDC_CTL_MODEL_HAS_CANCEL
+	CDC_CTL_MODEL_IS_TIME_ACTIVATED CDCControlOption = C.CDC_CTL_MODEL_IS_TIME_ACTIVATED
+	CDC_CTL_OPTION_ORIGIN           CDCControlOption = C.CDC_CTL_OPTION_ORIGIN
+	CDC_CTL_OPTION_CTL_NUM          CDCControlOption = C.CDC_CTL_OPTION_CTL_NUM
+	CDC_CTL_OPTION_ST_SELD          CDCControlOption = C.CDC_CTL_OPTION_ST_SELD
+	CDC_CTL_OPTION_OP_RCVD          CDCControlOption = C.CDC_CTL_OPTION_OP_RCVD
+	CDC_CTL_OPTION_OP_OK            CDCControlOption = C.CDC_CTL_OPTION_OP_OK
+	CDC_CTL_OPTION_T_OP_OK          CDCControlOption = C.CDC_CTL_OPTION_T_OP_OK
+	CDC_CTL_OPTION_SBO_TIMEOUT      CDCControlOption = C.CDC_CTL_OPTION_SBO_TIMEOUT
+	CDC_CTL_OPTION_SBO_CLASS        CDCControlOption = C.CDC_CTL_OPTION_SBO_CLASS
+	CDC_CTL_OPTION_OPER_TIMEOUT     CDCControlOption = C.CDC_CTL_OPTION_OPER_TIMEOUT
+)
+
+// The CreateDataObjectCDC_* methods create data objects of the common data classes of IEC 61850-7-3
+// with their mandatory data attributes, plus the optional ones selected by options.
+//
+// Status information:
+// SPS: Single Point Status
+// DPS: Double Point Status
+// INS: Integer Status
+// ENS: Enumerated Status
+// ACT: Protection Activation Information
+// ACD: Directional Protection Activation Information
+// SEC: Security Violation Counting
+// BCR: Binary Counter Reading
+// HST: Histogram
+// VSS: Visible String Status
+//
+// Measurands:
+// MV: Measured Value
+// CMV: Complex Measured Value
+// SAV: Sampled Value
+// WYE: Phase to ground related measured values of a three-phase system
+// DEL: Phase to phase related measured values of a three-phase system
+//
+// Controls:
+// SPC: Controllable Single Point
+// DPC: Controllable Double Point
+// INC: Controllable Integer Status
+// ENC: Controllable Enumerated Status
+// BSC: Binary controlled step position information
+// ISC: Integer controlled step position information
+// APC: Analogue Process Control
+// BAC: Binary controlled Analogue process value
+//
+// Settings:
+// SPG: Single Point Setting
+// ING: Integer Status Setting
+// ENG: Enumerated Status Setting
+// ASG: Analogue Setting
+// VSG: Visible String Setting
+//
+// Descriptions:
+// LPL: Logical node name plate
+// DPL: Device name plate
+//
+// IEC 61400-25 (wind power plants), with CDC61400Option bits as wpOptions:
+// SPV: Setpoint Value
+// STV: Status Value
+// CMD: Command
+// ALM: Alarm
+// CTE: Event Counting
+// TMS: State Timing
+
+func cdcOptions(options []CDCOption) C.uint32_t {
+	var bits CDCOption
+	for _, option := range options {
+		bits |= option
+	}
+	return C.uint32_t(bits)
+}
+
+// cdcControlOptions combines the control model, which uses the lower bits, with the control options.
+func cdcControlOptions(ctlModel ControlModel, controlOptions CDCControlOption) C.uint32_t {
+	return C.uint32_t(uint32(ctlModel) | uint32(controlOptions))
+}
+
+func (n *LogicalNode) parent() *C.ModelNode {
+	return (*C.ModelNode)(unsafe.Pointer(n.node))
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_SPS(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_SPS_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_DPS(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_DPS_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_INS(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_INS_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_ENS(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_ENS_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_ACT(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_ACT_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_ACD(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_ACD_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_SEC(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_SEC_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_BCR(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_BCR_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+// CreateDataObjectCDC_HST creates a histogram with up to maxPts points.
+func (n *LogicalNode) CreateDataObjectCDC_HST(name string, maxPts uint16, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_HST_create(cname, n.parent(), cdcOptions(options), C.uint16_t(maxPts)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_VSS(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_VSS_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+// CreateDataObjectCDC_MV creates a measured value, with integer instead of float magnitudes if isInteger is set.
+func (n *LogicalNode) CreateDataObjectCDC_MV(name string, isInteger bool, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_MV_create(cname, n.parent(), cdcOptions(options), C.bool(isInteger)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_CMV(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_CMV_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_SAV(name string, isInteger bool, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_SAV_create(cname, n.parent(), cdcOptions(options), C.bool(isInteger)),
+	}
+}
+
+// CreateDataObjectCDC_WYE creates the phase to ground values, CDC_OPTION_PHASE_* select the phases.
+func (n *LogicalNode) CreateDataObjectCDC_WYE(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_WYE_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+// CreateDataObjectCDC_DEL creates the phase to phase values, CDC_OPTION_PHASE_* select the phases.
+func (n *LogicalNode) CreateDataObjectCDC_DEL(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_DEL_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_SPC(name string, ctlModel ControlModel, controlOptions CDCControlOption, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_SPC_create(cname, n.parent(), cdcOptions(options), cdcControlOptions(ctlModel, controlOptions)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_DPC(name string, ctlModel ControlModel, controlOptions CDCControlOption, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_DPC_create(cname, n.parent(), cdcOptions(options), cdcControlOptions(ctlModel, controlOptions)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_INC(name string, ctlModel ControlModel, controlOptions CDCControlOption, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_INC_create(cname, n.parent(), cdcOptions(options), cdcControlOptions(ctlModel, controlOptions)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_ENC(name string, ctlModel ControlModel, controlOptions CDCControlOption, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_ENC_create(cname, n.parent(), cdcOptions(options), cdcControlOptions(ctlModel, controlOptions)),
+	}
+}
+
+// CreateDataObjectCDC_BSC creates a binary controlled step position, hasTransInd adds the transient indicator.
+func (n *LogicalNode) CreateDataObjectCDC_BSC(name string, ctlModel ControlModel, controlOptions CDCControlOption, hasTransInd bool, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_BSC_create(cname, n.parent(), cdcOptions(options), cdcControlOptions(ctlModel, controlOptions), C.bool(hasTransInd)),
+	}
+}
+
+// CreateDataObjectCDC_ISC creates an integer controlled step position, hasTransInd adds the transient indicator.
+func (n *LogicalNode) CreateDataObjectCDC_ISC(name string, ctlModel ControlModel, controlOptions CDCControlOption, hasTransInd bool, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_ISC_create(cname, n.parent(), cdcOptions(options), cdcControlOptions(ctlModel, controlOptions), C.bool(hasTransInd)),
+	}
+}
+
+// CreateDataObjectCDC_APC creates an analogue process control, with integer instead of float values if isInteger is set.
+func (n *LogicalNode) CreateDataObjectCDC_APC(name string, ctlModel ControlModel, controlOptions CDCControlOption, isInteger bool, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_APC_create(cname, n.parent(), cdcOptions(options), cdcControlOptions(ctlModel, controlOptions), C.bool(isInteger)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_BAC(name string, ctlModel ControlModel, controlOptions CDCControlOption, isInteger bool, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_BAC_create(cname, n.parent(), cdcOptions(options), cdcControlOptions(ctlModel, controlOptions), C.bool(isInteger)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_SPG(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_SPG_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_ING(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_ING_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_ENG(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_ENG_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_ASG(name string, isInteger bool, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_ASG_create(cname, n.parent(), cdcOptions(options), C.bool(isInteger)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_VSG(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_VSG_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_LPL(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_LPL_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_DPL(name string, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_DPL_create(cname, n.parent(), cdcOptions(options)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_SPV(name string, ctlModel ControlModel, controlOptions CDCControlOption, wpOptions CDC61400Option, hasChaManRs bool, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_SPV_create(cname, n.parent(), cdcOptions(options), cdcControlOptions(ctlModel, controlOptions), C.uint32_t(wpOptions), C.bool(hasChaManRs)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_STV(name string, ctlModel ControlModel, controlOptions CDCControlOption, wpOptions CDC61400Option, hasOldStatus bool, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_STV_create(cname, n.parent(), cdcOptions(options), cdcControlOptions(ctlModel, controlOptions), C.uint32_t(wpOptions), C.bool(hasOldStatus)),
+	}
+}
+
+// CreateDataObjectCDC_CMD creates a command, hasCmTm and hasCmCt add the command time and counter.
+func (n *LogicalNode) CreateDataObjectCDC_CMD(name string, ctlModel ControlModel, controlOptions CDCControlOption, wpOptions CDC61400Option, hasOldStatus, hasCmTm, hasCmCt bool, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_CMD_create(cname, n.parent(), cdcOptions(options), cdcControlOptions(ctlModel, controlOptions), C.uint32_t(wpOptions),
+			C.bool(hasOldStatus), C.bool(hasCmTm), C.bool(hasCmCt)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_ALM(name string, ctlModel ControlModel, controlOptions CDCControlOption, wpOptions CDC61400Option, hasOldStatus bool, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_ALM_create(cname, n.parent(), cdcOptions(options), cdcControlOptions(ctlModel, controlOptions), C.uint32_t(wpOptions), C.bool(hasOldStatus)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_CTE(name string, ctlModel ControlModel, controlOptions CDCControlOption, wpOptions CDC61400Option, hasHisRs bool, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_CTE_create(cname, n.parent(), cdcOptions(options), cdcControlOptions(ctlModel, controlOptions), C.uint32_t(wpOptions), C.bool(hasHisRs)),
+	}
+}
+
+func (n *LogicalNode) CreateDataObjectCDC_TMS(name string, ctlModel ControlModel, controlOptions CDCControlOption, wpOptions CDC61400Option, hasHisRs bool, options ...CDCOption) *DataObject {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return &DataObject{
+		object: C.CDC_TMS_create(cname, n.parent(), cdcOptions(options), cdcControlOptions(ctlModel, controlOptions), C.uint32_t(wpOptions), C.bool(hasHisRs)),
+	}
+}
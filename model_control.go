@@ -0,0 +1,279 @@
+package iec61850
+
+// #include <iec61850_server.h>
+// #include <iec61850_dynamic_model.h>
+import "C"
+
+import (
+	"net"
+	"unsafe"
+)
+
+// ReportControlBlockConfig describes a report control block of the dynamic model.
+type ReportControlBlockConfig struct {
+	Name     string // name of the RCB instance, including the index of indexed RCBs
+	RptID    string // empty to use the RCB reference
+	Buffered bool
+	DataSet  string // name of a data set of the logical node, like "Events"
+	ConfRev  uint32
+	TrgOps   TrgOps
+	OptFlds  OptFlds
+	BufTm    uint32 // buffer time in ms
+	IntgPd   uint32 // integrity period in ms
+	// Owner adds the Owner attribute to the RCB.
+	Owner bool
+	// PreconfiguredClient reserves the RCB for the client with this IPv4 or IPv6 address, nil for none.
+	PreconfiguredClient net.IP
+}
+
+// rcbHasOwner is the bit of the trigger options by which libiec61850 marks RCBs with Owner attribute.
+const rcbHasOwner = 64
+
+// CreateReportControlBlock creates a report control block in the logical node.
+func (n *LogicalNode) CreateReportControlBlock(config ReportControlBlockConfig) *ReportControlBlock {
+	cName := C.CString(config.Name)
+	defer C.free(unsafe.Pointer(cName))
+	cRptID := cStringOrNil(config.RptID)
+	defer C.free(unsafe.Pointer(cRptID))
+	cDataSet := cStringOrNil(config.DataSet)
+	defer C.free(unsafe.Pointer(cDataSet))
+
+	trgOps := config.TrgOps.bits()
+	if config.Owner {
+		trgOps |= rcbHasOwner
+	}
+
+	rcb := C.ReportControlBlock_create(cName, n.node, cRptID, C.bool(config.Buffered), cDataSet, C.uint32_t(config.ConfRev),
+		C.uint8_t(trgOps), C.uint8_t(config.OptFlds.bits()), C.uint32_t(config.BufTm), C.uint32_t(config.IntgPd))
+
+	if ip := config.PreconfiguredClient; ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			C.ReportControlBlock_setPreconfiguredClient(rcb, 4, (*C.uint8_t)(unsafe.Pointer(&ip4[0])))
+		} else if ip16 := ip.To16(); ip16 != nil {
+			C.ReportControlBlock_setPreconfiguredClient(rcb, 6, (*C.uint8_t)(unsafe.Pointer(&ip16[0])))
+		}
+	}
+	return &ReportControlBlock{rcb: rcb}
+}
+
+// bits returns the report options as libiec61850 RPT_OPT_* bit mask.
+func (o OptFlds) bits() uint8 {
+	var bits uint8
+	if o.SequenceNumber {
+		bits |= C.RPT_OPT_SEQ_NUM
+	}
+	if o.TimeOfEntry {
+		bits |= C.RPT_OPT_TIME_STAMP
+	}
+	if o.ReasonForInclusion {
+		bits |= C.RPT_OPT_REASON_FOR_INCLUSION
+	}
+	if o.DataSetName {
+		bits |= C.RPT_OPT_DATA_SET
+	}
+	if o.DataReference {
+		bits |= C.RPT_OPT_DATA_REFERENCE
+	}
+	if o.BufferOverflow {
+		bits |= C.RPT_OPT_BUFFER_OVERFLOW
+	}
+	if o.EntryID {
+		bits |= C.RPT_OPT_ENTRY_ID
+	}
+	if o.ConfigRevision {
+		bits |= C.RPT_OPT_CONF_REV
+	}
+	return bits
+}
+
+// GSEControlBlockConfig describes a GOOSE control block of the dynamic model.
+type GSEControlBlockConfig struct {
+	Name      string
+	AppID     string // GoID, empty to use the GoCB reference
+	DataSet   string // name of a data set of the logical node
+	ConfRev   uint32
+	FixedOffs bool
+	MinTime   int // ms, -1 if not specified
+	MaxTime   int // ms, -1 if not specified
+	// Address is the destination of the GOOSE messages, nil to use the defaults of the GOOSE publisher.
+	Address *PhyComAddress
+}
+
+// GSEControlBlock is a GOOSE control block of the server model.
+type GSEControlBlock struct {
+	gcb *C.GSEControlBlock
+}
+
+// CreateGSEControlBlock creates a GOOSE control block in the logical node, which has to be LLN0.
+func (n *LogicalNode) CreateGSEControlBlock(config GSEControlBlockConfig) *GSEControlBlock {
+	cName := C.CString(config.Name)
+	defer C.free(unsafe.Pointer(cName))
+	cAppID := cStringOrNil(config.AppID)
+	defer C.free(unsafe.Pointer(cAppID))
+	cDataSet := cStringOrNil(config.DataSet)
+	defer C.free(unsafe.Pointer(cDataSet))
+
+	gcb := C.GSEControlBlock_create(cName, n.node, cAppID, cDataSet, C.uint32_t(config.ConfRev), C.bool(config.FixedOffs),
+		C.int(config.MinTime), C.int(config.MaxTime))
+	if config.Address != nil {
+		C.GSEControlBlock_addPhyComAddress(gcb, config.Address.create())
+	}
+	return &GSEControlBlock{gcb: gcb}
+}
+
+// SVControlBlockConfig describes a sampled value control block of the dynamic model.
+type SVControlBlockConfig struct {
+	Name    string
+	SvID    string
+	DataSet string
+	ConfRev uint32
+	SmpMod  uint8 // 0: samples per period, 1: samples per second, 2: seconds per sample
+	SmpRate uint16
+	OptFlds SVOptFlds
+	Unicast bool // creates an USVCB instead of a MSVCB
+	// Address is the destination of the sampled value messages, nil for none.
+	Address *PhyComAddress
+}
+
+// SVControlBlock is a sampled value control block of the server model.
+type SVControlBlock struct {
+	svcb *C.SVControlBlock
+}
+
+// CreateSVControlBlock creates a sampled value control block in the logical node.
+func (n *LogicalNode) CreateSVControlBlock(config SVControlBlockConfig) *SVControlBlock {
+	cName := C.CString(config.Name)
+	defer C.free(unsafe.Pointer(cName))
+	cSvID := cStringOrNil(config.SvID)
+	defer C.free(unsafe.Pointer(cSvID))
+	cDataSet := cStringOrNil(config.DataSet)
+	defer C.free(unsafe.Pointer(cDataSet))
+
+	svcb := C.SVControlBlock_create(cName, n.node, cSvID, cDataSet, C.uint32_t(config.ConfRev), C.uint8_t(config.SmpMod),
+		C.uint16_t(config.SmpRate), C.uint8_t(config.OptFlds.bits()), C.bool(config.Unicast))
+	if config.Address != nil {
+		C.SVControlBlock_addPhyComAddress(svcb, config.Address.create())
+	}
+	return &SVControlBlock{svcb: svcb}
+}
+
+// bits returns the sampled value options as bit mask of the SVCB OptFlds.
+func (o SVOptFlds) bits() uint8 {
+	var bits uint8
+	if o.RefreshTime {
+		bits |= 1
+	}
+	if o.SampleSynchronized {
+		bits |= 2
+	}
+	if o.SampleRate {
+		bits |= 4
+	}
+	if o.DataSet {
+		bits |= 8
+	}
+	if o.Security {
+		bits |= 16
+	}
+	return bits
+}
+
+// create allocates the address for a control block, which owns it afterwards.
+func (a *PhyComAddress) create() *C.PhyComAddress {
+	dstAddress := a.DstAddress
+	return C.PhyComAddress_create(C.uint8_t(a.VlanPriority), C.uint16_t(a.VlanId), C.uint16_t(a.AppId),
+		(*C.uint8_t)(unsafe.Pointer(&dstAddress[0])))
+}
+
+// LogControlBlockConfig describes a log control block of the dynamic model.
+type LogControlBlockConfig struct {
+	Name    string
+	DataSet string
+	// LogRef is the log written by the LCB, like "GenericIO/LLN0$EventLog".
+	LogRef     string
+	TrgOps     TrgOps
+	IntgPd     uint32 // integrity period in ms
+	LogEna     bool
+	ReasonCode bool
+}
+
+// LogControlBlock is a log control block of the server model.
+type LogControlBlock struct {
+	lcb *C.LogControlBlock
+}
+
+// CreateLogControlBlock creates a log control block in the logical node. The log referenced by LogRef
+// is created by CreateLog.
+func (n *LogicalNode) CreateLogControlBlock(config LogControlBlockConfig) *LogControlBlock {
+	cName := C.CString(config.Name)
+	defer C.free(unsafe.Pointer(cName))
+	cDataSet := cStringOrNil(config.DataSet)
+	defer C.free(unsafe.Pointer(cDataSet))
+	cLogRef := cStringOrNil(config.LogRef)
+	defer C.free(unsafe.Pointer(cLogRef))
+
+	lcb := C.LogControlBlock_create(cName, n.node, cDataSet, cLogRef, C.uint8_t(config.TrgOps.bits()),
+		C.uint32_t(config.IntgPd), C.bool(config.LogEna), C.bool(config.ReasonCode))
+	return &LogControlBlock{lcb: lcb}
+}
+
+// CreateLog creates a log in the logical node. Entries are only stored after a log storage is
+// attached to the log at the server.
+func (n *LogicalNode) CreateLog(name string) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.Log_create(cName, n.node)
+}
+
+// SettingGroupControlBlock is the setting group control block of a logical device.
+type SettingGroupControlBlock struct {
+	sgcb *C.SettingGroupControlBlock
+}
+
+// CreateSettingGroupControlBlock creates the setting group control block in the logical node,
+// which has to be LLN0. Setting groups are numbered from 1 to numOfSGs.
+func (n *LogicalNode) CreateSettingGroupControlBlock(actSG, numOfSGs uint8) *SettingGroupControlBlock {
+	return &SettingGroupControlBlock{
+		sgcb: C.SettingGroupControlBlock_create(n.node, C.uint8_t(actSG), C.uint8_t(numOfSGs)),
+	}
+}
+
+// GetSettingGroupControlBlock returns the setting group control block of the logical device, like
+// "simpleIOGenericIO", or nil if the device has none.
+func (m *IedModel) GetSettingGroupControlBlock(ldName string) *SettingGroupControlBlock {
+	cLdName := C.CString(ldName)
+	defer C.free(unsafe.Pointer(cLdName))
+	ld := C.IedModel_getDevice(m.Model, cLdName)
+	if ld == nil {
+		return nil
+	}
+	sgcb := C.LogicalDevice_getSettingGroupControlBlock(ld)
+	if sgcb == nil {
+		return nil
+	}
+	return &SettingGroupControlBlock{sgcb: sgcb}
+}
+
+// NumOfSGs returns the number of setting groups.
+func (b *SettingGroupControlBlock) NumOfSGs() uint8 {
+	return uint8(b.sgcb.numOfSGs)
+}
+
+// EditSG returns the setting group selected for editing, 0 if none.
+func (b *SettingGroupControlBlock) EditSG() uint8 {
+	return uint8(b.sgcb.editSG)
+}
+
+// LogicalDevice returns the logical device of the setting group control block.
+func (b *SettingGroupControlBlock) LogicalDevice() *ModelNode {
+	return newModelNode((*C.ModelNode)(unsafe.Pointer(b.sgcb.parent)).parent)
+}
+
+// cStringOrNil returns nil for empty strings, for optional parameters of libiec61850.
+// C.free accepts the nil pointer.
+func cStringOrNil(s string) *C.char {
+	if s == "" {
+		return nil
+	}
+	return C.CString(s)
+}
@@ -0,0 +1,187 @@
+package iec61850
+
+// #include <iec61850_server.h>
+// #include <iec61850_dynamic_model.h>
+import "C"
+
+import "unsafe"
+
+// ModelNodeType is the kind of node in the server data model.
+type ModelNodeType int
+
+const (
+	MODEL_NODE_LOGICAL_DEVICE ModelNodeType = iota
+	MODEL_NODE_LOGICAL_NODE
+	MODEL_NODE_DATA_OBJECT
+	MODEL_NODE_DATA_ATTRIBUTE
+)
+
+func (t ModelNodeType) String() string {
+	switch t {
+	case MODEL_NODE_LOGICAL_DEVICE:
+		return "LogicalDevice"
+	case MODEL_NODE_LOGICAL_NODE:
+		return "LogicalNode"
+	case MODEL_NODE_DATA_OBJECT:
+		return "DataObject"
+	case MODEL_NODE_DATA_ATTRIBUTE:
+		return "DataAttribute"
+	}
+	return "Unknown"
+}
+
+// newModelNode wraps a node of the C model, nil stays nil.
+func newModelNode(node *C.ModelNode) *ModelNode {
+	if node == nil {
+		return nil
+	}
+	m := &ModelNode{_modelNode: unsafe.Pointer(node)}
+	m.ObjectReference = m.GetObjectReference()
+	return m
+}
+
+func (m *ModelNode) cNode() *C.ModelNode {
+	return (*C.ModelNode)(m._modelNode)
+}
+
+// GetLogicalDevices returns the logical devices of the model.
+func (m *IedModel) GetLogicalDevices() []*ModelNode {
+	var devices []*ModelNode
+	for ld := m.Model.firstChild; ld != nil; ld = (*C.LogicalDevice)(unsafe.Pointer(ld.sibling)) {
+		devices = append(devices, newModelNode((*C.ModelNode)(unsafe.Pointer(ld))))
+	}
+	return devices
+}
+
+// Walk calls fn for all nodes of the model in depth first order, see ModelNode.Walk.
+func (m *IedModel) Walk(fn func(node *ModelNode) bool) {
+	for _, ld := range m.GetLogicalDevices() {
+		ld.Walk(fn)
+	}
+}
+
+// GetType returns whether the node is a logical device, logical node, data object or data attribute.
+func (m *ModelNode) GetType() ModelNodeType {
+	return ModelNodeType(C.ModelNode_getType(m.cNode()))
+}
+
+// GetName returns the name of the node, like "GGIO1" or "stVal". The name of a logical device
+// includes the IED name.
+func (m *ModelNode) GetName() string {
+	return C.GoString(C.ModelNode_getName(m.cNode()))
+}
+
+// GetObjectReference returns the object reference of the node, like "simpleIOGenericIO/GGIO1.AnIn1.mag.f".
+func (m *ModelNode) GetObjectReference() string {
+	// object references are limited to 129 characters
+	buf := make([]byte, 130)
+	C.ModelNode_getObjectReference(m.cNode(), (*C.char)(unsafe.Pointer(&buf[0])))
+	return C.GoString((*C.char)(unsafe.Pointer(&buf[0])))
+}
+
+// GetParent returns the parent node, or nil for logical devices.
+func (m *ModelNode) GetParent() *ModelNode {
+	if m.GetType() == MODEL_NODE_LOGICAL_DEVICE {
+		return nil
+	}
+	return newModelNode(C.ModelNode_getParent(m.cNode()))
+}
+
+// GetChildren returns the child nodes in model order.
+func (m *ModelNode) GetChildren() []*ModelNode {
+	var children []*ModelNode
+	for child := m.cNode().firstChild; child != nil; child = child.sibling {
+		children = append(children, newModelNode(child))
+	}
+	return children
+}
+
+// GetChild returns the child with the name, or nil if it doesn't exist. Sub nodes can be addressed
+// with $ like "mag$f".
+func (m *ModelNode) GetChild(name string) *ModelNode {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	return newModelNode(C.ModelNode_getChild(m.cNode(), cName))
+}
+
+// GetChildWithFC returns the child data attribute with the name and functional constraint, or nil
+// if it doesn't exist.
+func (m *ModelNode) GetChildWithFC(name string, fc FC) *ModelNode {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	return newModelNode(C.ModelNode_getChildWithFc(m.cNode(), cName, C.FunctionalConstraint(fc)))
+}
+
+// Walk calls fn for the node and all nodes below it in depth first order. When fn returns false
+// the children of the node are skipped.
+func (m *ModelNode) Walk(fn func(node *ModelNode) bool) {
+	if !fn(m) {
+		return
+	}
+	for _, child := range m.GetChildren() {
+		child.Walk(fn)
+	}
+}
+
+// GetFC returns the functional constraint of a data attribute, NONE for other nodes.
+func (m *ModelNode) GetFC() FC {
+	if m.GetType() != MODEL_NODE_DATA_ATTRIBUTE {
+		return NONE
+	}
+	return FC(C.DataAttribute_getFC((*C.DataAttribute)(m._modelNode)))
+}
+
+// GetTrgOps returns the trigger options of a data attribute, which are empty for other nodes.
+func (m *ModelNode) GetTrgOps() TrgOps {
+	if m.GetType() != MODEL_NODE_DATA_ATTRIBUTE {
+		return TrgOps{}
+	}
+	return trgOpsFromServerBits(uint8(C.DataAttribute_getTrgOps((*C.DataAttribute)(m._modelNode))))
+}
+
+// GetDataAttributeType returns the type of a data attribute, DA_TYPE_CONSTRUCTED for other nodes.
+func (m *ModelNode) GetDataAttributeType() DataAttributeType {
+	if m.GetType() != MODEL_NODE_DATA_ATTRIBUTE {
+		return DA_TYPE_CONSTRUCTED
+	}
+	return DataAttributeType(C.DataAttribute_getType((*C.DataAttribute)(m._modelNode)))
+}
+
+// GetArrayElements returns the number of elements of array data objects and attributes, 0 for
+// other nodes.
+func (m *ModelNode) GetArrayElements() int {
+	switch m.GetType() {
+	case MODEL_NODE_DATA_OBJECT:
+		return int((*C.DataObject)(m._modelNode).elementCount)
+	case MODEL_NODE_DATA_ATTRIBUTE:
+		return int((*C.DataAttribute)(m._modelNode).elementCount)
+	}
+	return 0
+}
+
+// ConvertToDataAttribute returns the node as DataAttribute.
+func (m *ModelNode) ConvertToDataAttribute() *DataAttribute {
+	return &DataAttribute{
+		attribute: (*C.DataAttribute)(m._modelNode),
+	}
+}
+
+// ModelNode returns the logical device as node of the model tree.
+func (d *LogicalDevice) ModelNode() *ModelNode {
+	return newModelNode((*C.ModelNode)(unsafe.Pointer(d.device)))
+}
+
+// ModelNode returns the logical node as node of the model tree.
+func (n *LogicalNode) ModelNode() *ModelNode {
+	return newModelNode((*C.ModelNode)(unsafe.Pointer(n.node)))
+}
+
+// ModelNode returns the data object as node of the model tree.
+func (do *DataObject) ModelNode() *ModelNode {
+	return newModelNode((*C.ModelNode)(unsafe.Pointer(do.object)))
+}
+
+// ModelNode returns the data attribute as node of the model tree.
+func (da *DataAttribute) ModelNode() *ModelNode {
+	return newModelNode((*C.ModelNode)(unsafe.Pointer(da.attribute)))
+}
@@ -52,8 +52,8 @@ type GSE struct {
 	Address    *PhyComAddress `xml:"Address"`
 
 	// custom
-	MinTime int
-	MaxTime int
+	MinTime int `xml:"-"`
+	MaxTime int `xml:"-"`
 }
 
 type PhyComAddress struct {
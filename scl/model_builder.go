@@ -0,0 +1,414 @@
+package scl
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+
+	"github.com/marrasen/iec61850"
+	"github.com/spf13/cast"
+)
+
+// modelBuilder creates the server data model of an IED through the dynamic model API of libiec61850.
+// It follows StaticModelGenerator, which emits the same model as C code.
+type modelBuilder struct {
+	_scl        *SCL
+	ied         *IED
+	accessPoint *AccessPoint
+	connectedAP *ConnectedAP
+	hasOwner    bool
+	model       *iec61850.IedModel
+	logger      *slog.Logger
+}
+
+// BuildIedModel creates the server data model of an IED of a parsed SCL file at runtime, including
+// initial values, data sets and report, GOOSE, sampled value, log and setting group control blocks.
+// iedName and apName select the IED and its access point, empty for the first ones.
+// Logical devices are named by their inst attribute, like in the models of StaticModelGenerator.
+// Initial values which libiec61850 can't represent are skipped, use BuildIedModelWithLogger to log them.
+func BuildIedModel(scl *SCL, iedName, apName string) (*iec61850.IedModel, error) {
+	return BuildIedModelWithLogger(scl, iedName, apName, slog.New(slog.DiscardHandler))
+}
+
+// BuildIedModelWithLogger is BuildIedModel logging a warning to logger for each skipped initial value.
+func BuildIedModelWithLogger(scl *SCL, iedName, apName string, logger *slog.Logger) (*iec61850.IedModel, error) {
+	b := &modelBuilder{_scl: scl, logger: logger}
+
+	if iedName == "" {
+		b.ied = scl.getFirstIed()
+	} else {
+		b.ied = scl.getIedByName(iedName)
+	}
+	if b.ied == nil {
+		return nil, fmt.Errorf("BuildIedModel %q: IED model not found in SCL file", iedName)
+	}
+
+	if apName == "" {
+		b.accessPoint = b.ied.getFirstAccessPoint()
+	} else {
+		b.accessPoint = b.ied.getAccessPointByName(apName)
+	}
+	if b.accessPoint == nil || b.accessPoint.Server == nil {
+		return nil, fmt.Errorf("BuildIedModel %q: access point %q with server not found", b.ied.Name, apName)
+	}
+
+	if b.ied.Services != nil && b.ied.Services.ReportSettings != nil {
+		b.hasOwner = b.ied.Services.ReportSettings.Owner
+	}
+	if scl.Communication != nil {
+		b.connectedAP = scl.Communication.getConnectedAP(b.accessPoint.Name)
+	}
+
+	b.model = iec61850.NewIedModel(b.ied.Name)
+	if err := b.build(); err != nil {
+		b.model.Destroy()
+		return nil, fmt.Errorf("BuildIedModel %q: %w", b.ied.Name, err)
+	}
+	return b.model, nil
+}
+
+// ServerIPAddress returns the IP address of an access point of an IED in the communication section of a
+// parsed SCL file, to start the server of the model of BuildIedModel on it.
+// iedName and apName select the IED and its access point, empty for the first ones.
+func ServerIPAddress(scl *SCL, iedName, apName string) (string, error) {
+	var ied *IED
+	if iedName == "" {
+		ied = scl.getFirstIed()
+	} else {
+		ied = scl.getIedByName(iedName)
+	}
+	if ied == nil {
+		return "", fmt.Errorf("ServerIPAddress %q: IED model not found in SCL file", iedName)
+	}
+
+	var accessPoint *AccessPoint
+	if apName == "" {
+		accessPoint = ied.getFirstAccessPoint()
+	} else {
+		accessPoint = ied.getAccessPointByName(apName)
+	}
+	if accessPoint == nil {
+		return "", fmt.Errorf("ServerIPAddress %q: access point %q not found", ied.Name, apName)
+	}
+
+	var ipAddress string
+	if scl.Communication != nil {
+		ipAddress = scl.Communication.getIpAddressByIedName(ied.Name, accessPoint.Name)
+	}
+	if ipAddress == "" {
+		return "", fmt.Errorf("ServerIPAddress %q: no IP address of access point %q", ied.Name, accessPoint.Name)
+	}
+	return ipAddress, nil
+}
+
+func (b *modelBuilder) build() error {
+	for _, logicalDevice := range b.accessPoint.Server.LogicalDevices {
+		ld := b.model.CreateLogicalDevice(logicalDevice.Inst)
+
+		for _, logicalNode := range logicalDevice.LogicalNodes {
+			lnRef := logicalDevice.Inst + "/" + logicalNode.GetName()
+			ln := ld.CreateLogicalNode(logicalNode.GetName())
+
+			for _, dataObject := range logicalNode.DataObjects {
+				b.createDataObject(ln.CreateDataObject, lnRef, dataObject, false)
+			}
+			if err := b.createDataSets(ln, logicalNode); err != nil {
+				return fmt.Errorf("%s: %w", lnRef, err)
+			}
+			if err := b.createReportControlBlocks(ln, logicalNode); err != nil {
+				return fmt.Errorf("%s: %w", lnRef, err)
+			}
+			b.createLogControlBlocks(ln, logicalNode, logicalDevice)
+			for _, log := range logicalNode.Logs {
+				ln.CreateLog(log.Name)
+			}
+			b.createGSEControlBlocks(ln, logicalNode, logicalDevice)
+			b.createSVControlBlocks(ln, logicalNode, logicalDevice)
+			if len(logicalNode.SettingGroupControlBlocks) > 0 {
+				sgcb := logicalNode.SettingGroupControlBlocks[0]
+				ln.CreateSettingGroupControlBlock(uint8(sgcb.ActSG), uint8(sgcb.NumOfSGs))
+			}
+		}
+	}
+	return nil
+}
+
+func (b *modelBuilder) createDataObject(create func(name string, arrayElements int) *iec61850.DataObject, parentRef string,
+	dataObject *DataObject, isTransient bool) {
+	doRef := parentRef + "." + dataObject.Name
+	do := create(dataObject.Name, dataObject.Count)
+
+	// transient data objects pass the transient flag to all their attributes
+	isDoTransient := isTransient || dataObject.Trans
+
+	for _, subDataObject := range dataObject.SubDataObjects {
+		b.createDataObject(do.CreateDataObject, doRef, subDataObject, isDoTransient)
+	}
+	for _, dataAttribute := range dataObject.DataAttributes {
+		b.createDataAttribute(do.CreateDataAttribute, doRef, dataAttribute, isDoTransient)
+	}
+}
+
+func (b *modelBuilder) createDataAttribute(create func(name string, daType iec61850.DataAttributeType, fc iec61850.FC,
+	trgOps iec61850.TrgOps, arrayElements int, sAddr uint32) *iec61850.DataAttribute,
+	parentRef string, dataAttribute *DataAttribute, isTransient bool) {
+	daRef := parentRef + "." + dataAttribute.Name
+
+	trgOps := iec61850.TrgOps{Transient: isTransient}
+	if dataAttribute.TriggerOptions != nil {
+		trgOps.DataChange = dataAttribute.TriggerOptions.Dchg
+		trgOps.QualityChange = dataAttribute.TriggerOptions.Qchg
+		trgOps.DataUpdate = dataAttribute.TriggerOptions.Dupd
+	}
+
+	// short addresses which are no numbers are not supported by libiec61850 and ignored
+	shortAddr, _ := cast.ToUint32E(dataAttribute.ShortAddress)
+
+	// AttributeType follows the ordering of the libiec61850 DataAttributeType enum
+	da := create(dataAttribute.Name, iec61850.DataAttributeType(dataAttribute.AttributeType),
+		iec61850.FunctionalConstraintFromString(dataAttribute.FC), trgOps, dataAttribute.Count, shortAddr)
+
+	for _, subDataAttribute := range dataAttribute.SubDataAttributes {
+		b.createDataAttribute(da.CreateDataAttribute, daRef, subDataAttribute, isTransient)
+	}
+
+	// initial values of arrays are not supported, like in StaticModelGenerator
+	if dataAttribute.Count > 0 {
+		return
+	}
+
+	// use the value of the instance, or the default value of the type definition
+	value := dataAttribute.Value
+	if value == nil && dataAttribute.Definition != nil {
+		value = dataAttribute.Definition.Value
+		if value != nil && value.Value == nil {
+			value.updateEnumOrdValue(b._scl.DataTypeTemplates)
+		}
+	}
+	if value == nil || value.Value == nil {
+		return
+	}
+	// values of unsupported types like CHECK or TRGOPS are skipped, like in StaticModelGenerator
+	if err := da.SetValue(value.Value); err != nil {
+		b.logger.Warn("initial value is skipped", "ref", daRef, "error", err)
+	}
+}
+
+func (b *modelBuilder) createDataSets(ln *iec61850.LogicalNode, logicalNode *LogicalNode) error {
+	for _, dataSet := range logicalNode.DataSets {
+		ds := ln.CreateDataSet(dataSet.Name)
+
+		for _, fcda := range dataSet.FCDA {
+			var variable strings.Builder
+			if fcda.LdInst != "" {
+				variable.WriteString(fcda.LdInst + "/")
+			}
+			variable.WriteString(fcda.Prefix + fcda.LnClass + fcda.LnInst)
+			variable.WriteString("$" + fcda.Fc)
+			variable.WriteString("$" + toMmsString(fcda.DoName))
+			if fcda.DaName != "" {
+				variable.WriteString("$" + toMmsString(fcda.DaName))
+			}
+			variableName := variable.String()
+
+			// members can select an array element and a component of it, like "...$ST$Arr(2)$stVal"
+			index := -1
+			component := ""
+			if arrayStart := strings.Index(variableName, "("); arrayStart != -1 {
+				arrayEnd := strings.Index(variableName, ")")
+				if arrayEnd < arrayStart {
+					return fmt.Errorf("data set %s: invalid array index in %s", dataSet.Name, variableName)
+				}
+
+				var err error
+				if index, err = cast.ToIntE(variableName[arrayStart+1 : arrayEnd]); err != nil {
+					return fmt.Errorf("data set %s: %w", dataSet.Name, err)
+				}
+				component = strings.TrimPrefix(variableName[arrayEnd+1:], "$")
+				variableName = variableName[:arrayStart]
+			}
+
+			ds.AddDataSetEntryEx(variableName, index, component)
+		}
+	}
+	return nil
+}
+
+func (b *modelBuilder) createReportControlBlocks(ln *iec61850.LogicalNode, logicalNode *LogicalNode) error {
+	for _, rcb := range logicalNode.ReportControlBlocks {
+		config := iec61850.ReportControlBlockConfig{
+			RptID:    rcb.RptID,
+			Buffered: rcb.Buffered,
+			DataSet:  rcb.DatSet,
+			BufTm:    uint32(rcb.BufTime),
+			TrgOps:   toTrgOps(rcb.TriggerOptions),
+			Owner:    b.hasOwner,
+		}
+
+		var err error
+		if rcb.ConfRev != "" {
+			if config.ConfRev, err = cast.ToUint32E(rcb.ConfRev); err != nil {
+				return fmt.Errorf("report control block %s: confRev: %w", rcb.Name, err)
+			}
+		}
+		if rcb.IntgPd != "" {
+			if config.IntgPd, err = cast.ToUint32E(rcb.IntgPd); err != nil {
+				return fmt.Errorf("report control block %s: intgPd: %w", rcb.Name, err)
+			}
+		}
+
+		if rcb.OptionFields != nil {
+			config.OptFlds = iec61850.OptFlds{
+				SequenceNumber:     rcb.OptionFields.SeqNum,
+				TimeOfEntry:        rcb.OptionFields.TimeStamp,
+				ReasonForInclusion: rcb.OptionFields.ReasonCode,
+				DataSetName:        rcb.OptionFields.DataSet,
+				DataReference:      rcb.OptionFields.DataRef,
+				BufferOverflow:     rcb.OptionFields.BufOvfl,
+				EntryID:            rcb.OptionFields.EntryID,
+				ConfigRevision:     rcb.OptionFields.ConfigRef,
+			}
+		} else {
+			config.OptFlds = iec61850.OptFlds{BufferOverflow: true}
+		}
+
+		if !rcb.Indexed {
+			config.Name = rcb.Name
+			ln.CreateReportControlBlock(config)
+			continue
+		}
+
+		maxInstances := 1
+		var clientLNs []*ClientLN
+		if rcb.RptEnabled != nil {
+			maxInstances = rcb.RptEnabled.Max
+			clientLNs = rcb.RptEnabled.ClientLNs
+		}
+		for i := 0; i < maxInstances; i++ {
+			config.Name = fmt.Sprintf("%s%02d", rcb.Name, i+1)
+			config.PreconfiguredClient = nil
+			if i < len(clientLNs) && clientLNs[i] != nil && clientLNs[i].IedName != "" && b._scl.Communication != nil {
+				ipAddress := b._scl.Communication.getIpAddressByIedName(clientLNs[i].IedName, clientLNs[i].ApRef)
+				config.PreconfiguredClient = net.ParseIP(ipAddress)
+			}
+			ln.CreateReportControlBlock(config)
+		}
+	}
+	return nil
+}
+
+func (b *modelBuilder) createLogControlBlocks(ln *iec61850.LogicalNode, logicalNode *LogicalNode, logicalDevice *LogicalDevice) {
+	for _, lcb := range logicalNode.LogControlBlocks {
+		config := iec61850.LogControlBlockConfig{
+			Name:       lcb.Name,
+			DataSet:    lcb.DatSet,
+			TrgOps:     toTrgOps(lcb.TriggerOptions),
+			IntgPd:     uint32(lcb.IntgPd),
+			LogEna:     lcb.LogEna,
+			ReasonCode: lcb.ReasonCode,
+		}
+		// general interrogation is no trigger option of log control blocks
+		config.TrgOps.Gi = false
+
+		if lcb.LogName != "" {
+			ldInst := lcb.LdInst
+			if ldInst == "" {
+				ldInst = logicalDevice.Inst
+			}
+			lnName := lcb.Prefix + lcb.LnClass + lcb.LnInst
+			if lcb.LnClass == "" {
+				lnName = logicalNode.GetName()
+			}
+			config.LogRef = ldInst + "/" + lnName + "$" + lcb.LogName
+		}
+
+		ln.CreateLogControlBlock(config)
+	}
+}
+
+func (b *modelBuilder) createGSEControlBlocks(ln *iec61850.LogicalNode, logicalNode *LogicalNode, logicalDevice *LogicalDevice) {
+	for _, gseControlBlock := range logicalNode.GSEControlBlocks {
+		config := iec61850.GSEControlBlockConfig{
+			Name:      gseControlBlock.Name,
+			AppID:     gseControlBlock.AppID,
+			DataSet:   gseControlBlock.DatSet,
+			ConfRev:   uint32(gseControlBlock.ConfRev),
+			FixedOffs: gseControlBlock.FixedOffs,
+			MinTime:   -1,
+			MaxTime:   -1,
+		}
+
+		// the communication section provides address and timing, GoCBs of ICD files often have none
+		if b.connectedAP != nil {
+			if gse := b.connectedAP.LookupGSE(logicalDevice.Inst, gseControlBlock.Name); gse != nil {
+				config.MinTime = gse.MinTime
+				config.MaxTime = gse.MaxTime
+				config.Address = toPhyComAddress(gse.Address)
+			}
+		}
+
+		ln.CreateGSEControlBlock(config)
+	}
+}
+
+func (b *modelBuilder) createSVControlBlocks(ln *iec61850.LogicalNode, logicalNode *LogicalNode, logicalDevice *LogicalDevice) {
+	for _, svCB := range logicalNode.SMVControlBlocks {
+		config := iec61850.SVControlBlockConfig{
+			Name:    svCB.Name,
+			SvID:    svCB.SmvID,
+			DataSet: svCB.DatSet,
+			ConfRev: uint32(svCB.ConfRev),
+			SmpMod:  uint8(svCB.SmpMod),
+			SmpRate: uint16(svCB.SmpRate),
+			Unicast: !svCB.Multicast,
+		}
+		if opts := svCB.SmvOpts; opts != nil {
+			config.OptFlds = iec61850.SVOptFlds{
+				RefreshTime:        opts.RefreshTime,
+				SampleSynchronized: opts.SampleSynchronized,
+				SampleRate:         opts.SampleRate,
+				DataSet:            opts.DataSet,
+				Security:           opts.Security,
+			}
+		}
+
+		if b.connectedAP != nil {
+			if smv := b.connectedAP.LookupSMV(logicalDevice.Inst, svCB.Name); smv != nil {
+				config.Address = toPhyComAddress(smv.Address)
+			}
+		}
+
+		ln.CreateSVControlBlock(config)
+	}
+}
+
+func toTrgOps(triggerOptions *TriggerOptions) iec61850.TrgOps {
+	if triggerOptions == nil {
+		return iec61850.TrgOps{}
+	}
+	return iec61850.TrgOps{
+		DataChange:            triggerOptions.Dchg,
+		QualityChange:         triggerOptions.Qchg,
+		DataUpdate:            triggerOptions.Dupd,
+		TriggeredPeriodically: triggerOptions.Period,
+		Gi:                    triggerOptions.Gi,
+	}
+}
+
+func toPhyComAddress(address *PhyComAddress) *iec61850.PhyComAddress {
+	if address == nil {
+		return nil
+	}
+	phyComAddress := &iec61850.PhyComAddress{
+		VlanPriority: uint8(address.VlanPriority),
+		VlanId:       uint16(address.VlanId),
+		AppId:        uint16(address.AppId),
+	}
+	for i, mac := range address.MacAddress {
+		if i < len(phyComAddress.DstAddress) {
+			phyComAddress.DstAddress[i] = uint8(mac)
+		}
+	}
+	return phyComAddress
+}
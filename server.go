@@ -4,16 +4,47 @@ package iec61850
 import "C"
 
 import (
+	"sync"
 	"unsafe"
 )
 
 type IedServer struct {
-	server                      C.IedServer
-	serverConfig                ServerConfig
-	tlsConfig                   C.TLSConfiguration
+	server       C.IedServer
+	serverConfig ServerConfig
+	tlsConfig    C.TLSConfiguration
+	model        *IedModel
+	startOptions StartOptions
+	// access points added to the C server by StartWithOptions, they can't be removed
+	accessPoints []AccessPointAddress
+	// setup records every setting applied to the C server so it can be replayed when the server is rebuilt
+	setup                       []func()
+	identity                    *serverIdentity
 	clientAuthenticator         ClientAuthenticator
 	connectionIndicationHandler ConnectionIndicationHandler
 	rcbEventHandler             RCBEventHandler
+	// handlers by node, to find them when the access control monitors the node as well
+	writeAccessHandlers  map[*C.DataAttribute]*writeAccessCallback
+	performCheckHandlers map[*C.DataObject]*performCheckCallback
+	modelMonitored       bool
+	rbac                 *RBACConfig
+	// write access policies set by SetWriteAccessPolicy, libiec61850 ignores them for monitored attributes
+	writeAccessPolicies map[FC]AccessPolicy
+	readAccessHandler   ReadAccessHandler
+	readAccessInstalled bool
+	fileAccessHandler   FileAccessHandler
+	fileAccessInstalled bool
+	rcbEventInstalled   bool
+	stats               serverStats
+	logStorages         []C.LogStorage
+	goCBEventCallbackId int32
+	svcbEventCallbacks  map[*C.SVControlBlock]*svcbEventCallback
+	// GoEna set by EnableGoosePublishing and DisableGoosePublishing, and the GOOSE control blocks of
+	// libiec61850 known from their events, by reference
+	goCBLock sync.Mutex
+	goEna    map[string]bool
+	goCBs    map[string]C.MmsGooseControlBlock
+	// report control blocks of the model, libiec61850 reuses their list for the reports of the server
+	reportControlBlocks []*C.ReportControlBlock
 }
 
 func NewServerWithTlsSupport(serverConfig ServerConfig, tlsConfig *TLSConfig, iedModel *IedModel) (*IedServer, error) {
@@ -21,41 +52,84 @@ func NewServerWithTlsSupport(serverConfig ServerConfig, tlsConfig *TLSConfig, ie
 	if err != nil {
 		return nil, err
 	}
+	setTLSEventLogger(cTlsConfig, serverConfig.Logger)
 
 	config := serverConfig.createIedServerConfig(serverConfig)
 	defer C.IedServerConfig_destroy(config)
-	return &IedServer{
-		server:       C.IedServer_createWithConfig(iedModel.Model, cTlsConfig, config),
-		serverConfig: serverConfig,
-		tlsConfig:    cTlsConfig,
-	}, nil
+	rcbs := iedModel.reportControlBlocks()
+	is := &IedServer{
+		server:              C.IedServer_createWithConfig(iedModel.Model, cTlsConfig, config),
+		serverConfig:        serverConfig,
+		tlsConfig:           cTlsConfig,
+		model:               iedModel,
+		reportControlBlocks: rcbs,
+	}
+	is.trackConnections()
+	is.trackGoCBs()
+	is.trackTimeQuality()
+	return is, nil
 }
 
 func NewServerWithConfig(serverConfig ServerConfig, iedModel *IedModel) *IedServer {
 	config := serverConfig.createIedServerConfig(serverConfig)
 	defer C.IedServerConfig_destroy(config)
-	return &IedServer{
-		server:       C.IedServer_createWithConfig(iedModel.Model, nil, config),
-		serverConfig: serverConfig,
+	rcbs := iedModel.reportControlBlocks()
+	is := &IedServer{
+		server:              C.IedServer_createWithConfig(iedModel.Model, nil, config),
+		serverConfig:        serverConfig,
+		model:               iedModel,
+		reportControlBlocks: rcbs,
 	}
+	is.trackConnections()
+	is.trackGoCBs()
+	is.trackTimeQuality()
+	return is
+}
+
+// serverIdentity is the identity set by SetServerIdentity.
+type serverIdentity struct {
+	vendor  string
+	model   string
+	version string
 }
 
 // NewServer creates a new instance of the IedServer using the provided _iedModel.
 func NewServer(iedModel *IedModel) *IedServer {
-	return &IedServer{
-		server: C.IedServer_create(iedModel.Model),
+	rcbs := iedModel.reportControlBlocks()
+	is := &IedServer{
+		server:              C.IedServer_create(iedModel.Model),
+		model:               iedModel,
+		reportControlBlocks: rcbs,
 	}
+	is.trackConnections()
+	is.trackGoCBs()
+	is.trackTimeQuality()
+	return is
+}
+
+// apply runs fn against the current C server and remembers it for replay after a rebuild.
+func (is *IedServer) apply(fn func()) {
+	fn()
+	is.setup = append(is.setup, fn)
 }
 
 // SetWriteAccessPolicy changes the default write access policy for a given Functional Constraint (FC).
 func (is *IedServer) SetWriteAccessPolicy(fc FC, policy AccessPolicy) {
-	C.IedServer_setWriteAccessPolicy(is.server, C.FunctionalConstraint(fc), C.AccessPolicy(policy))
+	if is.writeAccessPolicies == nil {
+		is.writeAccessPolicies = make(map[FC]AccessPolicy)
+	}
+	is.writeAccessPolicies[fc] = policy
+	is.apply(func() {
+		C.IedServer_setWriteAccessPolicy(is.server, C.FunctionalConstraint(fc), C.AccessPolicy(policy))
+	})
 }
 
 // Start initiates the IedServer on the provided port.
 func (is *IedServer) Start(port int) {
-	C.IedServer_start(is.server, C.int(port))
-	// If there's another way to detect the error, handle it here.
+	is.startOptions = StartOptions{Port: port, AccessPoints: is.accessPoints}
+	if err := is.start(); err != nil {
+		is.logger().Error("start failed", "error", err)
+	}
 }
 
 // IsRunning checks if the IedServer is currently running.
@@ -65,12 +139,23 @@ func (is *IedServer) IsRunning() bool {
 
 // Stop terminates the IedServer.
 func (is *IedServer) Stop() {
+	if is.startOptions.Threadless {
+		C.IedServer_stopThreadless(is.server)
+		return
+	}
 	C.IedServer_stop(is.server)
 }
 
 // Destroy frees all resources associated with the IedServer.
 func (is *IedServer) Destroy() {
+	is.untrackTimeQuality()
 	C.IedServer_destroy(is.server)
+	is.resetGoCBs()
+	is.destroyLogStorages()
+	if is.tlsConfig != nil {
+		C.TLSConfiguration_destroy(is.tlsConfig)
+		is.tlsConfig = nil
+	}
 }
 
 // LockDataModel locks the data _iedModel of the IedServer.
@@ -137,6 +222,21 @@ func (is *IedServer) GetUTCTimeAttributeValue(node *ModelNode) int64 {
 	return int64(timestamp)
 }
 
+// GetTimestampAttributeValue reads the value of a time attribute in the server including its quality
+// flags, nil if the attribute is no time attribute.
+func (is *IedServer) GetTimestampAttributeValue(node *ModelNode) *Timestamp {
+	if node == nil || node._modelNode == nil {
+		return nil
+	}
+	mmsValue := C.IedServer_getAttributeValue(is.server, (*C.DataAttribute)(node._modelNode))
+	if mmsValue == nil || C.MmsValue_getType(mmsValue) != C.MMS_UTC_TIME {
+		return nil
+	}
+	timestamp := NewTimestamp()
+	C.Timestamp_setByMmsUtcTime(&timestamp.cTimestamp, mmsValue)
+	return timestamp
+}
+
 // GetNumberOfOpenConnections reads the amount of connections with the server
 func (is *IedServer) GetNumberOfOpenConnections() int {
 	return int(C.IedServer_getNumberOfOpenConnections(is.server))
@@ -144,15 +244,25 @@ func (is *IedServer) GetNumberOfOpenConnections() int {
 
 // SetServerIdentity updates the server identity of the IedServer
 func (is *IedServer) SetServerIdentity(vendor string, model string, version string) {
-	cVendor := C.CString(vendor)
-	cModel := C.CString(model)
-	cVersion := C.CString(version)
-
-	defer func() {
-		C.free(unsafe.Pointer(cVendor))
-		C.free(unsafe.Pointer(cModel))
-		C.free(unsafe.Pointer(cVersion))
-	}()
+	first := is.identity == nil
+	is.identity = &serverIdentity{vendor: vendor, model: model, version: version}
+
+	// the identity is applied once on rebuild, with the values of the last call
+	if first {
+		is.apply(is.applyServerIdentity)
+	} else {
+		is.applyServerIdentity()
+	}
+}
+
+// applyServerIdentity sets the identity on the C server, which copies the strings.
+func (is *IedServer) applyServerIdentity() {
+	cVendor := C.CString(is.identity.vendor)
+	defer C.free(unsafe.Pointer(cVendor))
+	cModel := C.CString(is.identity.model)
+	defer C.free(unsafe.Pointer(cModel))
+	cVersion := C.CString(is.identity.version)
+	defer C.free(unsafe.Pointer(cVersion))
 
 	C.IedServer_setServerIdentity(is.server, cVendor, cModel, cVersion)
 }
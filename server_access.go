@@ -0,0 +1,71 @@
+package iec61850
+
+/*
+#include <iec61850_server.h>
+
+extern MmsDataAccessError readAccessHandlerBridge(LogicalDevice* ld, LogicalNode* ln, DataObject* dataObject, FunctionalConstraint fc, ClientConnection connection, void* parameter);
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// ReadAccessHandler is invoked when a client reads data, node is the data object, or the logical node or
+// logical device when the client reads all of its data with the FC. Other results than
+// DATA_ACCESS_ERROR_SUCCESS deny the read with the error. The handler may update the values of the node
+// before they are sent, the data model is locked by the server.
+type ReadAccessHandler func(node *ModelNode, fc FC, connection *ClientConnection) MmsDataAccessError
+
+// SetReadAccessHandler sets the handler deciding on reads of clients. With role based access control the
+// handler is called for reads granted by the rules.
+//
+// libiec61850 1.5.3 has no public handlers for creating, deleting, reading and writing data sets, reading
+// their directory and listing the objects of the server, these requests can't be intercepted.
+func (is *IedServer) SetReadAccessHandler(handler ReadAccessHandler) {
+	is.readAccessHandler = handler
+	is.installReadAccessHandler()
+}
+
+// installReadAccessHandler installs the read access bridge for the access control and the read access handler.
+func (is *IedServer) installReadAccessHandler() {
+	if is.readAccessInstalled {
+		return
+	}
+	is.readAccessInstalled = true
+	cPtr := unsafe.Pointer(is)
+
+	is.apply(func() {
+		C.IedServer_setReadAccessHandler(is.server, (*[0]byte)(C.readAccessHandlerBridge), cPtr)
+	})
+}
+
+//export readAccessHandlerBridge
+func readAccessHandlerBridge(ld *C.LogicalDevice, ln *C.LogicalNode, dataObject *C.DataObject, fc C.FunctionalConstraint, connection C.ClientConnection, parameter unsafe.Pointer) (result C.MmsDataAccessError) {
+	is := (*IedServer)(parameter)
+	cNode := (*C.ModelNode)(unsafe.Pointer(ln))
+	if dataObject != nil {
+		cNode = (*C.ModelNode)(unsafe.Pointer(dataObject))
+	}
+	if cNode == nil {
+		cNode = (*C.ModelNode)(unsafe.Pointer(ld))
+	}
+	var node *ModelNode
+	var reference string
+	if cNode != nil {
+		node = newModelNode(cNode)
+		reference = node.ObjectReference
+	}
+
+	clientConnection := newClientConnection(connection)
+	defer func() {
+		is.stats.request(clientConnection, ACCESS_SERVICE_READ, result != C.DATA_ACCESS_ERROR_SUCCESS)
+	}()
+	if !is.accessAllowed(clientConnection, ACCESS_SERVICE_READ, reference, FC(fc)) {
+		return C.DATA_ACCESS_ERROR_OBJECT_ACCESS_DENIED
+	}
+	if is.readAccessHandler != nil {
+		return C.MmsDataAccessError(is.readAccessHandler(node, FC(fc), clientConnection))
+	}
+	return C.DATA_ACCESS_ERROR_SUCCESS
+}
@@ -2,33 +2,69 @@ package iec61850
 
 // #include <iec61850_server.h>
 import "C"
-import "unsafe"
+import (
+	"log/slog"
+	"unsafe"
+)
 
 // ServerConfig Configuration object to configure IEC 61850 stack features
+//
+// The maximum MMS PDU size and the TCP keepalive of the connections are build options of libiec61850
+// (CONFIG_MMS_MAXIMUM_PDU_SIZE, CONFIG_ACTIVATE_TCP_KEEPALIVE) and can't be configured here.
 type ServerConfig struct {
-	Edition                        uint8  // IEC 61850 edition (0 = edition 1, 1 = edition 2, 2 = edition 2.1, ...)
-	ReportBufferSize               int    // size of the report buffer associated with a buffered report control block
-	ReportBufferSizeForURCBs       int    // size of the report buffer associated with an unbuffered report control block
-	MaxConnections                 int    // maximum number of MMS (TCP) connections
-	SyncIntegrityReportTimes       bool   // integrity report start times will by synchronized with straight numbers
-	EnableFileService              bool   // when true (default) enable MMS file service
-	FileServiceBasePath            string // Base path (directory where the file service serves files
-	EnableDynamicDataSetService    bool   // when true (default) enable dynamic data set services for MMS
-	MaxAssociationSpecificDataSets int    // the maximum number of allowed association specific data sets
-	MaxDomainSpecificDataSets      int    // the maximum number of allowed domain specific data sets
-	MaxDataSetEntries              int    // maximum number of data set entries of dynamic data sets
-	EnableLogService               bool   // when true (default) enable log service
-	EnableEditSG                   bool   // enable EditSG service
-	EnableResvTmsForSGCB           bool   // enable visibility of SGCB.ResvTms
-	EnableResvTmsForBRCB           bool   // BRCB has resvTms attribute - only edition 2
-	EnableOwnerForRCB              bool   // RCB has owner attribute
-	UseIntegratedGoosePublisher    bool   // when true (default) the integrated GOOSE publisher is used
-	reportSettings                 ReportSetting
+	Edition                        uint8        // IEC 61850 edition (0 = edition 1, 1 = edition 2, 2 = edition 2.1, ...)
+	ReportBufferSize               int          // size of the report buffer associated with a buffered report control block
+	ReportBufferSizeForURCBs       int          // size of the report buffer associated with an unbuffered report control block
+	MaxConnections                 int          // maximum number of MMS (TCP) connections
+	SyncIntegrityReportTimes       bool         // integrity report start times will by synchronized with straight numbers
+	EnableFileService              bool         // when true (default) enable MMS file service
+	FileServiceBasePath            string       // Base path (directory where the file service serves files, see SetVirtualFilestore
+	EnableDynamicDataSetService    bool         // when true (default) enable dynamic data set services for MMS
+	MaxAssociationSpecificDataSets int          // the maximum number of allowed association specific data sets
+	MaxDomainSpecificDataSets      int          // the maximum number of allowed domain specific data sets
+	MaxDataSetEntries              int          // maximum number of data set entries of dynamic data sets
+	EnableLogService               bool         // when true (default) enable log service
+	EnableEditSG                   bool         // enable EditSG service
+	EnableResvTmsForSGCB           bool         // enable visibility of SGCB.ResvTms
+	EnableResvTmsForBRCB           bool         // BRCB has resvTms attribute - only edition 2
+	EnableOwnerForRCB              bool         // RCB has owner attribute
+	UseIntegratedGoosePublisher    bool         // when true (default) the integrated GOOSE publisher is used
+	Logger                         *slog.Logger // receives diagnostic output of the server, including TLS events
+	ReportSettings                 ReportSettings
+}
+
+// Report settings of SetReportSetting
+const (
+	REPORT_SETTING_RPT_ID     uint8 = C.IEC61850_REPORTSETTINGS_RPT_ID
+	REPORT_SETTING_BUF_TIME   uint8 = C.IEC61850_REPORTSETTINGS_BUF_TIME
+	REPORT_SETTING_DATSET     uint8 = C.IEC61850_REPORTSETTINGS_DATSET
+	REPORT_SETTING_TRG_OPS    uint8 = C.IEC61850_REPORTSETTINGS_TRG_OPS
+	REPORT_SETTING_OPT_FIELDS uint8 = C.IEC61850_REPORTSETTINGS_OPT_FIELDS
+	REPORT_SETTING_INTG_PD    uint8 = C.IEC61850_REPORTSETTINGS_INTG_PD
+)
+
+// ReportSettings tells which attributes of the report control blocks clients can only read, true for
+// "Conf", or also write, false for "Dyn", like the ReportSettings of the SCL file. The zero value keeps
+// all of them writable, the default of libiec61850.
+type ReportSettings struct {
+	ConfRptID     bool
+	ConfBufTm     bool
+	ConfDatSet    bool
+	ConfTrgOps    bool
+	ConfOptFields bool
+	ConfIntgPd    bool
 }
 
-type ReportSetting struct {
-	setting uint8
-	isDyn   bool
+// settings returns the isDyn flags by report setting.
+func (s ReportSettings) settings() map[uint8]bool {
+	return map[uint8]bool{
+		REPORT_SETTING_RPT_ID:     !s.ConfRptID,
+		REPORT_SETTING_BUF_TIME:   !s.ConfBufTm,
+		REPORT_SETTING_DATSET:     !s.ConfDatSet,
+		REPORT_SETTING_TRG_OPS:    !s.ConfTrgOps,
+		REPORT_SETTING_OPT_FIELDS: !s.ConfOptFields,
+		REPORT_SETTING_INTG_PD:    !s.ConfIntgPd,
+	}
 }
 
 // NewServerConfig creates a new ServerConfig object with default values
@@ -58,12 +94,22 @@ func NewServerConfig() ServerConfig {
 //
 // Parameters:
 //
-//	setting: one of IEC61850_REPORTSETTINGS_RPT_ID, _BUF_TIME, _DATSET, _TRG_OPS, _OPT_FIELDS, _INTG_PD
+//	setting: one of REPORT_SETTING_RPT_ID, _BUF_TIME, _DATSET, _TRG_OPS, _OPT_FIELDS, _INTG_PD
 //	isDyn: true, when setting is writable ("Dyn") or false, when read-only
-func (that ServerConfig) SetReportSetting(setting uint8, isDyn bool) {
-	that.reportSettings = ReportSetting{
-		setting: setting,
-		isDyn:   isDyn,
+func (that *ServerConfig) SetReportSetting(setting uint8, isDyn bool) {
+	switch setting {
+	case REPORT_SETTING_RPT_ID:
+		that.ReportSettings.ConfRptID = !isDyn
+	case REPORT_SETTING_BUF_TIME:
+		that.ReportSettings.ConfBufTm = !isDyn
+	case REPORT_SETTING_DATSET:
+		that.ReportSettings.ConfDatSet = !isDyn
+	case REPORT_SETTING_TRG_OPS:
+		that.ReportSettings.ConfTrgOps = !isDyn
+	case REPORT_SETTING_OPT_FIELDS:
+		that.ReportSettings.ConfOptFields = !isDyn
+	case REPORT_SETTING_INTG_PD:
+		that.ReportSettings.ConfIntgPd = !isDyn
 	}
 }
 
@@ -90,6 +136,8 @@ func (that ServerConfig) createIedServerConfig(serverConfig ServerConfig) C.IedS
 	C.IedServerConfig_enableResvTmsForBRCB(config, C.bool(serverConfig.EnableResvTmsForBRCB))
 	C.IedServerConfig_enableOwnerForRCB(config, C.bool(serverConfig.EnableOwnerForRCB))
 	C.IedServerConfig_useIntegratedGoosePublisher(config, C.bool(serverConfig.UseIntegratedGoosePublisher))
-	C.IedServerConfig_setReportSetting(config, C.uint8_t(serverConfig.reportSettings.setting), C.bool(serverConfig.reportSettings.isDyn))
+	for setting, isDyn := range serverConfig.ReportSettings.settings() {
+		C.IedServerConfig_setReportSetting(config, C.uint8_t(setting), C.bool(isDyn))
+	}
 	return config
 }
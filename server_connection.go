@@ -0,0 +1,87 @@
+package iec61850
+
+// #include <iec61850_server.h>
+import "C"
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// ClientConnection describes the client connection a server handler is called for.
+type ClientConnection struct {
+	// ID identifies the connection, it is unique for all connections of the process.
+	ID           uint64
+	PeerAddress  string // like "192.168.1.10:51234"
+	LocalAddress string
+	// SecurityToken is the token the ClientAuthenticator stored for the connection, nil if none.
+	SecurityToken unsafe.Pointer
+	// PeerCertificate is the TLS certificate of the client, only available when an authenticator is set.
+	PeerCertificate []byte
+}
+
+// clientSession keeps the Go side state of a client connection until it is closed.
+type clientSession struct {
+	id              uint64
+	securityToken   unsafe.Pointer
+	peerCertificate []byte
+}
+
+var (
+	clientSessionIdGen = atomic.Uint64{}
+	clientSessionsLock sync.Mutex
+	// clientSessions by C connection
+	clientSessions = make(map[C.ClientConnection]*clientSession)
+	// authenticatedSessions by the ID stored as security token, until the connection is first seen
+	authenticatedSessions = make(map[uint64]*clientSession)
+)
+
+// newAuthenticatedSession remembers the result of the authenticator, the returned ID is stored as
+// security token in libiec61850 to find the session again.
+func newAuthenticatedSession(securityToken unsafe.Pointer, peerCertificate []byte) uint64 {
+	session := &clientSession{
+		id:              clientSessionIdGen.Add(1),
+		securityToken:   securityToken,
+		peerCertificate: peerCertificate,
+	}
+	clientSessionsLock.Lock()
+	defer clientSessionsLock.Unlock()
+	authenticatedSessions[session.id] = session
+	return session.id
+}
+
+// newClientConnection copies the details of a connection, nil stays nil.
+func newClientConnection(connection C.ClientConnection) *ClientConnection {
+	if connection == nil {
+		return nil
+	}
+
+	clientSessionsLock.Lock()
+	session, ok := clientSessions[connection]
+	if !ok {
+		id := uint64(uintptr(C.ClientConnection_getSecurityToken(connection)))
+		if session, ok = authenticatedSessions[id]; ok {
+			delete(authenticatedSessions, id)
+		} else {
+			session = &clientSession{id: clientSessionIdGen.Add(1)}
+		}
+		clientSessions[connection] = session
+	}
+	clientSessionsLock.Unlock()
+
+	return &ClientConnection{
+		ID:              session.id,
+		PeerAddress:     C.GoString(C.ClientConnection_getPeerAddress(connection)),
+		LocalAddress:    C.GoString(C.ClientConnection_getLocalAddress(connection)),
+		SecurityToken:   session.securityToken,
+		PeerCertificate: session.peerCertificate,
+	}
+}
+
+// closeClientConnection forgets the session of a closed connection.
+func closeClientConnection(connection C.ClientConnection) {
+	clientSessionsLock.Lock()
+	defer clientSessionsLock.Unlock()
+	delete(clientSessions, connection)
+}
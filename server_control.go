@@ -0,0 +1,295 @@
+package iec61850
+
+/*
+#include <iec61850_server.h>
+
+extern CheckHandlerResult performCheckHandlerBridge(ControlAction action, void* parameter, MmsValue* ctlVal, bool test, bool interlockCheck);
+extern ControlHandlerResult waitForExecutionHandlerBridge(ControlAction action, void* parameter, MmsValue* ctlVal, bool test, bool synchroCheck);
+extern void selectStateChangedHandlerBridge(ControlAction action, void* parameter, bool isSelected, SelectStateChangedReason reason);
+*/
+import "C"
+
+import (
+	"log/slog"
+	"sync"
+	"unsafe"
+)
+
+var (
+	performCheckCallbacks       = make(map[int32]*performCheckCallback)
+	waitForExecutionCallbacks   = make(map[int32]*waitForExecutionCallback)
+	selectStateChangedCallbacks = make(map[int32]*selectStateChangedCallback)
+)
+
+// ControlPerformCheckHandler runs the static checks of select and operate, like the interlock check
+// when interlockCheck is set. Call ControlAction.SetAddCause to tell the client why the check failed.
+type ControlPerformCheckHandler func(node *ModelNode, action *ControlAction, mmsValue *MmsValue, test bool, interlockCheck bool) CheckHandlerResult
+
+// ControlWaitForExecutionHandler runs the dynamic checks before the operate, like the synchro check
+// when synchroCheck is set. It may return CONTROL_RESULT_WAITING and finish later with ControlAction.Complete.
+type ControlWaitForExecutionHandler func(node *ModelNode, action *ControlAction, mmsValue *MmsValue, test bool, synchroCheck bool) ControlHandlerResult
+
+// ControlSelectStateChangedHandler is invoked when a control object is selected or unselected.
+type ControlSelectStateChangedHandler func(node *ModelNode, action *ControlAction, isSelected bool, reason SelectStateChangedReason)
+
+type performCheckCallback struct {
+	server  *IedServer
+	node    *ModelNode
+	handler ControlPerformCheckHandler // nil for controls only monitored by the access control
+	logger  *slog.Logger
+}
+
+type waitForExecutionCallback struct {
+	server       *IedServer
+	node         *ModelNode
+	handler      ControlWaitForExecutionHandler
+	logger       *slog.Logger
+	terminations controlTerminations
+}
+
+type selectStateChangedCallback struct {
+	node    *ModelNode
+	handler ControlSelectStateChangedHandler
+}
+
+// controlTermination holds the result of a control action whose handler returned CONTROL_RESULT_WAITING
+// until ControlAction.Complete is called. Every invocation of a handler has its own termination, so a
+// late Complete never finishes another action.
+type controlTermination struct {
+	mu       sync.Mutex
+	done     bool
+	success  bool
+	addCause ControlAddCause
+}
+
+func (t *controlTermination) complete(success bool, addCause ControlAddCause) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.done {
+		t.done = true
+		t.success = success
+		t.addCause = addCause
+	}
+}
+
+// controlTerminations holds the waiting control actions of a control object by their action.
+type controlTerminations struct {
+	mu      sync.Mutex
+	actions map[C.ControlAction]*controlTermination
+}
+
+// start registers the termination of an action whose handler returned CONTROL_RESULT_WAITING.
+func (t *controlTerminations) start(action C.ControlAction, termination *controlTermination) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.actions == nil {
+		t.actions = make(map[C.ControlAction]*controlTermination)
+	}
+	t.actions[action] = termination
+}
+
+// poll returns the result of a waiting control action, waiting is false if the action is not in progress.
+// The action is removed once it is finished.
+func (t *controlTerminations) poll(action C.ControlAction) (result ControlHandlerResult, waiting bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	termination, ok := t.actions[action]
+	if !ok {
+		return CONTROL_RESULT_FAILED, false
+	}
+
+	termination.mu.Lock()
+	defer termination.mu.Unlock()
+	if !termination.done {
+		return CONTROL_RESULT_WAITING, true
+	}
+	delete(t.actions, action)
+	if termination.success {
+		return CONTROL_RESULT_OK, true
+	}
+	C.ControlAction_setAddCause(action, C.ControlAddCause(termination.addCause))
+	return CONTROL_RESULT_FAILED, true
+}
+
+func newControlAction(action C.ControlAction, termination *controlTermination) *ControlAction {
+	var (
+		orIdentSize C.int
+		orIdent     []byte
+	)
+	orIdentBuffer := C.ControlAction_getOrIdent(action, &orIdentSize)
+	if orIdentBuffer != nil {
+		orIdent = C.GoBytes(unsafe.Pointer(orIdentBuffer), orIdentSize)
+	}
+
+	return &ControlAction{
+		ControlTime:      uint64(C.ControlAction_getControlTime(action)),
+		IsSelect:         bool(C.ControlAction_isSelect(action)),
+		InterlockCheck:   bool(C.ControlAction_getInterlockCheck(action)),
+		SynchroCheck:     bool(C.ControlAction_getSynchroCheck(action)),
+		CtlNum:           int(C.ControlAction_getCtlNum(action)),
+		OrIdent:          orIdent,
+		OrCat:            int(C.ControlAction_getOrCat(action)),
+		ClientConnection: newClientConnection(C.ControlAction_getClientConnection(action)),
+		action:           action,
+		termination:      termination,
+	}
+}
+
+// SetAddCause sets the AddCause sent to the client when the control fails. It must be called
+// inside the handler.
+func (a *ControlAction) SetAddCause(addCause ControlAddCause) {
+	C.ControlAction_setAddCause(a.action, C.ControlAddCause(addCause))
+}
+
+// SetError sets the error code of the LastApplError sent to the client when the control fails.
+// It must be called inside the handler.
+func (a *ControlAction) SetError(err ControlLastApplError) {
+	C.ControlAction_setError(a.action, C.ControlLastApplError(err))
+}
+
+// Complete finishes a control action whose operate or wait-for-execution handler returned
+// CONTROL_RESULT_WAITING. It may be called from any goroutine after the handler returned.
+// For the operate of controls with enhanced security the server then sends the CommandTermination,
+// which is negative with addCause when success is false.
+func (a *ControlAction) Complete(success bool, addCause ControlAddCause) {
+	if a.termination != nil {
+		a.termination.complete(success, addCause)
+	}
+}
+
+//export performCheckHandlerBridge
+func performCheckHandlerBridge(action C.ControlAction, parameter unsafe.Pointer, ctlVal *C.MmsValue, test C.bool, interlockCheck C.bool) (result C.CheckHandlerResult) {
+	callbackId := int32(uintptr(parameter))
+	if call, ok := performCheckCallbacks[callbackId]; ok {
+		controlAction := newControlAction(action, nil)
+		defer func() {
+			accepted := result == C.CONTROL_ACCEPTED
+			call.server.stats.controlCheck(controlAction.IsSelect, accepted)
+			call.server.stats.request(controlAction.ClientConnection, ACCESS_SERVICE_CONTROL, !accepted)
+		}()
+		if !call.server.accessAllowed(controlAction.ClientConnection, ACCESS_SERVICE_CONTROL, call.node.ObjectReference, NONE) {
+			C.ControlAction_setAddCause(action, C.ADD_CAUSE_NO_ACCESS_AUTHORITY)
+			return C.CONTROL_OBJECT_ACCESS_DENIED
+		}
+		if call.handler == nil {
+			return C.CONTROL_ACCEPTED
+		}
+
+		// ctlVal is nil for a select without value
+		var mmsValue *MmsValue
+		if ctlVal != nil {
+			mmsType := MmsType(C.MmsValue_getType(ctlVal))
+			goValue, err := toGoValue(ctlVal, mmsType)
+			if err != nil {
+				call.logger.Error("control check rejected, ctlVal cannot be converted", "ref", call.node.ObjectReference, "error", err)
+				return C.CONTROL_VALUE_INVALID
+			}
+			mmsValue = &MmsValue{mmsType, goValue}
+		}
+		result := call.handler(call.node, controlAction, mmsValue, bool(test), bool(interlockCheck))
+		return C.CheckHandlerResult(result)
+	}
+	return C.CONTROL_ACCEPTED
+}
+
+//export waitForExecutionHandlerBridge
+func waitForExecutionHandlerBridge(action C.ControlAction, parameter unsafe.Pointer, ctlVal *C.MmsValue, test C.bool, synchroCheck C.bool) C.ControlHandlerResult {
+	callbackId := int32(uintptr(parameter))
+	if call, ok := waitForExecutionCallbacks[callbackId]; ok {
+		if result, waiting := call.terminations.poll(action); waiting {
+			if result == CONTROL_RESULT_FAILED {
+				call.server.stats.operateFailed()
+			}
+			return C.ControlHandlerResult(result)
+		}
+
+		mmsType := MmsType(C.MmsValue_getType(ctlVal))
+		goValue, err := toGoValue(ctlVal, mmsType)
+		if err != nil {
+			call.logger.Error("control rejected, ctlVal cannot be converted", "ref", call.node.ObjectReference, "error", err)
+			call.server.stats.operateFailed()
+			return C.CONTROL_RESULT_FAILED
+		}
+		termination := &controlTermination{}
+		result := call.handler(call.node, newControlAction(action, termination), &MmsValue{mmsType, goValue}, bool(test), bool(synchroCheck))
+		if result == CONTROL_RESULT_WAITING {
+			call.terminations.start(action, termination)
+		}
+		if result == CONTROL_RESULT_FAILED {
+			call.server.stats.operateFailed()
+		}
+		return C.ControlHandlerResult(result)
+	}
+	return C.CONTROL_RESULT_OK
+}
+
+//export selectStateChangedHandlerBridge
+func selectStateChangedHandlerBridge(action C.ControlAction, parameter unsafe.Pointer, isSelected C.bool, reason C.SelectStateChangedReason) {
+	callbackId := int32(uintptr(parameter))
+	if call, ok := selectStateChangedCallbacks[callbackId]; ok {
+		call.handler(call.node, newControlAction(action, nil), bool(isSelected), SelectStateChangedReason(reason))
+	}
+}
+
+// SetPerformCheckHandler sets the handler for the static checks of select and operate of the control object.
+func (is *IedServer) SetPerformCheckHandler(modelNode *ModelNode, handler ControlPerformCheckHandler) {
+	if modelNode == nil {
+		return
+	}
+
+	callbackId := callbackIdGen.Add(1)
+	cPtr := intToPointerBug58625(callbackId)
+	call := &performCheckCallback{
+		server:  is,
+		node:    modelNode,
+		handler: handler,
+		logger:  is.logger(),
+	}
+	performCheckCallbacks[callbackId] = call
+	if is.performCheckHandlers == nil {
+		is.performCheckHandlers = make(map[*C.DataObject]*performCheckCallback)
+	}
+	is.performCheckHandlers[(*C.DataObject)(modelNode._modelNode)] = call
+
+	is.apply(func() {
+		C.IedServer_setPerformCheckHandler(is.server, (*C.DataObject)(modelNode._modelNode), (*[0]byte)(C.performCheckHandlerBridge), cPtr)
+	})
+}
+
+// SetWaitForExecutionHandler sets the handler for the dynamic checks before the operate of the control object.
+func (is *IedServer) SetWaitForExecutionHandler(modelNode *ModelNode, handler ControlWaitForExecutionHandler) {
+	if modelNode == nil {
+		return
+	}
+
+	callbackId := callbackIdGen.Add(1)
+	cPtr := intToPointerBug58625(callbackId)
+	waitForExecutionCallbacks[callbackId] = &waitForExecutionCallback{
+		server:  is,
+		node:    modelNode,
+		handler: handler,
+		logger:  is.logger(),
+	}
+
+	is.apply(func() {
+		C.IedServer_setWaitForExecutionHandler(is.server, (*C.DataObject)(modelNode._modelNode), (*[0]byte)(C.waitForExecutionHandlerBridge), cPtr)
+	})
+}
+
+// SetSelectStateChangedHandler sets the handler that is invoked when the control object is selected or unselected.
+func (is *IedServer) SetSelectStateChangedHandler(modelNode *ModelNode, handler ControlSelectStateChangedHandler) {
+	if modelNode == nil {
+		return
+	}
+
+	callbackId := callbackIdGen.Add(1)
+	cPtr := intToPointerBug58625(callbackId)
+	selectStateChangedCallbacks[callbackId] = &selectStateChangedCallback{
+		node:    modelNode,
+		handler: handler,
+	}
+
+	is.apply(func() {
+		C.IedServer_setSelectStateChangedHandler(is.server, (*C.DataObject)(modelNode._modelNode), (*[0]byte)(C.selectStateChangedHandlerBridge), cPtr)
+	})
+}
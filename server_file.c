@@ -0,0 +1,157 @@
+#include "server_file.h"
+
+#include <dirent.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include <sys/stat.h>
+
+// The file provider of the HAL of libiec61850, which is replaced by this one for the whole process. Paths
+// of virtual filestores are handled by Go, all other paths by the file system like the file provider of
+// libiec61850.
+//
+// All functions of the file provider of the HAL are defined here, so the linker never takes its object
+// (file_provider_linux.c, file_provider_win32.c) from libhal.a and the symbols are not duplicated. A new
+// function in the file provider of libiec61850 has to be added here as well.
+
+struct sFileHandle {
+    FILE* file;
+    int virtualHandle;
+};
+
+struct sDirectoryHandle {
+    DIR* dir;
+    char* path;
+    int virtualHandle;
+};
+
+static bool isVirtual(const char* pathName) {
+    return strncmp(pathName, VIRTUAL_FILESTORE_PREFIX, strlen(VIRTUAL_FILESTORE_PREFIX)) == 0;
+}
+
+FileHandle FileSystem_openFile(char* pathName, bool readWrite) {
+    struct sFileHandle* handle;
+
+    if (isVirtual(pathName)) {
+        int virtualHandle = vfsOpenFile(pathName, readWrite);
+        if (virtualHandle == 0)
+            return NULL;
+        handle = (struct sFileHandle*) calloc(1, sizeof(struct sFileHandle));
+        handle->virtualHandle = virtualHandle;
+        return handle;
+    }
+
+    FILE* file = fopen(pathName, readWrite ? "wb" : "rb");
+    if (file == NULL)
+        return NULL;
+    handle = (struct sFileHandle*) calloc(1, sizeof(struct sFileHandle));
+    handle->file = file;
+    return handle;
+}
+
+int FileSystem_readFile(FileHandle fileHandle, uint8_t* buffer, int maxSize) {
+    struct sFileHandle* handle = (struct sFileHandle*) fileHandle;
+
+    if (handle->virtualHandle != 0)
+        return vfsReadFile(handle->virtualHandle, buffer, maxSize);
+    return (int) fread(buffer, 1, maxSize, handle->file);
+}
+
+int FileSystem_writeFile(FileHandle fileHandle, uint8_t* buffer, int size) {
+    struct sFileHandle* handle = (struct sFileHandle*) fileHandle;
+
+    if (handle->virtualHandle != 0)
+        return vfsWriteFile(handle->virtualHandle, buffer, size);
+    return (int) fwrite(buffer, 1, size, handle->file);
+}
+
+void FileSystem_closeFile(FileHandle fileHandle) {
+    struct sFileHandle* handle = (struct sFileHandle*) fileHandle;
+
+    if (handle->virtualHandle != 0)
+        vfsCloseFile(handle->virtualHandle);
+    else
+        fclose(handle->file);
+    free(handle);
+}
+
+bool FileSystem_getFileInfo(char* filename, uint32_t* fileSize, uint64_t* lastModificationTimestamp) {
+    if (isVirtual(filename))
+        return vfsGetFileInfo(filename, fileSize, lastModificationTimestamp);
+
+    struct stat fileStats;
+    if (stat(filename, &fileStats) == -1)
+        return false;
+    if (lastModificationTimestamp != NULL)
+        *lastModificationTimestamp = (uint64_t) fileStats.st_mtime * 1000LL;
+    if (fileSize != NULL)
+        *fileSize = (uint32_t) fileStats.st_size;
+    return true;
+}
+
+bool FileSystem_deleteFile(char* filename) {
+    if (isVirtual(filename))
+        return vfsDeleteFile(filename);
+    return remove(filename) == 0;
+}
+
+bool FileSystem_renameFile(char* oldFilename, char* newFilename) {
+    if (isVirtual(oldFilename))
+        return isVirtual(newFilename) && vfsRenameFile(oldFilename, newFilename);
+    return rename(oldFilename, newFilename) == 0;
+}
+
+DirectoryHandle FileSystem_openDirectory(char* directoryName) {
+    DirectoryHandle handle;
+
+    if (isVirtual(directoryName)) {
+        int virtualHandle = vfsOpenDirectory(directoryName);
+        if (virtualHandle == 0)
+            return NULL;
+        handle = (DirectoryHandle) calloc(1, sizeof(struct sDirectoryHandle));
+        handle->virtualHandle = virtualHandle;
+        return handle;
+    }
+
+    DIR* dir = opendir(directoryName);
+    if (dir == NULL)
+        return NULL;
+    handle = (DirectoryHandle) calloc(1, sizeof(struct sDirectoryHandle));
+    handle->dir = dir;
+    handle->path = strdup(directoryName);
+    return handle;
+}
+
+char* FileSystem_readDirectory(DirectoryHandle directory, bool* isDirectory) {
+    if (directory->virtualHandle != 0)
+        return vfsReadDirectory(directory->virtualHandle, isDirectory);
+
+    struct dirent* entry;
+    while ((entry = readdir(directory->dir)) != NULL) {
+        // hidden files, "." and ".." are not listed
+        if (entry->d_name[0] == '.')
+            continue;
+
+        if (isDirectory != NULL) {
+            size_t length = strlen(directory->path) + strlen(entry->d_name) + 2;
+            char* path = (char*) malloc(length);
+            struct stat fileStats;
+            snprintf(path, length, "%s/%s", directory->path, entry->d_name);
+            *isDirectory = stat(path, &fileStats) == 0 && S_ISDIR(fileStats.st_mode);
+            free(path);
+        }
+        return entry->d_name;
+    }
+    return NULL;
+}
+
+void FileSystem_closeDirectory(DirectoryHandle directory) {
+    if (directory->virtualHandle != 0) {
+        vfsCloseDirectory(directory->virtualHandle);
+    }
+    else {
+        closedir(directory->dir);
+        free(directory->path);
+    }
+    free(directory);
+}
@@ -0,0 +1,358 @@
+package iec61850
+
+/*
+#include <iec61850_server.h>
+#include <mms_server.h>
+#include "server_file.h"
+
+extern MmsError fileAccessHandlerBridge(void* parameter, MmsServerConnection connection, MmsFileServiceType service, char* localFilename, char* otherFilename);
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// FileService is a file service requested by a client.
+type FileService int
+
+const (
+	FILE_SERVICE_READ_DIRECTORY FileService = C.MMS_FILE_ACCESS_TYPE_READ_DIRECTORY
+	FILE_SERVICE_OPEN           FileService = C.MMS_FILE_ACCESS_TYPE_OPEN
+	// FILE_SERVICE_OBTAIN writes a file of the client to the server
+	FILE_SERVICE_OBTAIN FileService = C.MMS_FILE_ACCESS_TYPE_OBTAIN
+	FILE_SERVICE_DELETE FileService = C.MMS_FILE_ACCESS_TYPE_DELETE
+	FILE_SERVICE_RENAME FileService = C.MMS_FILE_ACCESS_TYPE_RENAME
+)
+
+func (s FileService) String() string {
+	switch s {
+	case FILE_SERVICE_READ_DIRECTORY:
+		return "readDirectory"
+	case FILE_SERVICE_OPEN:
+		return "open"
+	case FILE_SERVICE_OBTAIN:
+		return "obtain"
+	case FILE_SERVICE_DELETE:
+		return "delete"
+	case FILE_SERVICE_RENAME:
+		return "rename"
+	}
+	return "unknown"
+}
+
+// FileAccessHandler is invoked when a client requests a file service. name is the file or directory at the
+// server, otherName the source file of the client for FILE_SERVICE_OBTAIN or the new name for
+// FILE_SERVICE_RENAME. Other results than MMS_ERROR_NONE deny the request, like MMS_ERROR_FILE_FILE_ACCESS_DENIED.
+type FileAccessHandler func(connection *ClientConnection, service FileService, name string, otherName string) MmsError
+
+// SetFileAccessHandler sets the handler deciding on the file services requested by clients. With role based
+// access control the handler is called for requests granted by the rules.
+func (is *IedServer) SetFileAccessHandler(handler FileAccessHandler) {
+	is.fileAccessHandler = handler
+	is.installFileAccessHandler()
+}
+
+// installFileAccessHandler installs the file access bridge for the access control and the file access handler.
+func (is *IedServer) installFileAccessHandler() {
+	if is.fileAccessInstalled {
+		return
+	}
+	is.fileAccessInstalled = true
+	cPtr := unsafe.Pointer(is)
+
+	is.apply(func() {
+		C.MmsServer_installFileAccessHandler(C.IedServer_getMmsServer(is.server), (*[0]byte)(C.fileAccessHandlerBridge), cPtr)
+	})
+}
+
+//export fileAccessHandlerBridge
+func fileAccessHandlerBridge(parameter unsafe.Pointer, connection C.MmsServerConnection, service C.MmsFileServiceType, localFilename *C.char, otherFilename *C.char) (result C.MmsError) {
+	is := (*IedServer)(parameter)
+	clientConnection := is.clientConnectionOf(connection)
+	defer func() {
+		is.stats.request(clientConnection, ACCESS_SERVICE_FILE, result != C.MMS_ERROR_NONE)
+	}()
+	var names [2]string
+	for i, filename := range []*C.char{localFilename, otherFilename} {
+		if filename == nil {
+			continue
+		}
+		names[i] = strings.TrimPrefix(C.GoString(filename), "/")
+		if !is.accessAllowed(clientConnection, ACCESS_SERVICE_FILE, names[i], NONE) {
+			return C.MMS_ERROR_FILE_FILE_ACCESS_DENIED
+		}
+	}
+	if is.fileAccessHandler != nil {
+		return C.MmsError(is.fileAccessHandler(clientConnection, FileService(service), names[0], names[1]))
+	}
+	return C.MMS_ERROR_NONE
+}
+
+// VirtualFilestore provides the files of the MMS file services, like COMTRADE records kept in memory.
+type VirtualFilestore struct {
+	// FS has the files and directories read by clients.
+	FS fs.FS
+	// Create returns the writer for a file obtained from a client, nil denies writing files.
+	Create func(name string) (io.WriteCloser, error)
+	// Remove deletes a file, nil denies deleting files.
+	Remove func(name string) error
+	// Rename renames a file, nil denies renaming files.
+	Rename func(oldName, newName string) error
+}
+
+// virtualFilestorePrefix is the beginning of the paths of the virtual filestores, as in server_file.h.
+const virtualFilestorePrefix = "go-vfs:"
+
+type virtualFilestore struct {
+	VirtualFilestore
+	server *IedServer
+}
+
+type virtualFile struct {
+	name   string
+	reader fs.File
+	writer io.WriteCloser
+}
+
+type virtualDirectory struct {
+	entries []fs.DirEntry
+	next    int
+	name    *C.char // name of the last entry read, freed with the next one
+}
+
+var (
+	virtualFilestoresLock sync.Mutex
+	virtualFilestores     = make(map[int32]*virtualFilestore)
+	// open files and directories by handle
+	virtualHandleGen int32
+	virtualHandles   = make(map[int32]any)
+)
+
+// SetVirtualFilestore serves the MMS file services from the virtual filestore instead of the
+// FileServiceBasePath of the ServerConfig.
+//
+// The package replaces the file system functions of the HAL of libiec61850 to do so, for the whole
+// process and even without virtual filestores. Files of the FileServiceBasePath of every server and all
+// other files of libiec61850, like of the client file services, go through this replacement, which
+// passes paths outside of virtual filestores to the file system like libiec61850 does.
+func (is *IedServer) SetVirtualFilestore(filestore VirtualFilestore) error {
+	if filestore.FS == nil {
+		return fmt.Errorf("SetVirtualFilestore: no FS: %w", UserProvidedInvalidArgument)
+	}
+	id := callbackIdGen.Add(1)
+	virtualFilestoresLock.Lock()
+	virtualFilestores[id] = &virtualFilestore{VirtualFilestore: filestore, server: is}
+	virtualFilestoresLock.Unlock()
+
+	basePath := virtualFilestorePrefix + strconv.Itoa(int(id)) + "/"
+	is.apply(func() {
+		cBasePath := C.CString(basePath)
+		defer C.free(unsafe.Pointer(cBasePath))
+		C.IedServer_setFilestoreBasepath(is.server, cBasePath)
+	})
+	return nil
+}
+
+// lookupVirtualPath returns the virtual filestore and the name in its FS of a path like "go-vfs:3/COMTRADE/rec.cfg".
+func lookupVirtualPath(pathName *C.char) (*virtualFilestore, string) {
+	idText, name, _ := strings.Cut(strings.TrimPrefix(C.GoString(pathName), virtualFilestorePrefix), "/")
+	id, err := strconv.Atoi(idText)
+	if err != nil {
+		return nil, ""
+	}
+	virtualFilestoresLock.Lock()
+	filestore := virtualFilestores[int32(id)]
+	virtualFilestoresLock.Unlock()
+
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		name = "."
+	}
+	return filestore, name
+}
+
+func addVirtualHandle(value any) C.int {
+	virtualFilestoresLock.Lock()
+	defer virtualFilestoresLock.Unlock()
+	virtualHandleGen++
+	virtualHandles[virtualHandleGen] = value
+	return C.int(virtualHandleGen)
+}
+
+func virtualHandle(handle C.int) any {
+	virtualFilestoresLock.Lock()
+	defer virtualFilestoresLock.Unlock()
+	return virtualHandles[int32(handle)]
+}
+
+func removeVirtualHandle(handle C.int) any {
+	virtualFilestoresLock.Lock()
+	defer virtualFilestoresLock.Unlock()
+	value := virtualHandles[int32(handle)]
+	delete(virtualHandles, int32(handle))
+	return value
+}
+
+//export vfsOpenFile
+func vfsOpenFile(pathName *C.char, readWrite C.bool) C.int {
+	filestore, name := lookupVirtualPath(pathName)
+	if filestore == nil {
+		return 0
+	}
+	if readWrite {
+		if filestore.Create == nil {
+			return 0
+		}
+		writer, err := filestore.Create(name)
+		if err != nil {
+			filestore.server.logger().Warn("virtual file not created", "name", name, "error", err)
+			return 0
+		}
+		return addVirtualHandle(&virtualFile{name: name, writer: writer})
+	}
+	reader, err := filestore.FS.Open(name)
+	if err != nil {
+		return 0
+	}
+	return addVirtualHandle(&virtualFile{name: name, reader: reader})
+}
+
+//export vfsReadFile
+func vfsReadFile(handle C.int, buffer *C.uint8_t, maxSize C.int) C.int {
+	file, ok := virtualHandle(handle).(*virtualFile)
+	if !ok || file.reader == nil {
+		return -1
+	}
+	n, err := io.ReadFull(file.reader, unsafe.Slice((*byte)(buffer), int(maxSize)))
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return -1
+	}
+	return C.int(n)
+}
+
+//export vfsWriteFile
+func vfsWriteFile(handle C.int, buffer *C.uint8_t, size C.int) C.int {
+	file, ok := virtualHandle(handle).(*virtualFile)
+	if !ok || file.writer == nil {
+		return -1
+	}
+	n, err := file.writer.Write(unsafe.Slice((*byte)(buffer), int(size)))
+	if err != nil {
+		return -1
+	}
+	return C.int(n)
+}
+
+//export vfsCloseFile
+func vfsCloseFile(handle C.int) {
+	file, ok := removeVirtualHandle(handle).(*virtualFile)
+	if !ok {
+		return
+	}
+	if file.reader != nil {
+		file.reader.Close()
+	}
+	if file.writer != nil {
+		file.writer.Close()
+	}
+}
+
+//export vfsGetFileInfo
+func vfsGetFileInfo(pathName *C.char, fileSize *C.uint32_t, lastModificationTimestamp *C.uint64_t) C.bool {
+	filestore, name := lookupVirtualPath(pathName)
+	if filestore == nil {
+		return false
+	}
+	info, err := fs.Stat(filestore.FS, name)
+	if err != nil {
+		return false
+	}
+	if fileSize != nil {
+		*fileSize = C.uint32_t(info.Size())
+	}
+	if lastModificationTimestamp != nil {
+		*lastModificationTimestamp = C.uint64_t(info.ModTime().UnixMilli())
+	}
+	return true
+}
+
+//export vfsDeleteFile
+func vfsDeleteFile(pathName *C.char) C.bool {
+	filestore, name := lookupVirtualPath(pathName)
+	if filestore == nil || filestore.Remove == nil {
+		return false
+	}
+	if err := filestore.Remove(name); err != nil {
+		filestore.server.logger().Warn("virtual file not deleted", "name", name, "error", err)
+		return false
+	}
+	return true
+}
+
+//export vfsRenameFile
+func vfsRenameFile(oldPathName *C.char, newPathName *C.char) C.bool {
+	filestore, oldName := lookupVirtualPath(oldPathName)
+	newFilestore, newName := lookupVirtualPath(newPathName)
+	if filestore == nil || filestore != newFilestore || filestore.Rename == nil {
+		return false
+	}
+	if err := filestore.Rename(oldName, newName); err != nil {
+		filestore.server.logger().Warn("virtual file not renamed", "name", oldName, "newName", newName, "error", err)
+		return false
+	}
+	return true
+}
+
+//export vfsOpenDirectory
+func vfsOpenDirectory(pathName *C.char) C.int {
+	filestore, name := lookupVirtualPath(pathName)
+	if filestore == nil {
+		return 0
+	}
+	entries, err := fs.ReadDir(filestore.FS, name)
+	if err != nil {
+		return 0
+	}
+	// hidden files are not listed, like by the file system of libiec61850
+	entries = slices.DeleteFunc(entries, func(entry fs.DirEntry) bool {
+		return strings.HasPrefix(entry.Name(), ".")
+	})
+	return addVirtualHandle(&virtualDirectory{entries: entries})
+}
+
+//export vfsReadDirectory
+func vfsReadDirectory(handle C.int, isDirectory *C.bool) *C.char {
+	directory, ok := virtualHandle(handle).(*virtualDirectory)
+	if !ok {
+		return nil
+	}
+	C.free(unsafe.Pointer(directory.name))
+	directory.name = nil
+	if directory.next >= len(directory.entries) {
+		return nil
+	}
+	entry := directory.entries[directory.next]
+	directory.next++
+	if isDirectory != nil {
+		*isDirectory = C.bool(entry.IsDir())
+	}
+	directory.name = C.CString(entry.Name())
+	return directory.name
+}
+
+//export vfsCloseDirectory
+func vfsCloseDirectory(handle C.int) {
+	if directory, ok := removeVirtualHandle(handle).(*virtualDirectory); ok {
+		C.free(unsafe.Pointer(directory.name))
+	}
+}
@@ -0,0 +1,18 @@
+#include <hal_filesystem.h>
+#include <stdbool.h>
+#include <stdint.h>
+
+// Paths of the virtual filestores, like "go-vfs:3/COMTRADE/rec.cfg", are served by the Go functions.
+#define VIRTUAL_FILESTORE_PREFIX "go-vfs:"
+
+// Declare the Go functions
+int vfsOpenFile(char* pathName, bool readWrite);
+int vfsReadFile(int handle, uint8_t* buffer, int maxSize);
+int vfsWriteFile(int handle, uint8_t* buffer, int size);
+void vfsCloseFile(int handle);
+bool vfsGetFileInfo(char* filename, uint32_t* fileSize, uint64_t* lastModificationTimestamp);
+bool vfsDeleteFile(char* filename);
+bool vfsRenameFile(char* oldFilename, char* newFilename);
+int vfsOpenDirectory(char* directoryName);
+char* vfsReadDirectory(int handle, bool* isDirectory);
+void vfsCloseDirectory(int handle);
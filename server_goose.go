@@ -0,0 +1,237 @@
+package iec61850
+
+/*
+#include <iec61850_server.h>
+
+extern void goCBEventHandlerBridge(MmsGooseControlBlock goCb, int event, void* parameter);
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// GoCBEvent tells whether a client enabled or disabled a GOOSE control block.
+type GoCBEvent int
+
+const (
+	GOCB_EVENT_DISABLE GoCBEvent = C.IEC61850_GOCB_EVENT_DISABLE
+	GOCB_EVENT_ENABLE  GoCBEvent = C.IEC61850_GOCB_EVENT_ENABLE
+)
+
+func (e GoCBEvent) String() string {
+	if e == GOCB_EVENT_ENABLE {
+		return "enable"
+	}
+	return "disable"
+}
+
+// GoCBState is the state of a GOOSE control block of the server. stNum and sqNum are kept by the
+// integrated GOOSE publisher of libiec61850, which doesn't provide them, subscribe to the GOOSE messages
+// to observe them.
+type GoCBState struct {
+	Ref       string // like "simpleIOGenericIO/LLN0.gcbAnalogValues"
+	Name      string
+	DataSet   string // like "simpleIOGenericIO/LLN0$AnalogValues"
+	ConfRev   uint32
+	GoEna     bool
+	MinTime   int // ms
+	MaxTime   int // ms
+	FixedOffs bool
+}
+
+// GoCBEventHandler is invoked when a client enables or disables a GOOSE control block, like to start an
+// external GOOSE publisher.
+type GoCBEventHandler func(state *GoCBState, event GoCBEvent)
+
+type goCBEventCallback struct {
+	server  *IedServer
+	handler GoCBEventHandler
+}
+
+var goCBEventCallbacks = make(map[int32]*goCBEventCallback)
+
+// trackGoCBs installs the GoCB handler, which keeps the GoCBs and calls the GoCBEventHandler.
+func (is *IedServer) trackGoCBs() {
+	callbackId := callbackIdGen.Add(1)
+	cPtr := intToPointerBug58625(callbackId)
+	goCBEventCallbacks[callbackId] = &goCBEventCallback{server: is}
+	is.goCBEventCallbackId = callbackId
+
+	is.apply(func() {
+		C.IedServer_setGoCBHandler(is.server, (*[0]byte)(C.goCBEventHandlerBridge), cPtr)
+	})
+}
+
+//export goCBEventHandlerBridge
+func goCBEventHandlerBridge(goCb C.MmsGooseControlBlock, event C.int, parameter unsafe.Pointer) {
+	callbackId := int32(uintptr(parameter))
+	call, ok := goCBEventCallbacks[callbackId]
+	if !ok {
+		return
+	}
+	is := call.server
+	ln := newModelNode((*C.ModelNode)(unsafe.Pointer(C.MmsGooseControlBlock_getLogicalNode(goCb))))
+	ref := ln.ObjectReference + "." + C.GoString(C.MmsGooseControlBlock_getName(goCb))
+	is.goCBLock.Lock()
+	if is.goCBs == nil {
+		is.goCBs = make(map[string]C.MmsGooseControlBlock)
+	}
+	is.goCBs[ref] = goCb
+	is.goCBLock.Unlock()
+
+	if call.handler == nil {
+		return
+	}
+	state, err := is.GetGoCBState(ref)
+	if err != nil {
+		is.logger().Error("GoCB event not handled", "ref", ref, "error", err)
+		return
+	}
+	call.handler(state, GoCBEvent(event))
+}
+
+// SetGoCBEventHandler sets the handler for GOOSE control blocks enabled or disabled by clients.
+func (is *IedServer) SetGoCBEventHandler(handler GoCBEventHandler) {
+	if call, ok := goCBEventCallbacks[is.goCBEventCallbackId]; ok {
+		call.handler = handler
+	}
+}
+
+func (is *IedServer) setGoEna(ref string, goEna bool) {
+	is.goCBLock.Lock()
+	defer is.goCBLock.Unlock()
+	if is.goEna == nil {
+		is.goEna = make(map[string]bool)
+	}
+	is.goEna[ref] = goEna
+}
+
+// resetGoCBs forgets GoEna and the GoCBs of libiec61850 when the C server is destroyed.
+func (is *IedServer) resetGoCBs() {
+	is.goCBLock.Lock()
+	defer is.goCBLock.Unlock()
+	is.goEna = nil
+	is.goCBs = nil
+}
+
+// EnableGoosePublishing enables all GOOSE control blocks, so the integrated GOOSE publisher starts
+// sending. Without it GOOSE control blocks are only enabled by clients. GOOSE control blocks without
+// data set are not enabled.
+func (is *IedServer) EnableGoosePublishing() {
+	C.IedServer_enableGoosePublishing(is.server)
+	for _, gcb := range is.model.gseControlBlocks() {
+		// libiec61850 doesn't enable GOOSE control blocks without data set
+		is.setGoEna(gcb.ref(), gcb.gcb.dataSetName != nil)
+	}
+}
+
+// DisableGoosePublishing disables all GOOSE control blocks, which stops sending GOOSE messages.
+func (is *IedServer) DisableGoosePublishing() {
+	C.IedServer_disableGoosePublishing(is.server)
+	for _, gcb := range is.model.gseControlBlocks() {
+		is.setGoEna(gcb.ref(), false)
+	}
+}
+
+// SetGooseInterfaceId sets the Ethernet interface of all GOOSE control blocks, like "eth0". It must be
+// called before Start.
+func (is *IedServer) SetGooseInterfaceId(interfaceId string) {
+	is.apply(func() {
+		cInterfaceId := C.CString(interfaceId)
+		defer C.free(unsafe.Pointer(cInterfaceId))
+		C.IedServer_setGooseInterfaceId(is.server, cInterfaceId)
+	})
+}
+
+// SetGooseInterfaceIdForGoCB sets the Ethernet interface of the GOOSE control block, like "eth1". It
+// must be called before Start.
+func (is *IedServer) SetGooseInterfaceIdForGoCB(gocbRef string, interfaceId string) error {
+	gcb := is.model.gseControlBlock(gocbRef)
+	if gcb == nil {
+		return fmt.Errorf("SetGooseInterfaceIdForGoCB %q: %w", gocbRef, UserProvidedInvalidArgument)
+	}
+	is.apply(func() {
+		cName := C.CString(C.GoString(gcb.gcb.name))
+		defer C.free(unsafe.Pointer(cName))
+		cInterfaceId := C.CString(interfaceId)
+		defer C.free(unsafe.Pointer(cInterfaceId))
+		C.IedServer_setGooseInterfaceIdEx(is.server, gcb.gcb.parent, cName, cInterfaceId)
+	})
+	return nil
+}
+
+// UseGooseVlanTag enables or disables the VLAN tag in the GOOSE messages of the GOOSE control block.
+// It must be called before Start.
+func (is *IedServer) UseGooseVlanTag(gocbRef string, useVlanTag bool) error {
+	gcb := is.model.gseControlBlock(gocbRef)
+	if gcb == nil {
+		return fmt.Errorf("UseGooseVlanTag %q: %w", gocbRef, UserProvidedInvalidArgument)
+	}
+	is.apply(func() {
+		cName := C.CString(C.GoString(gcb.gcb.name))
+		defer C.free(unsafe.Pointer(cName))
+		C.IedServer_useGooseVlanTag(is.server, gcb.gcb.parent, cName, C.bool(useVlanTag))
+	})
+	return nil
+}
+
+// GetGoCBState returns the configuration and GoEna of the GOOSE control block. GoEna is read from
+// libiec61850 once a client enabled or disabled the GOOSE control block. Before, it is the state set
+// by EnableGoosePublishing and DisableGoosePublishing, which doesn't show when libiec61850 failed to
+// create the publisher of the GOOSE control block, like for a missing Ethernet interface.
+func (is *IedServer) GetGoCBState(gocbRef string) (*GoCBState, error) {
+	gcb := is.model.gseControlBlock(gocbRef)
+	if gcb == nil {
+		return nil, fmt.Errorf("GetGoCBState %q: %w", gocbRef, UserProvidedInvalidArgument)
+	}
+	lnRef := newModelNode((*C.ModelNode)(unsafe.Pointer(gcb.gcb.parent))).ObjectReference
+	state := &GoCBState{
+		Ref:       gocbRef,
+		Name:      C.GoString(gcb.gcb.name),
+		ConfRev:   uint32(gcb.gcb.confRev),
+		MinTime:   int(gcb.gcb.minTime),
+		MaxTime:   int(gcb.gcb.maxTime),
+		FixedOffs: bool(gcb.gcb.fixedOffs),
+	}
+	if gcb.gcb.dataSetName != nil {
+		ld, _, _ := strings.Cut(lnRef, "/")
+		state.DataSet = ld + "/" + C.GoString((*C.ModelNode)(unsafe.Pointer(gcb.gcb.parent)).name) + "$" + C.GoString(gcb.gcb.dataSetName)
+	}
+
+	is.goCBLock.Lock()
+	if goCb, ok := is.goCBs[gocbRef]; ok {
+		state.GoEna = bool(C.MmsGooseControlBlock_getGoEna(goCb))
+	} else {
+		state.GoEna = is.goEna[gocbRef]
+	}
+	is.goCBLock.Unlock()
+	return state, nil
+}
+
+// ref returns the reference of the GOOSE control block, like "simpleIOGenericIO/LLN0.gcbAnalogValues".
+func (b *GSEControlBlock) ref() string {
+	ln := newModelNode((*C.ModelNode)(unsafe.Pointer(b.gcb.parent)))
+	return ln.ObjectReference + "." + C.GoString(b.gcb.name)
+}
+
+// gseControlBlocks returns the GOOSE control blocks of the model.
+func (m *IedModel) gseControlBlocks() []*GSEControlBlock {
+	var gcbs []*GSEControlBlock
+	for gcb := m.Model.gseCBs; gcb != nil; gcb = gcb.sibling {
+		gcbs = append(gcbs, &GSEControlBlock{gcb: gcb})
+	}
+	return gcbs
+}
+
+// gseControlBlock returns the GOOSE control block with the reference, or nil.
+func (m *IedModel) gseControlBlock(ref string) *GSEControlBlock {
+	for _, gcb := range m.gseControlBlocks() {
+		if gcb.ref() == ref {
+			return gcb
+		}
+	}
+	return nil
+}
@@ -35,7 +35,7 @@ static Buffer AcseAuthenticationParameter_GetBuffer(AcseAuthenticationParameter
 import "C"
 
 import (
-	"fmt"
+	"log/slog"
 	"sync/atomic"
 	"unsafe"
 )
@@ -47,13 +47,18 @@ var (
 )
 
 type writeAccessCallback struct {
+	server  *IedServer
 	node    *ModelNode
-	handler WriteAccessHandler
+	handler WriteAccessHandler // nil for attributes only monitored by the access control
+	logger  *slog.Logger
 }
 
 type controlCallback struct {
-	node    *ModelNode
-	handler ControlHandler
+	server       *IedServer
+	node         *ModelNode
+	handler      ControlHandler
+	logger       *slog.Logger
+	terminations controlTerminations
 }
 
 type ControlAction struct {
@@ -64,6 +69,12 @@ type ControlAction struct {
 	CtlNum         int
 	OrIdent        []byte
 	OrCat          int
+	// ClientConnection is the connection of the client that sent the control, nil if unknown.
+	ClientConnection *ClientConnection
+
+	// action is only valid while the handler runs
+	action      C.ControlAction
+	termination *controlTermination
 }
 
 type IsoApplicationReference struct {
@@ -77,14 +88,16 @@ type AcseAuthenticationParameter struct {
 	Certificate []byte // for mechanism = ACSE_AUTH_CERTIFICATE or ACSE_AUTH_TLS
 }
 
-type WriteAccessHandler func(node *ModelNode, mmsValue *MmsValue) MmsDataAccessError
+type WriteAccessHandler func(node *ModelNode, mmsValue *MmsValue, connection *ClientConnection) MmsDataAccessError
 
 type ControlHandler func(node *ModelNode, action *ControlAction, mmsValue *MmsValue, test bool) ControlHandlerResult
 
+// ClientAuthenticator accepts or rejects a client. It may store a token for the client in securityToken, which
+// the server handlers find in ClientConnection.SecurityToken.
 type ClientAuthenticator func(securityToken *unsafe.Pointer, authParameter *AcseAuthenticationParameter, appReference *IsoApplicationReference) bool
 
 // ConnectionIndicationHandler is invoked when a client connection is opened or closed.
-type ConnectionIndicationHandler func(server *IedServer, connected bool)
+type ConnectionIndicationHandler func(server *IedServer, connection *ClientConnection, connected bool)
 
 // RCBEventType maps to libiec61850 IedServer_RCBEventType
 type RCBEventType int
@@ -124,13 +137,34 @@ func (r *ReportControlBlock) GetDataSet() string {
 	return C.GoString(cStr)
 }
 
-// RCBEventHandler is invoked when an RCB event occurs.
-type RCBEventHandler func(rcb *ReportControlBlock, event RCBEventType, parameterName string, serviceError MmsDataAccessError)
+// RCBEventHandler is invoked when an RCB event occurs. connection is nil for events not caused by a client.
+type RCBEventHandler func(rcb *ReportControlBlock, connection *ClientConnection, event RCBEventType, parameterName string, serviceError MmsDataAccessError)
 
 //export writeAccessHandlerBridge
-func writeAccessHandlerBridge(dataAttribute *C.DataAttribute, value *C.MmsValue, connection C.ClientConnection, parameter unsafe.Pointer) C.MmsDataAccessError {
+func writeAccessHandlerBridge(dataAttribute *C.DataAttribute, value *C.MmsValue, connection C.ClientConnection, parameter unsafe.Pointer) (result C.MmsDataAccessError) {
 	callbackId := int32(uintptr(parameter))
 	if call, ok := writeAccessCallbacks[callbackId]; ok {
+		is := call.server
+		node := call.node
+		if unsafe.Pointer(dataAttribute) != node._modelNode {
+			// the access control monitors complex attributes including their sub attributes
+			node = newModelNode((*C.ModelNode)(unsafe.Pointer(dataAttribute)))
+		}
+		clientConnection := newClientConnection(connection)
+		defer func() {
+			rejected := result != C.DATA_ACCESS_ERROR_SUCCESS && result != C.DATA_ACCESS_ERROR_SUCCESS_NO_UPDATE
+			is.stats.request(clientConnection, ACCESS_SERVICE_WRITE, rejected)
+		}()
+		if !is.accessAllowed(clientConnection, ACCESS_SERVICE_WRITE, node.ObjectReference, node.GetFC()) {
+			return C.DATA_ACCESS_ERROR_OBJECT_ACCESS_DENIED
+		}
+		// libiec61850 calls only the first handler of an attribute, which may be the one of the access control
+		if handler, ok := is.writeAccessHandlers[dataAttribute]; ok {
+			call = handler
+		}
+		if call.handler == nil {
+			return is.writeAccessPolicy(node.GetFC())
+		}
 
 		mmsType := MmsType(C.MmsValue_getType(value))
 		if goValue, err := toGoValue(value, mmsType); err == nil {
@@ -138,10 +172,10 @@ func writeAccessHandlerBridge(dataAttribute *C.DataAttribute, value *C.MmsValue,
 			dataAccessError := call.handler(call.node, &MmsValue{
 				Type:  mmsType,
 				Value: goValue,
-			})
+			}, clientConnection)
 			return C.MmsDataAccessError(dataAccessError)
 		} else {
-			fmt.Printf("mms value to go value error: %v\n", err)
+			call.logger.Error("write access rejected, value cannot be converted", "ref", call.node.ObjectReference, "error", err)
 		}
 	}
 	return C.DATA_ACCESS_ERROR_OBJECT_ACCESS_DENIED
@@ -151,33 +185,29 @@ func writeAccessHandlerBridge(dataAttribute *C.DataAttribute, value *C.MmsValue,
 func controlHandlerBridge(action C.ControlAction, parameter unsafe.Pointer, ctlVal *C.MmsValue, test C.bool) C.ControlHandlerResult {
 	callbackId := int32(uintptr(parameter))
 	if call, ok := controlCallbacks[callbackId]; ok {
+		// libiec61850 polls the handler until an operation in progress is completed
+		if result, waiting := call.terminations.poll(action); waiting {
+			if result == CONTROL_RESULT_FAILED {
+				call.server.stats.operateFailed()
+			}
+			return C.ControlHandlerResult(result)
+		}
 
 		mmsType := MmsType(C.MmsValue_getType(ctlVal))
 		if goValue, err := toGoValue(ctlVal, mmsType); err == nil {
-
-			var (
-				orIdentSize C.int
-				orIdent     []byte
-			)
-
-			orIdentBuffer := C.ControlAction_getOrIdent(action, (*C.int)(unsafe.Pointer(&orIdentSize)))
-			if orIdentBuffer != nil {
-				size := int(orIdentSize)
-				orIdent = C.GoBytes(unsafe.Pointer(orIdentBuffer), C.int(size))
+			termination := &controlTermination{}
+			actionFill := newControlAction(action, termination)
+			controlHandlerResult := call.handler(call.node, actionFill, &MmsValue{mmsType, goValue}, bool(test))
+			if controlHandlerResult == CONTROL_RESULT_WAITING {
+				call.terminations.start(action, termination)
 			}
-
-			actionFill := &ControlAction{
-				ControlTime:    uint64(C.ControlAction_getControlTime(action)),
-				IsSelect:       bool(C.ControlAction_isSelect(action)),
-				InterlockCheck: bool(C.ControlAction_getInterlockCheck(action)),
-				SynchroCheck:   bool(C.ControlAction_getSynchroCheck(action)),
-				CtlNum:         int(C.ControlAction_getCtlNum(action)),
-				OrIdent:        orIdent,
-				OrCat:          int(C.ControlAction_getOrCat(action)),
+			if controlHandlerResult == CONTROL_RESULT_FAILED {
+				call.server.stats.operateFailed()
 			}
-
-			controlHandlerResult := call.handler(call.node, actionFill, &MmsValue{mmsType, goValue}, bool(test))
 			return C.ControlHandlerResult(controlHandlerResult)
+		} else {
+			call.logger.Error("control rejected, ctlVal cannot be converted", "ref", call.node.ObjectReference, "error", err)
+			call.server.stats.operateFailed()
 		}
 	}
 	return C.CONTROL_RESULT_FAILED
@@ -215,7 +245,12 @@ func acseAuthenticatorBridge(parameter unsafe.Pointer, authParameter C.AcseAuthe
 		// none
 	}
 
-	result := is.clientAuthenticator(securityToken, _authParameter, _appReference)
+	// the token of the authenticator stays in Go memory, libiec61850 keeps the ID of the session instead
+	var token unsafe.Pointer
+	result := is.clientAuthenticator(&token, _authParameter, _appReference)
+	if result {
+		*securityToken = uint64ToPointer(newAuthenticatedSession(token, _authParameter.Certificate))
+	}
 	return C.bool(result)
 }
 
@@ -227,14 +262,25 @@ func (is *IedServer) SetHandleWriteAccess(modelNode *ModelNode, handler WriteAcc
 	callbackId := callbackIdGen.Add(1)
 	// 将 int 转为 uintptr，再转为 unsafe.Pointer
 	cPtr := intToPointerBug58625(callbackId)
-	writeAccessCallbacks[callbackId] = &writeAccessCallback{
+	call := &writeAccessCallback{
+		server:  is,
 		node:    modelNode,
 		handler: handler,
+		logger:  is.logger(),
+	}
+	writeAccessCallbacks[callbackId] = call
+	if is.writeAccessHandlers == nil {
+		is.writeAccessHandlers = make(map[*C.DataAttribute]*writeAccessCallback)
 	}
+	is.writeAccessHandlers[(*C.DataAttribute)(modelNode._modelNode)] = call
 
-	C.IedServer_handleWriteAccess(is.server, (*C.DataAttribute)(modelNode._modelNode), (*[0]byte)(C.writeAccessHandlerBridge), cPtr)
+	is.apply(func() {
+		C.IedServer_handleWriteAccess(is.server, (*C.DataAttribute)(modelNode._modelNode), (*[0]byte)(C.writeAccessHandlerBridge), cPtr)
+	})
 }
 
+// SetControlHandler sets the operate handler of the control object. The handler may return CONTROL_RESULT_WAITING
+// for operations that take longer and finish them later with ControlAction.Complete.
 func (is *IedServer) SetControlHandler(modelNode *ModelNode, handler ControlHandler) {
 	if modelNode == nil {
 		return
@@ -244,11 +290,21 @@ func (is *IedServer) SetControlHandler(modelNode *ModelNode, handler ControlHand
 	// 将 int 转为 uintptr，再转为 unsafe.Pointer
 	cPtr := intToPointerBug58625(callbackId)
 	controlCallbacks[callbackId] = &controlCallback{
+		server:  is,
 		node:    modelNode,
 		handler: handler,
+		logger:  is.logger(),
 	}
 
-	C.IedServer_setControlHandler(is.server, (*C.DataObject)(modelNode._modelNode), (*[0]byte)(C.controlHandlerBridge), cPtr)
+	is.apply(func() {
+		C.IedServer_setControlHandler(is.server, (*C.DataObject)(modelNode._modelNode), (*[0]byte)(C.controlHandlerBridge), cPtr)
+	})
+}
+
+// uint64ToPointer stores an ID in a pointer, see intToPointerBug58625.
+func uint64ToPointer(i uint64) unsafe.Pointer {
+	var intPtr = uintptr(i)
+	return *(*unsafe.Pointer)(unsafe.Pointer(&intPtr))
 }
 
 // intToPointerBug58625 is a helper function to fix issue #58625 in Go | https://github.com/golang/go/issues/58625
@@ -260,40 +316,80 @@ func intToPointerBug58625(i int32) unsafe.Pointer {
 func (is *IedServer) SetAuthenticator(clientAuthenticator ClientAuthenticator) {
 	is.clientAuthenticator = clientAuthenticator
 	cPtr := unsafe.Pointer(is)
-	C.IedServer_setAuthenticator(is.server, (*[0]byte)(C.acseAuthenticatorBridge), cPtr)
+	is.apply(func() {
+		C.IedServer_setAuthenticator(is.server, (*[0]byte)(C.acseAuthenticatorBridge), cPtr)
+	})
 }
 
 //export connectionIndicationBridge
 func connectionIndicationBridge(self C.IedServer, connection C.ClientConnection, connected C.bool, parameter unsafe.Pointer) {
 	is := (*IedServer)(parameter)
-	if is != nil && is.connectionIndicationHandler != nil {
-		is.connectionIndicationHandler(is, bool(connected))
+	clientConnection := newClientConnection(connection)
+	if !connected {
+		defer closeClientConnection(connection)
+	}
+	if is == nil {
+		return
+	}
+	if connected {
+		is.stats.connected(clientConnection)
+	} else {
+		is.stats.disconnected(clientConnection)
 	}
+	if is.connectionIndicationHandler != nil {
+		is.connectionIndicationHandler(is, clientConnection, bool(connected))
+	}
+}
+
+// trackConnections installs the connection indication bridge, which also releases the Go state of
+// closed client connections.
+func (is *IedServer) trackConnections() {
+	cPtr := unsafe.Pointer(is)
+	is.apply(func() {
+		C.IedServer_setConnectionIndicationHandler(is.server, (*[0]byte)(C.connectionIndicationBridge), cPtr)
+	})
 }
 
 // SetConnectionIndicationHandler registers a callback for client connection open/close events.
 func (is *IedServer) SetConnectionIndicationHandler(handler ConnectionIndicationHandler) {
 	is.connectionIndicationHandler = handler
-	cPtr := unsafe.Pointer(is)
-	C.IedServer_setConnectionIndicationHandler(is.server, (*[0]byte)(C.connectionIndicationBridge), cPtr)
 }
 
 //export rcbEventHandlerBridge
 func rcbEventHandlerBridge(parameter unsafe.Pointer, rcb *C.ReportControlBlock, connection C.ClientConnection, event C.IedServer_RCBEventType, parameterName *C.char, serviceError C.MmsDataAccessError) {
 	is := (*IedServer)(parameter)
-	if is != nil && is.rcbEventHandler != nil {
+	if is == nil {
+		return
+	}
+	is.stats.rcbEvent(rcb, RCBEventType(event))
+	if event == C.RCB_EVENT_SET_PARAMETER {
+		is.stats.request(newClientConnection(connection), ACCESS_SERVICE_WRITE, serviceError != C.DATA_ACCESS_ERROR_SUCCESS)
+	}
+	if is.rcbEventHandler != nil {
 		var paramName string
 		if parameterName != nil {
 			paramName = C.GoString(parameterName)
 		}
 		wrap := &ReportControlBlock{rcb: rcb}
-		is.rcbEventHandler(wrap, RCBEventType(event), paramName, MmsDataAccessError(serviceError))
+		is.rcbEventHandler(wrap, newClientConnection(connection), RCBEventType(event), paramName, MmsDataAccessError(serviceError))
 	}
 }
 
 // SetRCBEventHandler registers a callback for server-side RCB events.
 func (is *IedServer) SetRCBEventHandler(handler RCBEventHandler) {
 	is.rcbEventHandler = handler
+	is.installRCBEventHandler()
+}
+
+// installRCBEventHandler installs the RCB event bridge for the statistics and the RCB event handler.
+func (is *IedServer) installRCBEventHandler() {
+	if is.rcbEventInstalled {
+		return
+	}
+	is.rcbEventInstalled = true
 	cPtr := unsafe.Pointer(is)
-	C.IedServer_setRCBEventHandler(is.server, (*[0]byte)(C.rcbEventHandlerBridge), cPtr)
+
+	is.apply(func() {
+		C.IedServer_setRCBEventHandler(is.server, (*[0]byte)(C.rcbEventHandlerBridge), cPtr)
+	})
 }
@@ -0,0 +1,249 @@
+package iec61850
+
+/*
+#include <iec61850_server.h>
+#include <mms_server.h>
+
+// the private headers of libiec61850 depend on its build configuration, so the functions are declared here
+extern ClientConnection private_IedServer_getClientConnectionByHandle(IedServer self, void* serverConnectionHandle);
+
+extern MmsDataAccessError writeAccessHandlerBridge(DataAttribute* dataAttribute, MmsValue* value, ClientConnection connection, void* parameter);
+extern CheckHandlerResult performCheckHandlerBridge(ControlAction action, void* parameter, MmsValue* ctlVal, bool test, bool interlockCheck);
+*/
+import "C"
+
+import (
+	"crypto/x509"
+	"path"
+	"slices"
+	"time"
+	"unsafe"
+)
+
+// AccessService is a group of services the role based access control decides on.
+type AccessService int
+
+const (
+	// ACCESS_SERVICE_READ reads data values, the reference is the data object like "IEDLD/GGIO1.Ind1"
+	ACCESS_SERVICE_READ AccessService = iota
+	// ACCESS_SERVICE_WRITE writes data attributes, the reference is the attribute like "IEDLD/GGIO1.SPCSet1.setVal"
+	ACCESS_SERVICE_WRITE
+	// ACCESS_SERVICE_CONTROL selects and operates controls, the reference is the control object like "IEDLD/GGIO1.SPCSO1"
+	ACCESS_SERVICE_CONTROL
+	// ACCESS_SERVICE_FILE opens, deletes, renames and lists files, the reference is the file name
+	ACCESS_SERVICE_FILE
+)
+
+func (s AccessService) String() string {
+	switch s {
+	case ACCESS_SERVICE_READ:
+		return "read"
+	case ACCESS_SERVICE_WRITE:
+		return "write"
+	case ACCESS_SERVICE_CONTROL:
+		return "control"
+	case ACCESS_SERVICE_FILE:
+		return "file"
+	}
+	return "unknown"
+}
+
+// AccessRule grants services on references to a role.
+type AccessRule struct {
+	Services []AccessService
+	// References are patterns in the syntax of path.Match, like "*/GGIO1.*" or "COMTRADE/*". Empty matches all.
+	References []string
+	// FCs restricts read and write access to functional constraints, empty for all.
+	FCs []FC
+}
+
+// AccessDenial describes a request rejected by the role based access control.
+type AccessDenial struct {
+	Time       time.Time
+	Connection *ClientConnection
+	Roles      []string
+	Service    AccessService
+	Reference  string
+	FC         FC // NONE for control and file services
+}
+
+// RBACConfig configures the role based access control of the server in the spirit of IEC 62351-8.
+// Requests are denied unless a rule of one of the roles of the client grants them.
+type RBACConfig struct {
+	// Roles returns the roles of a client, like from the ClientConnection.SecurityToken stored by the
+	// authenticator or from the TLS certificate with RolesFromPeerCertificate.
+	Roles func(connection *ClientConnection) []string
+	// Rules by role.
+	Rules map[string][]AccessRule
+	// Audit receives all denied requests, nil to log them as warning with the server logger.
+	Audit func(denial AccessDenial)
+}
+
+// RolesFromPeerCertificate returns the organizational units of the subject of the TLS certificate of the
+// client as roles. Role extensions of IEC 62351-8 certificates are not evaluated.
+func RolesFromPeerCertificate(connection *ClientConnection) []string {
+	if connection == nil || connection.PeerCertificate == nil {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(connection.PeerCertificate)
+	if err != nil {
+		return nil
+	}
+	return cert.Subject.OrganizationalUnit
+}
+
+// EnableRBAC enforces the role based access control for reads, writes of data attributes, controls and
+// files. It must be called before Start, after the model is complete. Write and control handlers of the
+// server are still called for granted requests.
+//
+// libiec61850 1.5.3 has no public hooks in front of the writes of control blocks, like RptEna of an RCB or
+// GoEna of a GoCB, and in front of the data set services, so these requests are not checked.
+func (is *IedServer) EnableRBAC(config RBACConfig) {
+	is.rbac = &config
+	is.installReadAccessHandler()
+	is.installFileAccessHandler()
+	is.monitorModel()
+}
+
+// monitorModel installs the write access bridge for the writable attributes and the perform check bridge
+// for the controls of the model, for the access control and the statistics.
+func (is *IedServer) monitorModel() {
+	if is.modelMonitored {
+		return
+	}
+	is.modelMonitored = true
+
+	is.model.Walk(func(node *ModelNode) bool {
+		switch node.GetType() {
+		case MODEL_NODE_DATA_OBJECT:
+			if node.GetChild("Oper") != nil {
+				is.monitorControl(node)
+			}
+		case MODEL_NODE_DATA_ATTRIBUTE:
+			switch node.GetFC() {
+			case ST, MX, CO, OR, SG, SR:
+				// not writable or handled by the control model
+			default:
+				is.monitorWriteAccess(node)
+			}
+			return false
+		}
+		return true
+	})
+}
+
+// monitorWriteAccess installs the write access bridge without handler for the attribute and its sub
+// attributes, so writes are checked against the rules and counted.
+func (is *IedServer) monitorWriteAccess(node *ModelNode) {
+	callbackId := callbackIdGen.Add(1)
+	cPtr := intToPointerBug58625(callbackId)
+	writeAccessCallbacks[callbackId] = &writeAccessCallback{
+		server: is,
+		node:   node,
+		logger: is.logger(),
+	}
+
+	is.apply(func() {
+		C.IedServer_handleWriteAccessForComplexAttribute(is.server, (*C.DataAttribute)(node._modelNode), (*[0]byte)(C.writeAccessHandlerBridge), cPtr)
+	})
+}
+
+// monitorControl installs the perform check bridge without handler for controls without perform check handler.
+func (is *IedServer) monitorControl(node *ModelNode) {
+	if _, ok := is.performCheckHandlers[(*C.DataObject)(node._modelNode)]; ok {
+		return
+	}
+	callbackId := callbackIdGen.Add(1)
+	cPtr := intToPointerBug58625(callbackId)
+	performCheckCallbacks[callbackId] = &performCheckCallback{
+		server: is,
+		node:   node,
+		logger: is.logger(),
+	}
+
+	is.apply(func() {
+		C.IedServer_setPerformCheckHandler(is.server, (*C.DataObject)(node._modelNode), (*[0]byte)(C.performCheckHandlerBridge), cPtr)
+	})
+}
+
+// accessAllowed checks a request against the rules, denied requests are audited.
+func (is *IedServer) accessAllowed(connection *ClientConnection, service AccessService, reference string, fc FC) bool {
+	if is.rbac == nil {
+		return true
+	}
+	var roles []string
+	if is.rbac.Roles != nil {
+		roles = is.rbac.Roles(connection)
+	}
+	for _, role := range roles {
+		for _, rule := range is.rbac.Rules[role] {
+			if rule.grants(service, reference, fc) {
+				return true
+			}
+		}
+	}
+
+	is.stats.denied(connection, service)
+	denial := AccessDenial{
+		Time:       time.Now(),
+		Connection: connection,
+		Roles:      roles,
+		Service:    service,
+		Reference:  reference,
+		FC:         fc,
+	}
+	if is.rbac.Audit != nil {
+		is.rbac.Audit(denial)
+	} else {
+		var peer string
+		if connection != nil {
+			peer = connection.PeerAddress
+		}
+		is.logger().Warn("access denied", "service", service, "ref", reference, "fc", fc, "peer", peer, "roles", roles)
+	}
+	return false
+}
+
+func (r AccessRule) grants(service AccessService, reference string, fc FC) bool {
+	if !slices.Contains(r.Services, service) {
+		return false
+	}
+	if len(r.FCs) > 0 && fc != NONE && !slices.Contains(r.FCs, fc) {
+		return false
+	}
+	if len(r.References) == 0 {
+		return true
+	}
+	for _, pattern := range r.References {
+		if ok, _ := path.Match(pattern, reference); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// writeAccessPolicy returns the result of the default write access policy of the server for the FC.
+func (is *IedServer) writeAccessPolicy(fc FC) C.MmsDataAccessError {
+	policy, ok := is.writeAccessPolicies[fc]
+	if !ok {
+		// defaults of libiec61850
+		switch fc {
+		case DC, CF, SP, SV, SE:
+			policy = ACCESS_POLICY_ALLOW
+		default:
+			policy = ACCESS_POLICY_DENY
+		}
+	}
+	if policy == ACCESS_POLICY_ALLOW {
+		return C.DATA_ACCESS_ERROR_SUCCESS
+	}
+	return C.DATA_ACCESS_ERROR_OBJECT_ACCESS_DENIED
+}
+
+// clientConnectionOf returns the connection of an MMS server connection.
+func (is *IedServer) clientConnectionOf(connection C.MmsServerConnection) *ClientConnection {
+	if connection == nil {
+		return nil
+	}
+	return newClientConnection(C.private_IedServer_getClientConnectionByHandle(is.server, unsafe.Pointer(connection)))
+}
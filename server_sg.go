@@ -0,0 +1,226 @@
+package iec61850
+
+/*
+#include <iec61850_server.h>
+
+extern bool activeSettingGroupChangedHandlerBridge(void* parameter, SettingGroupControlBlock* sgcb, uint8_t newActSg, ClientConnection connection);
+extern bool editSettingGroupChangedHandlerBridge(void* parameter, SettingGroupControlBlock* sgcb, uint8_t newEditSg, ClientConnection connection);
+extern void editSettingGroupConfirmationHandlerBridge(void* parameter, SettingGroupControlBlock* sgcb, uint8_t editSg);
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// ActiveSettingGroupChangedHandler is invoked before a client changes the active setting group, the
+// change is rejected when it returns false. The handler should load the values of the new group into
+// the attributes with FC SG, like with SetSettingValues.
+type ActiveSettingGroupChangedHandler func(sgcb *SettingGroupControlBlock, newActSG uint8, connection *ClientConnection) bool
+
+// EditSettingGroupChangedHandler is invoked before a client selects a setting group for editing, the
+// selection is rejected when it returns false. The handler should load the values of the group into the
+// attributes with FC SE.
+type EditSettingGroupChangedHandler func(sgcb *SettingGroupControlBlock, newEditSG uint8, connection *ClientConnection) bool
+
+// EditSettingGroupConfirmationHandler is invoked when a client confirmed the values written to the
+// edit setting group. The handler should store them, like with GetSettingValues, and copy them to the
+// active values with CopyEditSettingsToActive when editSG is the active group.
+type EditSettingGroupConfirmationHandler func(sgcb *SettingGroupControlBlock, editSG uint8)
+
+// SettingValues are the values of the basic attributes of a setting group by object reference, like
+// "simpleIOGenericIO/PTOC1.StrVal.setMag.f". The references are the same for FC SG and SE.
+type SettingValues map[string]*MmsValue
+
+type activeSettingGroupChangedCallback struct {
+	sgcb    *SettingGroupControlBlock
+	handler ActiveSettingGroupChangedHandler
+}
+
+type editSettingGroupChangedCallback struct {
+	sgcb    *SettingGroupControlBlock
+	handler EditSettingGroupChangedHandler
+}
+
+type editSettingGroupConfirmationCallback struct {
+	sgcb    *SettingGroupControlBlock
+	handler EditSettingGroupConfirmationHandler
+}
+
+var (
+	activeSettingGroupChangedCallbacks    = make(map[int32]*activeSettingGroupChangedCallback)
+	editSettingGroupChangedCallbacks      = make(map[int32]*editSettingGroupChangedCallback)
+	editSettingGroupConfirmationCallbacks = make(map[int32]*editSettingGroupConfirmationCallback)
+)
+
+//export activeSettingGroupChangedHandlerBridge
+func activeSettingGroupChangedHandlerBridge(parameter unsafe.Pointer, sgcb *C.SettingGroupControlBlock, newActSg C.uint8_t, connection C.ClientConnection) C.bool {
+	callbackId := int32(uintptr(parameter))
+	if call, ok := activeSettingGroupChangedCallbacks[callbackId]; ok {
+		return C.bool(call.handler(call.sgcb, uint8(newActSg), newClientConnection(connection)))
+	}
+	return true
+}
+
+//export editSettingGroupChangedHandlerBridge
+func editSettingGroupChangedHandlerBridge(parameter unsafe.Pointer, sgcb *C.SettingGroupControlBlock, newEditSg C.uint8_t, connection C.ClientConnection) C.bool {
+	callbackId := int32(uintptr(parameter))
+	if call, ok := editSettingGroupChangedCallbacks[callbackId]; ok {
+		return C.bool(call.handler(call.sgcb, uint8(newEditSg), newClientConnection(connection)))
+	}
+	return true
+}
+
+//export editSettingGroupConfirmationHandlerBridge
+func editSettingGroupConfirmationHandlerBridge(parameter unsafe.Pointer, sgcb *C.SettingGroupControlBlock, editSg C.uint8_t) {
+	callbackId := int32(uintptr(parameter))
+	if call, ok := editSettingGroupConfirmationCallbacks[callbackId]; ok {
+		call.handler(call.sgcb, uint8(editSg))
+	}
+}
+
+// SetActiveSettingGroupChangedHandler sets the handler for changes of the active setting group by clients.
+func (is *IedServer) SetActiveSettingGroupChangedHandler(sgcb *SettingGroupControlBlock, handler ActiveSettingGroupChangedHandler) {
+	if sgcb == nil {
+		return
+	}
+
+	callbackId := callbackIdGen.Add(1)
+	cPtr := intToPointerBug58625(callbackId)
+	activeSettingGroupChangedCallbacks[callbackId] = &activeSettingGroupChangedCallback{
+		sgcb:    sgcb,
+		handler: handler,
+	}
+
+	is.apply(func() {
+		C.IedServer_setActiveSettingGroupChangedHandler(is.server, sgcb.sgcb, (*[0]byte)(C.activeSettingGroupChangedHandlerBridge), cPtr)
+	})
+}
+
+// SetEditSettingGroupChangedHandler sets the handler for the selection of the edit setting group by clients.
+func (is *IedServer) SetEditSettingGroupChangedHandler(sgcb *SettingGroupControlBlock, handler EditSettingGroupChangedHandler) {
+	if sgcb == nil {
+		return
+	}
+
+	callbackId := callbackIdGen.Add(1)
+	cPtr := intToPointerBug58625(callbackId)
+	editSettingGroupChangedCallbacks[callbackId] = &editSettingGroupChangedCallback{
+		sgcb:    sgcb,
+		handler: handler,
+	}
+
+	is.apply(func() {
+		C.IedServer_setEditSettingGroupChangedHandler(is.server, sgcb.sgcb, (*[0]byte)(C.editSettingGroupChangedHandlerBridge), cPtr)
+	})
+}
+
+// SetEditSettingGroupConfirmationHandler sets the handler for the confirmation of the edit setting group by clients.
+func (is *IedServer) SetEditSettingGroupConfirmationHandler(sgcb *SettingGroupControlBlock, handler EditSettingGroupConfirmationHandler) {
+	if sgcb == nil {
+		return
+	}
+
+	callbackId := callbackIdGen.Add(1)
+	cPtr := intToPointerBug58625(callbackId)
+	editSettingGroupConfirmationCallbacks[callbackId] = &editSettingGroupConfirmationCallback{
+		sgcb:    sgcb,
+		handler: handler,
+	}
+
+	is.apply(func() {
+		C.IedServer_setEditSettingGroupConfirmationHandler(is.server, sgcb.sgcb, (*[0]byte)(C.editSettingGroupConfirmationHandlerBridge), cPtr)
+	})
+}
+
+// ChangeActiveSettingGroup changes the active setting group because of an event in the device. The
+// values of the new group should be set with SetSettingValues before.
+func (is *IedServer) ChangeActiveSettingGroup(sgcb *SettingGroupControlBlock, newActiveSG uint8) error {
+	if sgcb == nil {
+		return fmt.Errorf("ChangeActiveSettingGroup: %w", UserProvidedInvalidArgument)
+	}
+	if newActiveSG < 1 || newActiveSG > sgcb.NumOfSGs() {
+		return fmt.Errorf("ChangeActiveSettingGroup %d: setting groups are 1 to %d: %w", newActiveSG, sgcb.NumOfSGs(), UserProvidedInvalidArgument)
+	}
+	C.IedServer_changeActiveSettingGroup(is.server, sgcb.sgcb, C.uint8_t(newActiveSG))
+	return nil
+}
+
+// GetActiveSettingGroup returns the number of the active setting group.
+func (is *IedServer) GetActiveSettingGroup(sgcb *SettingGroupControlBlock) uint8 {
+	if sgcb == nil {
+		return 0
+	}
+	return uint8(C.IedServer_getActiveSettingGroup(is.server, sgcb.sgcb))
+}
+
+// GetSettingValues reads the values of the attributes with the FC, SG for the active group or SE for the
+// edit group, of the logical device of the setting group control block.
+func (is *IedServer) GetSettingValues(sgcb *SettingGroupControlBlock, fc FC) (SettingValues, error) {
+	if sgcb == nil {
+		return nil, fmt.Errorf("GetSettingValues: %w", UserProvidedInvalidArgument)
+	}
+	values := make(SettingValues)
+	var err error
+	settingAttributes(sgcb, fc, func(node *ModelNode) {
+		if err != nil {
+			return
+		}
+		var value *MmsValue
+		if value, err = is.GetAttributeValue(node); err != nil {
+			err = fmt.Errorf("GetSettingValues %q: %w", node.ObjectReference, err)
+			return
+		}
+		values[node.ObjectReference] = value
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// SetSettingValues updates the attributes with the FC, SG for the active group or SE for the edit group,
+// of the logical device of the setting group control block. Attributes without value are not changed.
+// It can be called in the setting group handlers, outside of them the data model should be locked.
+func (is *IedServer) SetSettingValues(sgcb *SettingGroupControlBlock, fc FC, values SettingValues) error {
+	if sgcb == nil {
+		return fmt.Errorf("SetSettingValues: %w", UserProvidedInvalidArgument)
+	}
+
+	var err error
+	settingAttributes(sgcb, fc, func(node *ModelNode) {
+		value, ok := values[node.ObjectReference]
+		if err != nil || !ok {
+			return
+		}
+		err = is.updateAttributeValue("SetSettingValues", node, value.Value)
+	})
+	return err
+}
+
+// CopyEditSettingsToActive copies the values of the attributes with FC SE to the attributes with FC SG,
+// to apply the confirmed values when the edit setting group is the active group.
+func (is *IedServer) CopyEditSettingsToActive(sgcb *SettingGroupControlBlock) error {
+	values, err := is.GetSettingValues(sgcb, SE)
+	if err != nil {
+		return err
+	}
+	return is.SetSettingValues(sgcb, SG, values)
+}
+
+// settingAttributes calls fn for the basic attributes with the FC in the logical device of the SGCB.
+func settingAttributes(sgcb *SettingGroupControlBlock, fc FC, fn func(node *ModelNode)) {
+	sgcb.LogicalDevice().Walk(func(node *ModelNode) bool {
+		if node.GetType() != MODEL_NODE_DATA_ATTRIBUTE {
+			return true
+		}
+		if node.GetFC() != fc {
+			return false
+		}
+		if node.GetDataAttributeType() != DA_TYPE_CONSTRUCTED {
+			fn(node)
+		}
+		return true
+	})
+}
@@ -0,0 +1,125 @@
+package iec61850
+
+// #include <iec61850_server.h>
+import "C"
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"unsafe"
+)
+
+// StartOptions select the addresses the server listens on.
+type StartOptions struct {
+	// Port is the TCP port, -1 for the default port 102, or 3782 with TLS.
+	Port int
+	// LocalAddress is the local IP address to listen on, like "192.168.1.10", empty for all interfaces.
+	LocalAddress string
+	// AccessPoints are further addresses the server listens on with the same model and port, like the
+	// second station bus of a redundant configuration. They are added once, later starts must use the
+	// same ones.
+	AccessPoints []AccessPointAddress
+	// Threadless starts the server without the threads of libiec61850, Poll has to be called instead.
+	Threadless bool
+}
+
+// AccessPointAddress is an additional address of the server. libiec61850 1.5.3 starts all access points
+// on the port of the server, so they differ by their local address.
+type AccessPointAddress struct {
+	// LocalAddress is the local IP address, empty for all interfaces.
+	LocalAddress string
+	// TLS uses the TLS configuration of the server on the access point.
+	TLS bool
+}
+
+// StartWithOptions starts the server on the addresses of options. The sockets of libiec61850 1.5.3
+// support IPv4 only, IPv6 addresses are rejected with IPv6NotSupported.
+//
+// To serve several access points of an SCL file with their own models, build the model of each access
+// point and start a server for each of them on its address.
+func (is *IedServer) StartWithOptions(options StartOptions) error {
+	if is.IsRunning() {
+		return fmt.Errorf("StartWithOptions: server is running")
+	}
+	if err := checkLocalAddress(options.LocalAddress); err != nil {
+		return fmt.Errorf("StartWithOptions %q: %w", options.LocalAddress, err)
+	}
+	for _, accessPoint := range options.AccessPoints {
+		if err := checkLocalAddress(accessPoint.LocalAddress); err != nil {
+			return fmt.Errorf("StartWithOptions %q: %w", accessPoint.LocalAddress, err)
+		}
+		if accessPoint.TLS && is.tlsConfig == nil {
+			return fmt.Errorf("StartWithOptions %q: %w", accessPoint.LocalAddress, TLSNotEnabled)
+		}
+	}
+
+	if is.accessPoints != nil {
+		if !slices.Equal(is.accessPoints, options.AccessPoints) {
+			return fmt.Errorf("StartWithOptions: access points can't be changed: %w", UserProvidedInvalidArgument)
+		}
+	} else if len(options.AccessPoints) > 0 {
+		is.addAccessPoints(options.AccessPoints)
+	}
+
+	is.startOptions = options
+	return is.start()
+}
+
+// addAccessPoints adds the access points to the C server, also after a rebuild.
+func (is *IedServer) addAccessPoints(accessPoints []AccessPointAddress) {
+	is.accessPoints = slices.Clone(accessPoints)
+	for _, accessPoint := range is.accessPoints {
+		is.apply(func() {
+			// libiec61850 copies the address
+			cAddress := cStringOrNil(accessPoint.LocalAddress)
+			defer C.free(unsafe.Pointer(cAddress))
+			var tlsConfig C.TLSConfiguration
+			if accessPoint.TLS {
+				tlsConfig = is.tlsConfig
+			}
+			// the port is set by IedServer_start for all access points
+			if !C.IedServer_addAccessPoint(is.server, cAddress, -1, tlsConfig) {
+				is.logger().Error("add access point failed", "address", accessPoint.LocalAddress)
+			}
+		})
+	}
+}
+
+// start starts the C server on the addresses of the start options.
+func (is *IedServer) start() error {
+	options := is.startOptions
+
+	localAddress := options.LocalAddress
+	if localAddress == "" {
+		localAddress = "0.0.0.0"
+	}
+	cLocalAddress := C.CString(localAddress)
+	defer C.free(unsafe.Pointer(cLocalAddress))
+	C.IedServer_setLocalIpAddress(is.server, cLocalAddress)
+
+	if options.Threadless {
+		C.IedServer_startThreadless(is.server, C.int(options.Port))
+	} else {
+		C.IedServer_start(is.server, C.int(options.Port))
+	}
+	if !is.IsRunning() {
+		return fmt.Errorf("start on %s port %d failed", localAddress, options.Port)
+	}
+	return nil
+}
+
+// checkLocalAddress checks that the server can listen on the address, empty for all interfaces.
+func checkLocalAddress(address string) error {
+	if address == "" {
+		return nil
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return UserProvidedInvalidArgument
+	}
+	if ip.To4() == nil {
+		return IPv6NotSupported
+	}
+	return nil
+}
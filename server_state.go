@@ -0,0 +1,324 @@
+package iec61850
+
+/*
+#include <iec61850_server.h>
+#include <iec61850_dynamic_model.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// ServerState is the state of the writable attributes of a server, written by IedServer.Snapshot as
+// JSON to restore it after a restart with IedServer.Restore.
+type ServerState struct {
+	Time                time.Time
+	Values              []ServerStateValue
+	SettingGroups       []ServerStateSettingGroup
+	ReportControlBlocks []ServerStateRCB
+}
+
+// ServerStateValue is the value of a basic attribute.
+type ServerStateValue struct {
+	Ref   string // like "simpleIOGenericIO/GGIO1.NamPlt.vendor"
+	FC    FC
+	Type  MmsType
+	Value string // the value as text for readers, Restore uses Data
+	Data  []byte // the value in the BER encoding of MMS
+}
+
+// ServerStateSettingGroup is the active setting group of a logical device.
+type ServerStateSettingGroup struct {
+	LD    string // like "simpleIOGenericIO"
+	ActSG uint8
+}
+
+// ServerStateRCB is the configuration of a report control block.
+type ServerStateRCB struct {
+	Ref      string // like "simpleIOGenericIO/LLN0.EventsRCB01"
+	Buffered bool
+	RptID    string
+	DataSet  string // like "simpleIOGenericIO/LLN0$Events"
+	TrgOps   TrgOps
+	OptFlds  OptFlds
+	BufTm    uint32 // ms
+	IntgPd   uint32 // ms
+}
+
+// defaultStateFCs are the functional constraints of the settings and the configuration.
+var defaultStateFCs = []FC{SP, SV, CF, DC, SG, SE, BR, RP}
+
+// Snapshot writes the state of the attributes with the functional constraints to w as JSON, by default
+// the settings and the configuration (SP, SV, CF, DC, SG, SE) and the configuration of the report
+// control blocks (BR, RP). The active setting groups are part of SP, ALL includes every FC.
+//
+// The state of the controls, like selections and operations in progress, is not part of the snapshot,
+// after a restart the controls are unselected. The enabled and reserved report control blocks are not
+// saved either, clients have to enable them again.
+func (is *IedServer) Snapshot(w io.Writer, fcs ...FC) error {
+	if len(fcs) == 0 {
+		fcs = defaultStateFCs
+	}
+	state := &ServerState{Time: time.Now()}
+
+	is.LockDataModel()
+	is.stateAttributes(fcs, func(node *ModelNode, da *C.DataAttribute) {
+		if value := C.IedServer_getAttributeValue(is.server, da); value != nil {
+			state.Values = append(state.Values, newServerStateValue(node, value))
+		}
+	})
+	if hasFC(fcs, SP) {
+		for _, ld := range is.model.GetLogicalDevices() {
+			if sgcb := is.model.GetSettingGroupControlBlock(ld.GetName()); sgcb != nil {
+				state.SettingGroups = append(state.SettingGroups, ServerStateSettingGroup{
+					LD:    ld.GetName(),
+					ActSG: is.GetActiveSettingGroup(sgcb),
+				})
+			}
+		}
+	}
+	is.UnlockDataModel()
+
+	for _, rcb := range is.reportControlBlocks {
+		if hasFC(fcs, reportControlBlockFC(rcb)) {
+			state.ReportControlBlocks = append(state.ReportControlBlocks, newServerStateRCB(rcb))
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(state); err != nil {
+		return fmt.Errorf("Snapshot: %w", err)
+	}
+	return nil
+}
+
+// Restore loads the state written by Snapshot before Start, restricted to the functional constraints
+// with the same defaults, so status and measured values of a snapshot with all FCs can be left out.
+// Attributes, setting groups and report control blocks which are no longer in the model or changed
+// their type are skipped with a warning.
+//
+// Changed report control blocks are set in the model, libiec61850 reads them only when creating the server.
+// Restore rebuilds the underlying server instance for them, which installs the handlers and settings of
+// the IedServer again.
+func (is *IedServer) Restore(r io.Reader, fcs ...FC) error {
+	if is.IsRunning() {
+		return fmt.Errorf("Restore: server is running")
+	}
+	if len(fcs) == 0 {
+		fcs = defaultStateFCs
+	}
+	var state ServerState
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return fmt.Errorf("Restore: %w", err)
+	}
+
+	rcbsChanged := false
+	for _, rcbState := range state.ReportControlBlocks {
+		rcb := is.reportControlBlock(rcbState.Ref)
+		if rcb == nil || bool(C.ReportControlBlock_isBuffered(rcb)) != rcbState.Buffered {
+			is.logger().Warn("report control block not restored", "ref", rcbState.Ref)
+			continue
+		}
+		if hasFC(fcs, reportControlBlockFC(rcb)) {
+			is.setReportControlBlock(rcb, rcbState)
+			rcbsChanged = true
+		}
+	}
+	if rcbsChanged {
+		// libiec61850 reads the configuration of the report control blocks when creating the server
+		is.rebuild(is.tlsConfig)
+	}
+
+	if hasFC(fcs, SP) {
+		for _, sg := range state.SettingGroups {
+			sgcb := is.model.GetSettingGroupControlBlock(sg.LD)
+			if sgcb == nil {
+				is.logger().Warn("setting group not restored", "ld", sg.LD)
+				continue
+			}
+			if err := is.ChangeActiveSettingGroup(sgcb, sg.ActSG); err != nil {
+				return fmt.Errorf("Restore %q: %w", sg.LD, err)
+			}
+		}
+	}
+
+	type stateKey struct {
+		ref string
+		fc  FC
+	}
+	values := make(map[stateKey]ServerStateValue)
+	for _, value := range state.Values {
+		values[stateKey{value.Ref, value.FC}] = value
+	}
+
+	is.LockDataModel()
+	defer is.UnlockDataModel()
+	var err error
+	is.stateAttributes(fcs, func(node *ModelNode, da *C.DataAttribute) {
+		key := stateKey{node.ObjectReference, node.GetFC()}
+		value, ok := values[key]
+		if !ok || err != nil {
+			return
+		}
+		delete(values, key)
+		err = is.restoreAttributeValue(da, value)
+	})
+	if err != nil {
+		return err
+	}
+	for key := range values {
+		if hasFC(fcs, key.fc) {
+			is.logger().Warn("attribute not restored", "ref", key.ref, "fc", key.fc)
+		}
+	}
+	return nil
+}
+
+// hasFC returns whether the functional constraints include fc, ALL includes every FC.
+func hasFC(fcs []FC, fc FC) bool {
+	return slices.Contains(fcs, fc) || slices.Contains(fcs, ALL)
+}
+
+// stateAttributes calls fn for the basic attributes of the model with one of the functional constraints.
+func (is *IedServer) stateAttributes(fcs []FC, fn func(node *ModelNode, da *C.DataAttribute)) {
+	is.model.Walk(func(node *ModelNode) bool {
+		if node.GetType() != MODEL_NODE_DATA_ATTRIBUTE {
+			return true
+		}
+		if !hasFC(fcs, node.GetFC()) {
+			return false
+		}
+		if node.GetDataAttributeType() != DA_TYPE_CONSTRUCTED {
+			fn(node, (*C.DataAttribute)(node._modelNode))
+		}
+		return true
+	})
+}
+
+func newServerStateValue(node *ModelNode, value *C.MmsValue) ServerStateValue {
+	mmsType := MmsType(C.MmsValue_getType(value))
+	stateValue := ServerStateValue{Ref: node.ObjectReference, FC: node.GetFC(), Type: mmsType}
+	if goValue, err := toGoValue(value, mmsType); err == nil {
+		stateValue.Value = MmsValue{Type: mmsType, Value: goValue}.String()
+	}
+
+	size := C.MmsValue_encodeMmsData(value, nil, 0, false)
+	stateValue.Data = make([]byte, int(size))
+	if size > 0 {
+		C.MmsValue_encodeMmsData(value, (*C.uint8_t)(unsafe.Pointer(&stateValue.Data[0])), 0, true)
+	}
+	return stateValue
+}
+
+// restoreAttributeValue sets the attribute to the value of the state if it has the same type.
+func (is *IedServer) restoreAttributeValue(da *C.DataAttribute, value ServerStateValue) error {
+	if len(value.Data) == 0 {
+		return fmt.Errorf("Restore %q: no data", value.Ref)
+	}
+	buffer := C.CBytes(value.Data)
+	defer C.free(buffer)
+	var endBufPos C.int
+	mmsValue := C.MmsValue_decodeMmsData((*C.uint8_t)(buffer), 0, C.int(len(value.Data)), &endBufPos)
+	if mmsValue == nil {
+		return fmt.Errorf("Restore %q: invalid data", value.Ref)
+	}
+	defer C.MmsValue_delete(mmsValue)
+
+	current := C.IedServer_getAttributeValue(is.server, da)
+	if current == nil || C.MmsValue_getType(current) != C.MmsValue_getType(mmsValue) {
+		is.logger().Warn("attribute not restored, the type changed", "ref", value.Ref, "fc", value.FC)
+		return nil
+	}
+	C.IedServer_updateAttributeValue(is.server, da, mmsValue)
+	return nil
+}
+
+// reportControlBlocks returns the report control blocks of the model, which can only be listed before a
+// server is created for the model.
+func (m *IedModel) reportControlBlocks() []*C.ReportControlBlock {
+	var rcbs []*C.ReportControlBlock
+	for rcb := m.Model.rcbs; rcb != nil; rcb = rcb.sibling {
+		rcbs = append(rcbs, rcb)
+	}
+	return rcbs
+}
+
+// reportControlBlock returns the report control block with the reference, or nil.
+func (is *IedServer) reportControlBlock(ref string) *C.ReportControlBlock {
+	for _, rcb := range is.reportControlBlocks {
+		if reportControlBlockRef(rcb) == ref {
+			return rcb
+		}
+	}
+	return nil
+}
+
+func reportControlBlockRef(rcb *C.ReportControlBlock) string {
+	ln := newModelNode((*C.ModelNode)(unsafe.Pointer(C.ReportControlBlock_getParent(rcb))))
+	return ln.ObjectReference + "." + C.GoString(C.ReportControlBlock_getName(rcb))
+}
+
+func reportControlBlockFC(rcb *C.ReportControlBlock) FC {
+	if C.ReportControlBlock_isBuffered(rcb) {
+		return BR
+	}
+	return RP
+}
+
+// newServerStateRCB returns the current configuration of the report control block, which libiec61850
+// provides from the values of the server.
+func newServerStateRCB(rcb *C.ReportControlBlock) ServerStateRCB {
+	cRptID := C.ReportControlBlock_getRptID(rcb)
+	defer C.free(unsafe.Pointer(cRptID))
+	cDataSet := C.ReportControlBlock_getDataSet(rcb)
+	defer C.free(unsafe.Pointer(cDataSet))
+
+	rcbState := ServerStateRCB{
+		Ref:      reportControlBlockRef(rcb),
+		Buffered: bool(C.ReportControlBlock_isBuffered(rcb)),
+		TrgOps:   trgOpsFromServerBits(uint8(C.ReportControlBlock_getTrgOps(rcb))),
+		OptFlds:  optFldsFromBits(int(C.ReportControlBlock_getOptFlds(rcb))),
+		BufTm:    uint32(C.ReportControlBlock_getBufTm(rcb)),
+		IntgPd:   uint32(C.ReportControlBlock_getIntgPd(rcb)),
+	}
+	if cRptID != nil {
+		rcbState.RptID = C.GoString(cRptID)
+	}
+	if cDataSet != nil {
+		rcbState.DataSet = C.GoString(cDataSet)
+	}
+	return rcbState
+}
+
+// setReportControlBlock sets the configuration of the report control block in the model. The strings
+// of the dynamic model are allocated by libiec61850 and freed by IedModel_destroy, so the previous ones
+// are freed here and the new ones are left to the model.
+func (is *IedServer) setReportControlBlock(rcb *C.ReportControlBlock, rcbState ServerStateRCB) {
+	C.free(unsafe.Pointer(rcb.rptId))
+	rcb.rptId = cStringOrNil(rcbState.RptID)
+
+	// the model has the name of the data set in the logical node of the report control block
+	ln := newModelNode((*C.ModelNode)(unsafe.Pointer(C.ReportControlBlock_getParent(rcb))))
+	if rcbState.DataSet == "" {
+		C.free(unsafe.Pointer(rcb.dataSetName))
+		rcb.dataSetName = nil
+	} else if name, ok := strings.CutPrefix(rcbState.DataSet, ln.ObjectReference+"$"); ok {
+		C.free(unsafe.Pointer(rcb.dataSetName))
+		rcb.dataSetName = C.CString(name)
+	} else {
+		is.logger().Warn("data set of report control block not restored", "ref", rcbState.Ref, "dataSet", rcbState.DataSet)
+	}
+
+	rcb.trgOps = rcb.trgOps&rcbHasOwner | C.uint8_t(rcbState.TrgOps.bits())
+	rcb.options = C.uint8_t(rcbState.OptFlds.bits())
+	rcb.bufferTime = C.uint32_t(rcbState.BufTm)
+	rcb.intPeriod = C.uint32_t(rcbState.IntgPd)
+}
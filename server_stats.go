@@ -0,0 +1,262 @@
+package iec61850
+
+// #include <iec61850_server.h>
+import "C"
+
+import (
+	"sync"
+	"time"
+)
+
+// ServerStats is a snapshot of the counters of a server since EnableStats, for monitoring.
+//
+// The counters come from the public handlers of libiec61850 1.5.3, so requests are grouped by AccessService
+// instead of MMS service: reads of data objects checked by the read access handler, writes of data
+// attributes and RCB parameters, selects and operates of controls and file services. A read of a logical
+// node or of a data set counts once for each data object.
+//
+// libiec61850 1.5.3 has no public hooks for the MMS services themselves, the bytes sent and received on
+// the connections, the GOOSE messages sent by the GoCBs, the data set services, cancels of controls and
+// writes of other control blocks than RCBs, so these are not counted.
+type ServerStats struct {
+	Time time.Time
+	// OpenConnections is the number of connected clients.
+	OpenConnections int
+	// Connections is the number of clients connected since the server was created.
+	Connections uint64
+	// Services has the requests by AccessService group.
+	Services map[AccessService]ServiceStats
+	Controls ControlStats
+	// ReportControlBlocks has the reports by reference of the RCB, like "IEDLD/LLN0.EventsRCB01".
+	ReportControlBlocks map[string]RCBStats
+	// ClientConnections has the requests of the connected clients.
+	ClientConnections []ClientConnectionStats
+}
+
+// ServiceStats counts the requests of a service.
+type ServiceStats struct {
+	Requests uint64
+	// Rejected is the number of requests answered with an error.
+	Rejected uint64
+	// Denied is the number of requests denied by the role based access control, they are also rejected.
+	Denied uint64
+}
+
+// ControlStats counts the control operations of clients by their result. Operates fail when the perform
+// check, the wait for execution handler or the control handler rejects them.
+type ControlStats struct {
+	Selects        uint64
+	SelectsFailed  uint64
+	Operates       uint64
+	OperatesFailed uint64
+}
+
+// RCBStats counts the reports of a report control block.
+type RCBStats struct {
+	// Reports is the number of reports created for the clients.
+	Reports uint64
+	// Overflows is the number of reports lost because the buffer of the RCB was full.
+	Overflows uint64
+}
+
+// ClientConnectionStats counts the requests of a connected client.
+type ClientConnectionStats struct {
+	Connection *ClientConnection
+	Connected  time.Time
+	Services   map[AccessService]ServiceStats
+}
+
+// serverStats keeps the counters, they are updated by the threads of libiec61850 once enabled.
+type serverStats struct {
+	mu          sync.Mutex
+	enabled     bool
+	connections uint64
+	services    map[AccessService]ServiceStats
+	controls    ControlStats
+	rcbs        map[*C.ReportControlBlock]*RCBStats
+	// clients by the ID of the connection, until it is closed
+	clients map[uint64]*ClientConnectionStats
+}
+
+// Stats returns the current counters of the server.
+func (is *IedServer) Stats() ServerStats {
+	s := &is.stats
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := ServerStats{
+		Time:                time.Now(),
+		OpenConnections:     len(s.clients),
+		Connections:         s.connections,
+		Services:            make(map[AccessService]ServiceStats, len(s.services)),
+		Controls:            s.controls,
+		ReportControlBlocks: make(map[string]RCBStats, len(is.reportControlBlocks)),
+	}
+	for service, serviceStats := range s.services {
+		stats.Services[service] = serviceStats
+	}
+	for _, rcb := range is.reportControlBlocks {
+		var rcbStats RCBStats
+		if counters, ok := s.rcbs[rcb]; ok {
+			rcbStats = *counters
+		}
+		stats.ReportControlBlocks[reportControlBlockRef(rcb)] = rcbStats
+	}
+	for _, client := range s.clients {
+		clientStats := *client
+		clientStats.Services = make(map[AccessService]ServiceStats, len(client.Services))
+		for service, serviceStats := range client.Services {
+			clientStats.Services[service] = serviceStats
+		}
+		stats.ClientConnections = append(stats.ClientConnections, clientStats)
+	}
+	return stats
+}
+
+// EnableStats installs the bridges counting the connections, requests and reports of the server, which
+// are returned by Stats. It must be called before Start, after the model is complete.
+func (is *IedServer) EnableStats() {
+	is.stats.mu.Lock()
+	is.stats.enabled = true
+	is.stats.mu.Unlock()
+
+	is.installReadAccessHandler()
+	is.installFileAccessHandler()
+	is.installRCBEventHandler()
+	is.monitorModel()
+}
+
+func (s *serverStats) connected(connection *ClientConnection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled {
+		return
+	}
+	if s.clients == nil {
+		s.clients = make(map[uint64]*ClientConnectionStats)
+	}
+	s.connections++
+	s.clients[connection.ID] = &ClientConnectionStats{
+		Connection: connection,
+		Connected:  time.Now(),
+		Services:   make(map[AccessService]ServiceStats),
+	}
+}
+
+func (s *serverStats) disconnected(connection *ClientConnection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled {
+		return
+	}
+	delete(s.clients, connection.ID)
+}
+
+// request counts a request of the client, connection may be nil.
+func (s *serverStats) request(connection *ClientConnection, service AccessService, rejected bool) {
+	count := func(services map[AccessService]ServiceStats) {
+		serviceStats := services[service]
+		serviceStats.Requests++
+		if rejected {
+			serviceStats.Rejected++
+		}
+		services[service] = serviceStats
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled {
+		return
+	}
+	if s.services == nil {
+		s.services = make(map[AccessService]ServiceStats)
+	}
+	count(s.services)
+	if client := s.client(connection); client != nil {
+		count(client.Services)
+	}
+}
+
+// denied counts a request denied by the access control, connection may be nil.
+func (s *serverStats) denied(connection *ClientConnection, service AccessService) {
+	count := func(services map[AccessService]ServiceStats) {
+		serviceStats := services[service]
+		serviceStats.Denied++
+		services[service] = serviceStats
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled {
+		return
+	}
+	if s.services == nil {
+		s.services = make(map[AccessService]ServiceStats)
+	}
+	count(s.services)
+	if client := s.client(connection); client != nil {
+		count(client.Services)
+	}
+}
+
+func (s *serverStats) client(connection *ClientConnection) *ClientConnectionStats {
+	if connection == nil {
+		return nil
+	}
+	return s.clients[connection.ID]
+}
+
+// controlCheck counts a select or operate by the result of its perform check.
+func (s *serverStats) controlCheck(isSelect bool, accepted bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled {
+		return
+	}
+	if isSelect {
+		s.controls.Selects++
+		if !accepted {
+			s.controls.SelectsFailed++
+		}
+	} else {
+		s.controls.Operates++
+		if !accepted {
+			s.controls.OperatesFailed++
+		}
+	}
+}
+
+// operateFailed counts an operate that passed the perform check but failed in a later handler.
+func (s *serverStats) operateFailed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled {
+		return
+	}
+	s.controls.OperatesFailed++
+}
+
+func (s *serverStats) rcbEvent(rcb *C.ReportControlBlock, event RCBEventType) {
+	if event != RCB_EVENT_REPORT_CREATED && event != RCB_EVENT_OVERFLOW {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled {
+		return
+	}
+	if s.rcbs == nil {
+		s.rcbs = make(map[*C.ReportControlBlock]*RCBStats)
+	}
+	rcbStats, ok := s.rcbs[rcb]
+	if !ok {
+		rcbStats = &RCBStats{}
+		s.rcbs[rcb] = rcbStats
+	}
+	if event == RCB_EVENT_REPORT_CREATED {
+		rcbStats.Reports++
+	} else {
+		rcbStats.Overflows++
+	}
+}
@@ -0,0 +1,161 @@
+package iec61850
+
+/*
+#include <iec61850_server.h>
+
+extern void svcbEventHandlerBridge(SVControlBlock* svcb, int event, void* parameter);
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// SVCBEvent tells whether a client enabled or disabled a sampled value control block.
+type SVCBEvent int
+
+const (
+	SVCB_EVENT_DISABLE SVCBEvent = C.IEC61850_SVCB_EVENT_DISABLE
+	SVCB_EVENT_ENABLE  SVCBEvent = C.IEC61850_SVCB_EVENT_ENABLE
+)
+
+func (e SVCBEvent) String() string {
+	if e == SVCB_EVENT_ENABLE {
+		return "enable"
+	}
+	return "disable"
+}
+
+// SVCBConfig is the configuration of a sampled value control block of the server model.
+type SVCBConfig struct {
+	Ref     string // like "simpleIOGenericIO/LLN0.MSVCB01"
+	Name    string
+	SvID    string
+	DataSet string // like "simpleIOGenericIO/LLN0$PhsMeas"
+	ConfRev uint32
+	SmpMod  uint8 // 0: samples per period, 1: samples per second, 2: seconds per sample
+	SmpRate uint16
+	OptFlds SVOptFlds
+	Unicast bool
+	NoASDU  int
+	// Address is the destination of the sampled value messages, nil if the SVCB has none.
+	Address *PhyComAddress
+
+	svcb *C.SVControlBlock
+}
+
+// SVCBEventHandler is invoked when a client enables or disables a sampled value control block.
+type SVCBEventHandler func(config *SVCBConfig, event SVCBEvent)
+
+type svcbEventCallback struct {
+	config  *SVCBConfig
+	handler SVCBEventHandler
+	// publisher is notified before the handler, see SVCBPublisher
+	publisher func(event SVCBEvent)
+}
+
+var svcbEventCallbacks = make(map[int32]*svcbEventCallback)
+
+//export svcbEventHandlerBridge
+func svcbEventHandlerBridge(svcb *C.SVControlBlock, event C.int, parameter unsafe.Pointer) {
+	callbackId := int32(uintptr(parameter))
+	if call, ok := svcbEventCallbacks[callbackId]; ok {
+		if call.publisher != nil {
+			call.publisher(SVCBEvent(event))
+		}
+		if call.handler != nil {
+			call.handler(call.config, SVCBEvent(event))
+		}
+	}
+}
+
+// svcbEventCallback returns the callback of the SVCB, which is installed on first use since libiec61850
+// has a single handler per SVCB.
+func (is *IedServer) svcbEventCallback(config *SVCBConfig) *svcbEventCallback {
+	if call, ok := is.svcbEventCallbacks[config.svcb]; ok {
+		return call
+	}
+
+	callbackId := callbackIdGen.Add(1)
+	cPtr := intToPointerBug58625(callbackId)
+	call := &svcbEventCallback{config: config}
+	svcbEventCallbacks[callbackId] = call
+	if is.svcbEventCallbacks == nil {
+		is.svcbEventCallbacks = make(map[*C.SVControlBlock]*svcbEventCallback)
+	}
+	is.svcbEventCallbacks[config.svcb] = call
+
+	is.apply(func() {
+		C.IedServer_setSVCBHandler(is.server, config.svcb, (*[0]byte)(C.svcbEventHandlerBridge), cPtr)
+	})
+	return call
+}
+
+// SetSVCBEventHandler sets the handler for the sampled value control block enabled or disabled by clients.
+func (is *IedServer) SetSVCBEventHandler(svcbRef string, handler SVCBEventHandler) error {
+	config, err := is.model.GetSVCBConfig(svcbRef)
+	if err != nil {
+		return fmt.Errorf("SetSVCBEventHandler: %w", err)
+	}
+	is.svcbEventCallback(config).handler = handler
+	return nil
+}
+
+// GetSVCBConfigs returns the sampled value control blocks of the model.
+func (m *IedModel) GetSVCBConfigs() []*SVCBConfig {
+	var configs []*SVCBConfig
+	for svcb := m.Model.svCBs; svcb != nil; svcb = svcb.sibling {
+		configs = append(configs, newSVCBConfig(svcb))
+	}
+	return configs
+}
+
+// GetSVCBConfig returns the sampled value control block with the reference.
+func (m *IedModel) GetSVCBConfig(svcbRef string) (*SVCBConfig, error) {
+	for _, config := range m.GetSVCBConfigs() {
+		if config.Ref == svcbRef {
+			return config, nil
+		}
+	}
+	return nil, fmt.Errorf("GetSVCBConfig %q: %w", svcbRef, UserProvidedInvalidArgument)
+}
+
+func newSVCBConfig(svcb *C.SVControlBlock) *SVCBConfig {
+	ln := newModelNode((*C.ModelNode)(unsafe.Pointer(svcb.parent)))
+	optFlds := int(svcb.optFlds)
+	config := &SVCBConfig{
+		Ref:     ln.ObjectReference + "." + C.GoString(svcb.name),
+		Name:    C.GoString(svcb.name),
+		SvID:    C.GoString(svcb.svId),
+		ConfRev: uint32(svcb.confRev),
+		SmpMod:  uint8(svcb.smpMod),
+		SmpRate: uint16(svcb.smpRate),
+		OptFlds: SVOptFlds{
+			RefreshTime:        IsBitSet(optFlds, 0),
+			SampleSynchronized: IsBitSet(optFlds, 1),
+			SampleRate:         IsBitSet(optFlds, 2),
+			DataSet:            IsBitSet(optFlds, 3),
+			Security:           IsBitSet(optFlds, 4),
+		},
+		Unicast: bool(svcb.isUnicast),
+		NoASDU:  int(svcb.noASDU),
+		svcb:    svcb,
+	}
+	if svcb.dataSetName != nil {
+		ld, _, _ := strings.Cut(ln.ObjectReference, "/")
+		config.DataSet = ld + "/" + ln.GetName() + "$" + C.GoString(svcb.dataSetName)
+	}
+	if address := svcb.dstAddress; address != nil {
+		config.Address = &PhyComAddress{
+			VlanPriority: uint8(address.vlanPriority),
+			VlanId:       uint16(address.vlanId),
+			AppId:        uint16(address.appId),
+		}
+		for i := range config.Address.DstAddress {
+			config.Address.DstAddress[i] = uint8(address.dstAddress[i])
+		}
+	}
+	return config
+}
@@ -0,0 +1,320 @@
+//go:build linux && amd64
+
+package iec61850
+
+/*
+#include <iec61850_server.h>
+#include "sv_publisher.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SVCBPublisherOptions configures an SVCBPublisher.
+type SVCBPublisherOptions struct {
+	InterfaceID string  // like "eth0"
+	Frequency   float64 // nominal frequency for SmpMod samples per period, 50 when 0
+}
+
+// SVCBPublisher publishes the values of the data set of a sampled value control block with the
+// sample rate of the SVCB while a client has enabled it with SvEna. Each message carries one ASDU.
+type SVCBPublisher struct {
+	server    *IedServer
+	config    *SVCBConfig
+	publisher *SVPublisher
+	asdu      *SvPublisherASDU
+	samples   []svSample
+	interval  time.Duration
+	refrTm    bool
+
+	mu      sync.Mutex
+	running bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+type svSampleKind int
+
+const (
+	svSampleInt8 svSampleKind = iota
+	svSampleInt32
+	svSampleInt64
+	svSampleFloat
+	svSampleFloat64
+	svSampleQuality
+	svSampleTimestamp
+)
+
+// svSample is a basic value of the data set and its position in the ASDU.
+type svSample struct {
+	kind  svSampleKind
+	index int
+	value *C.MmsValue
+}
+
+// NewSVCBPublisher creates the publisher for the SVCB, which must have a destination address. It has
+// to be created before Start.
+func (is *IedServer) NewSVCBPublisher(svcbRef string, options SVCBPublisherOptions) (*SVCBPublisher, error) {
+	config, err := is.model.GetSVCBConfig(svcbRef)
+	if err != nil {
+		return nil, fmt.Errorf("NewSVCBPublisher: %w", err)
+	}
+	if config.Address == nil {
+		return nil, fmt.Errorf("NewSVCBPublisher %q: no destination address", svcbRef)
+	}
+	interval, wrap, err := svSampleInterval(config, options.Frequency)
+	if err != nil {
+		return nil, fmt.Errorf("NewSVCBPublisher %q: %w", svcbRef, err)
+	}
+
+	var dataSet *C.DataSet
+	if config.svcb.dataSetName != nil {
+		dataSet = C.LogicalNode_getDataSet(config.svcb.parent, config.svcb.dataSetName)
+	}
+	if dataSet == nil {
+		return nil, fmt.Errorf("NewSVCBPublisher %q: data set %q not found", svcbRef, config.DataSet)
+	}
+
+	p := &SVCBPublisher{
+		server: is,
+		config: config,
+		publisher: NewSVPublisher(SvPublisherConf{
+			EtherName:    options.InterfaceID,
+			AppID:        config.Address.AppId,
+			DstAddr:      config.Address.DstAddress,
+			VlanID:       config.Address.VlanId,
+			VlanPriority: config.Address.VlanPriority,
+		}),
+		interval: interval,
+		refrTm:   config.OptFlds.RefreshTime,
+	}
+	p.asdu = p.publisher.AddSvASDU(config.SvID, strings.Replace(config.DataSet, "$", ".", 1), config.ConfRev)
+	for entry := dataSet.fcdas; entry != nil; entry = entry.sibling {
+		if err := p.addSamples(entry.value); err != nil {
+			p.publisher.Destroy()
+			return nil, fmt.Errorf("NewSVCBPublisher %q: %s: %w", svcbRef, C.GoString(entry.variableName), err)
+		}
+	}
+	if p.refrTm {
+		p.asdu.EnableRefrTm()
+	}
+	p.asdu.SetSmpMod(config.SmpMod)
+	p.asdu.SetSmpRate(config.SmpRate)
+	p.asdu.SetSmpCntWrap(wrap)
+	p.publisher.SetupComplete()
+
+	is.svcbEventCallback(config).publisher = p.handleEvent
+	return p, nil
+}
+
+// svSampleInterval returns the time between samples and the number of samples per second.
+func svSampleInterval(config *SVCBConfig, frequency float64) (time.Duration, uint16, error) {
+	if config.SmpRate == 0 {
+		return 0, 0, fmt.Errorf("SmpRate 0")
+	}
+	if frequency == 0 {
+		frequency = 50
+	}
+	switch config.SmpMod {
+	case 0:
+		samplesPerSecond := float64(config.SmpRate) * frequency
+		return time.Duration(float64(time.Second) / samplesPerSecond), uint16(samplesPerSecond), nil
+	case 1:
+		return time.Second / time.Duration(config.SmpRate), config.SmpRate, nil
+	case 2:
+		return time.Duration(config.SmpRate) * time.Second, 1, nil
+	}
+	return 0, 0, fmt.Errorf("invalid SmpMod %d", config.SmpMod)
+}
+
+// addSamples adds the basic values of the data set member to the ASDU.
+func (p *SVCBPublisher) addSamples(value *C.MmsValue) error {
+	if value == nil {
+		return fmt.Errorf("no value")
+	}
+	mmsType := MmsType(C.MmsValue_getType(value))
+	if mmsType == Structure || mmsType == Array {
+		for i := 0; i < int(C.MmsValue_getArraySize(value)); i++ {
+			if err := p.addSamples(C.MmsValue_getElement(value, C.int(i))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	kind, err := svSampleKindOf(p.server.model, value, mmsType)
+	if err != nil {
+		return err
+	}
+	var index int
+	switch kind {
+	case svSampleInt8:
+		index = p.asdu.AddInt8()
+	case svSampleInt32:
+		index = p.asdu.AddInt32()
+	case svSampleInt64:
+		index = p.asdu.AddInt64()
+	case svSampleFloat:
+		index = p.asdu.AddFloat()
+	case svSampleFloat64:
+		index = p.asdu.AddFloat64()
+	case svSampleQuality:
+		index = p.asdu.AddQuality()
+	case svSampleTimestamp:
+		index = p.asdu.AddTimestamp()
+	}
+	p.samples = append(p.samples, svSample{kind: kind, index: index, value: value})
+	return nil
+}
+
+// svSampleKindOf returns the encoding of a basic value by the type of its data attribute.
+func svSampleKindOf(model *IedModel, value *C.MmsValue, mmsType MmsType) (svSampleKind, error) {
+	if da := C.IedModel_lookupDataAttributeByMmsValue(model.Model, value); da != nil {
+		switch DataAttributeType(C.DataAttribute_getType(da)) {
+		case DA_TYPE_BOOLEAN, DA_TYPE_INT8:
+			return svSampleInt8, nil
+		case DA_TYPE_INT64:
+			return svSampleInt64, nil
+		case DA_TYPE_FLOAT32:
+			return svSampleFloat, nil
+		case DA_TYPE_FLOAT64:
+			return svSampleFloat64, nil
+		case DA_TYPE_QUALITY:
+			return svSampleQuality, nil
+		case DA_TYPE_TIMESTAMP:
+			return svSampleTimestamp, nil
+		}
+	}
+	switch mmsType {
+	case Boolean:
+		return svSampleInt8, nil
+	case Integer, Unsigned:
+		return svSampleInt32, nil
+	case Float:
+		return svSampleFloat, nil
+	case BitString:
+		return svSampleQuality, nil
+	case UTCTime:
+		return svSampleTimestamp, nil
+	}
+	return 0, fmt.Errorf("type %s not supported in sampled values", mmsType)
+}
+
+// handleEvent starts and stops publishing with SvEna.
+func (p *SVCBPublisher) handleEvent(event SVCBEvent) {
+	if event == SVCB_EVENT_ENABLE {
+		p.start()
+	} else {
+		// not waiting for the goroutine, the data model may be locked by the server in the handler
+		p.halt(false)
+	}
+}
+
+func (p *SVCBPublisher) start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.running {
+		return
+	}
+	p.running = true
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.run(p.stop, p.done)
+}
+
+func (p *SVCBPublisher) halt(wait bool) {
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return
+	}
+	p.running = false
+	close(p.stop)
+	done := p.done
+	p.mu.Unlock()
+	if wait {
+		<-done
+	}
+}
+
+// IsRunning returns whether the publisher is sending, which is while SvEna is set.
+func (p *SVCBPublisher) IsRunning() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.running
+}
+
+func (p *SVCBPublisher) run(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.publish()
+		}
+	}
+}
+
+// publish sends the current values of the data set.
+func (p *SVCBPublisher) publish() {
+	asdu := p.asdu.cPublisherASDU
+	p.server.LockDataModel()
+	for _, sample := range p.samples {
+		index := C.int(sample.index)
+		switch sample.kind {
+		case svSampleInt8:
+			var v C.int8_t
+			if C.MmsValue_getType(sample.value) == C.MMS_BOOLEAN {
+				if C.MmsValue_getBoolean(sample.value) {
+					v = 1
+				}
+			} else {
+				v = C.int8_t(C.MmsValue_toInt32(sample.value))
+			}
+			C.SVPublisher_ASDU_setINT8(asdu, index, v)
+		case svSampleInt32:
+			C.SVPublisher_ASDU_setINT32(asdu, index, C.MmsValue_toInt32(sample.value))
+		case svSampleInt64:
+			C.SVPublisher_ASDU_setINT64(asdu, index, C.MmsValue_toInt64(sample.value))
+		case svSampleFloat:
+			C.SVPublisher_ASDU_setFLOAT(asdu, index, C.MmsValue_toFloat(sample.value))
+		case svSampleFloat64:
+			C.SVPublisher_ASDU_setFLOAT64(asdu, index, C.MmsValue_toDouble(sample.value))
+		case svSampleQuality:
+			C.SVPublisher_ASDU_setQuality(asdu, index, C.Quality(C.MmsValue_getBitStringAsInteger(sample.value)))
+		case svSampleTimestamp:
+			var timestamp C.Timestamp
+			C.Timestamp_setByMmsUtcTime(&timestamp, sample.value)
+			C.SVPublisher_ASDU_setTimestamp(asdu, index, timestamp)
+		}
+	}
+	p.server.UnlockDataModel()
+
+	if p.refrTm {
+		p.asdu.SetRefrTmMs(Now().GetTime().UnixMilli())
+	}
+	p.asdu.IncreaseSmpCnt()
+	p.publisher.Publish()
+}
+
+// Close stops publishing and frees the publisher.
+func (p *SVCBPublisher) Close() {
+	if call, ok := p.server.svcbEventCallbacks[p.config.svcb]; ok {
+		call.publisher = nil
+	}
+	p.halt(true)
+	p.publisher.Destroy()
+}
+
+// Config returns the configuration of the SVCB.
+func (p *SVCBPublisher) Config() *SVCBConfig {
+	return p.config
+}
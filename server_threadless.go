@@ -0,0 +1,45 @@
+package iec61850
+
+// #include <iec61850_server.h>
+import "C"
+
+import (
+	"fmt"
+	"time"
+)
+
+// StartThreadless starts the server on the port without the threads of libiec61850. The server handles
+// requests and its periodic tasks, like reports, GOOSE retransmissions and control timeouts, only when
+// Poll is called, and all handlers run on the goroutine calling Poll. Stop stops the server.
+func (is *IedServer) StartThreadless(port int) error {
+	if err := is.StartWithOptions(StartOptions{Port: port, AccessPoints: is.accessPoints, Threadless: true}); err != nil {
+		return fmt.Errorf("StartThreadless: %w", err)
+	}
+	return nil
+}
+
+// Poll waits up to timeout for requests of clients, handles them and performs the periodic tasks of a
+// server started without threads.
+func (is *IedServer) Poll(timeout time.Duration) {
+	is.WaitReady(timeout)
+	is.ProcessIncomingData()
+	is.PerformPeriodicTasks()
+}
+
+// WaitReady waits up to timeout until a connection of a server started without threads has data,
+// true if there is data to process.
+func (is *IedServer) WaitReady(timeout time.Duration) bool {
+	return C.IedServer_waitReady(is.server, C.uint(timeout.Milliseconds())) != 0
+}
+
+// ProcessIncomingData accepts new connections and handles the requests of the clients of a server
+// started without threads, it doesn't wait for requests.
+func (is *IedServer) ProcessIncomingData() {
+	C.IedServer_processIncomingData(is.server)
+}
+
+// PerformPeriodicTasks sends due reports and GOOSE messages and handles the timeouts of a server started
+// without threads, the more often it is called the more accurate are the times.
+func (is *IedServer) PerformPeriodicTasks() {
+	C.IedServer_performPeriodicTasks(is.server)
+}
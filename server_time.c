@@ -0,0 +1,67 @@
+#include "server_time.h"
+
+#ifdef _WIN32
+#include <windows.h>
+#else
+#include <time.h>
+#endif
+
+// The time functions of the HAL of libiec61850, which are replaced by these ones to take the time from the
+// time source of Go when one is installed.
+
+// 100 ns intervals from 1601 to 1970
+#define FILETIME_UNIX_EPOCH 116444736000000000ULL
+
+static volatile bool timeSourceInstalled = false;
+
+void setTimeSourceInstalled(bool installed) {
+    timeSourceInstalled = installed;
+}
+
+static nsSinceEpoch systemTimeInNs(void) {
+#ifdef _WIN32
+    FILETIME fileTime;
+    ULARGE_INTEGER t;
+
+    GetSystemTimeAsFileTime(&fileTime);
+    t.LowPart = fileTime.dwLowDateTime;
+    t.HighPart = fileTime.dwHighDateTime;
+    return (nsSinceEpoch) (t.QuadPart - FILETIME_UNIX_EPOCH) * 100;
+#else
+    struct timespec ts;
+
+    clock_gettime(CLOCK_REALTIME, &ts);
+    return (nsSinceEpoch) ts.tv_sec * 1000000000ULL + (nsSinceEpoch) ts.tv_nsec;
+#endif
+}
+
+nsSinceEpoch Hal_getTimeInNs(void) {
+    if (timeSourceInstalled)
+        return timeSourceNowNs();
+    return systemTimeInNs();
+}
+
+msSinceEpoch Hal_getTimeInMs(void) {
+    return Hal_getTimeInNs() / 1000000ULL;
+}
+
+bool Hal_setTimeInNs(nsSinceEpoch nsTime) {
+#ifdef _WIN32
+    FILETIME fileTime;
+    SYSTEMTIME systemTime;
+    ULARGE_INTEGER t;
+
+    t.QuadPart = nsTime / 100 + FILETIME_UNIX_EPOCH;
+    fileTime.dwLowDateTime = t.LowPart;
+    fileTime.dwHighDateTime = t.HighPart;
+    if (!FileTimeToSystemTime(&fileTime, &systemTime))
+        return false;
+    return SetSystemTime(&systemTime);
+#else
+    struct timespec ts;
+
+    ts.tv_sec = nsTime / 1000000000ULL;
+    ts.tv_nsec = nsTime % 1000000000ULL;
+    return clock_settime(CLOCK_REALTIME, &ts) == 0;
+#endif
+}
@@ -0,0 +1,165 @@
+package iec61850
+
+/*
+#include <iec61850_server.h>
+#include "server_time.h"
+*/
+import "C"
+
+import (
+	"sync"
+	"time"
+)
+
+// TIME_ACCURACY_UNSPECIFIED is the TimeAccuracy of a clock with unknown accuracy.
+const TIME_ACCURACY_UNSPECIFIED = 31
+
+// TimeQuality is the quality of the time of a TimeSource, which is part of every timestamp.
+type TimeQuality struct {
+	// LeapSecondKnown tells that the time includes all leap seconds, time.Time has UTC without them.
+	LeapSecondKnown      bool
+	ClockFailure         bool
+	ClockNotSynchronized bool
+	// TimeAccuracy is the number of significant bits of the fraction of second, like 10 for about 1 ms.
+	TimeAccuracy int
+}
+
+// TimeSource provides the time of the servers and its quality, like of a PTP clock.
+//
+// Now is called by the threads of libiec61850 whenever it needs the time, also for its timers, so it has
+// to be fast and must not call methods of the servers. The time should not jump, a time moved backwards
+// delays the timers of libiec61850, like the integrity periods of reports.
+type TimeSource interface {
+	Now() (time.Time, TimeQuality)
+}
+
+var (
+	timeSourceLock sync.Mutex
+	timeSource     TimeSource
+	// timeQuality is the last quality of the time source, which the servers apply to the timestamps they create
+	timeQuality        TimeQuality
+	timeQualityServers = make(map[*IedServer]bool)
+)
+
+// SetProcessTimeSource replaces the clock of libiec61850 for the whole process by the time source, nil
+// restores the system clock. The time source is used for all timestamps created automatically, like of
+// controls, the time of entry of reports and log entries, and the timestamps of UpdateDataObject. The
+// servers set the quality of the time source in the timestamps libiec61850 creates for controls and in
+// UpdateUTCTimeAttributeValue.
+//
+// libiec61850 has one clock, the Hal_getTimeInMs and Hal_getTimeInNs functions of its HAL, which are
+// replaced. So the time source applies to all servers, clients, GOOSE and SV publishers and subscribers
+// of the process, and also to their timers, like the select timeouts and time activated operations of
+// controls, the buffer times and integrity periods of reports and the retransmissions of GOOSE.
+func SetProcessTimeSource(source TimeSource) {
+	quality := TimeQuality{}
+	if source != nil {
+		_, quality = source.Now()
+	}
+	timeSourceLock.Lock()
+	timeSource = source
+	timeSourceLock.Unlock()
+	C.setTimeSourceInstalled(C.bool(source != nil))
+	updateTimeQuality(quality)
+}
+
+// Now returns the time of the time source as timestamp with the quality flags, or the time of the system
+// clock without a time source.
+func Now() *Timestamp {
+	now, quality := currentTime()
+	return NewTimestamp(now).
+		SetLeapSecondKnown(quality.LeapSecondKnown).
+		SetClockFailure(quality.ClockFailure).
+		SetClockNotSynchronized(quality.ClockNotSynchronized).
+		SetSubSecondPrecision(quality.TimeAccuracy)
+}
+
+// currentTime returns the time and quality of the time source and updates the quality of the servers
+// when it changed.
+func currentTime() (time.Time, TimeQuality) {
+	timeSourceLock.Lock()
+	source := timeSource
+	timeSourceLock.Unlock()
+	if source == nil {
+		return time.Now(), TimeQuality{}
+	}
+	now, quality := source.Now()
+	updateTimeQuality(quality)
+	return now, quality
+}
+
+//export timeSourceNowNs
+func timeSourceNowNs() C.uint64_t {
+	now, _ := currentTime()
+	return C.uint64_t(now.UnixNano())
+}
+
+func updateTimeQuality(quality TimeQuality) {
+	timeSourceLock.Lock()
+	defer timeSourceLock.Unlock()
+	if quality == timeQuality {
+		return
+	}
+	timeQuality = quality
+	for is := range timeQualityServers {
+		is.setTimeQuality(quality)
+	}
+}
+
+// trackTimeQuality keeps the time quality of the server at the quality of the time source.
+func (is *IedServer) trackTimeQuality() {
+	timeSourceLock.Lock()
+	timeQualityServers[is] = true
+	timeSourceLock.Unlock()
+
+	is.apply(func() {
+		timeSourceLock.Lock()
+		defer timeSourceLock.Unlock()
+		is.setTimeQuality(timeQuality)
+	})
+}
+
+func (is *IedServer) untrackTimeQuality() {
+	timeSourceLock.Lock()
+	delete(timeQualityServers, is)
+	timeSourceLock.Unlock()
+}
+
+func (is *IedServer) setTimeQuality(quality TimeQuality) {
+	C.IedServer_setTimeQuality(is.server, C.bool(quality.LeapSecondKnown), C.bool(quality.ClockFailure),
+		C.bool(quality.ClockNotSynchronized), C.int(quality.TimeAccuracy))
+}
+
+// SystemTimeSource is the system clock with an offset and a quality set by the application, like to
+// simulate a clock which lost its synchronization.
+type SystemTimeSource struct {
+	mu      sync.Mutex
+	offset  time.Duration
+	quality TimeQuality
+}
+
+// NewSystemTimeSource creates the time source with the quality.
+func NewSystemTimeSource(quality TimeQuality) *SystemTimeSource {
+	return &SystemTimeSource{quality: quality}
+}
+
+// Now returns the time of the system clock moved by the offset.
+func (s *SystemTimeSource) Now() (time.Time, TimeQuality) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Now().Add(s.offset), s.quality
+}
+
+// SetOffset moves the time by offset from the system clock.
+func (s *SystemTimeSource) SetOffset(offset time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset = offset
+}
+
+// SetQuality changes the quality of the time.
+func (s *SystemTimeSource) SetQuality(quality TimeQuality) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.quality = quality
+}
@@ -0,0 +1,9 @@
+#include <hal_time.h>
+#include <stdbool.h>
+#include <stdint.h>
+
+// Switches the clock of libiec61850 between the system clock and the time source of Go.
+void setTimeSourceInstalled(bool installed);
+
+// Declare the Go function
+uint64_t timeSourceNowNs(void);
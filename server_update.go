@@ -0,0 +1,166 @@
+package iec61850
+
+// #include <iec61850_server.h>
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// UpdateBooleanAttributeValue updates a DataAttribute with a boolean value.
+func (is *IedServer) UpdateBooleanAttributeValue(node *ModelNode, value bool) {
+	if node == nil || node._modelNode == nil {
+		return
+	}
+	C.IedServer_updateBooleanAttributeValue(is.server, (*C.DataAttribute)(node._modelNode), C.bool(value))
+}
+
+// UpdateInt64AttributeValue updates a DataAttribute with an Int64 value.
+func (is *IedServer) UpdateInt64AttributeValue(node *ModelNode, value int64) {
+	if node == nil || node._modelNode == nil {
+		return
+	}
+	C.IedServer_updateInt64AttributeValue(is.server, (*C.DataAttribute)(node._modelNode), C.int64_t(value))
+}
+
+// UpdateUnsignedAttributeValue updates a DataAttribute with an unsigned value.
+func (is *IedServer) UpdateUnsignedAttributeValue(node *ModelNode, value uint32) {
+	if node == nil || node._modelNode == nil {
+		return
+	}
+	C.IedServer_updateUnsignedAttributeValue(is.server, (*C.DataAttribute)(node._modelNode), C.uint32_t(value))
+}
+
+// UpdateBitStringAttributeValue updates a DataAttribute with a bit string value, bit 0 is the least
+// significant bit of value.
+func (is *IedServer) UpdateBitStringAttributeValue(node *ModelNode, value uint32) {
+	if node == nil || node._modelNode == nil {
+		return
+	}
+	C.IedServer_updateBitStringAttributeValue(is.server, (*C.DataAttribute)(node._modelNode), C.uint32_t(value))
+}
+
+// UpdateDbposValue updates a double point status attribute like the stVal of DPS and DPC.
+func (is *IedServer) UpdateDbposValue(node *ModelNode, value Dbpos) {
+	if node == nil || node._modelNode == nil {
+		return
+	}
+	C.IedServer_updateDbposValue(is.server, (*C.DataAttribute)(node._modelNode), C.Dbpos(value))
+}
+
+// UpdateTimestampAttributeValue updates a DataAttribute with a time stamp including its quality flags
+// like clock failure and clock not synchronized.
+func (is *IedServer) UpdateTimestampAttributeValue(node *ModelNode, value *Timestamp) {
+	if node == nil || node._modelNode == nil || value == nil {
+		return
+	}
+	C.IedServer_updateTimestampAttributeValue(is.server, (*C.DataAttribute)(node._modelNode), &value.cTimestamp)
+}
+
+// UpdateOctetStringAttributeValue updates a DataAttribute with an octet string value.
+func (is *IedServer) UpdateOctetStringAttributeValue(node *ModelNode, value []byte) error {
+	if node == nil || node._modelNode == nil {
+		return fmt.Errorf("UpdateOctetStringAttributeValue: %w", UserProvidedInvalidArgument)
+	}
+	return is.updateAttributeValue("UpdateOctetStringAttributeValue", node, value)
+}
+
+// UpdateEnumAttributeValue updates an ENUMERATED DataAttribute with the ordinal value of the enumeration.
+func (is *IedServer) UpdateEnumAttributeValue(node *ModelNode, value int32) {
+	is.UpdateInt32AttributeValue(node, value)
+}
+
+// UpdateAttributeValue updates a DataAttribute with any value, including bit strings like Check, TrgOps
+// and OptFlds and structures like PhyComAddr. The value is created from its Type, which has to match the
+// type of the attribute. Bit strings, also those of structures, take the size of the attribute.
+func (is *IedServer) UpdateAttributeValue(node *ModelNode, value *MmsValue) error {
+	if node == nil || node._modelNode == nil || value == nil {
+		return fmt.Errorf("UpdateAttributeValue: %w", UserProvidedInvalidArgument)
+	}
+	da := (*C.DataAttribute)(node._modelNode)
+	if C.ModelNode_getType((*C.ModelNode)(node._modelNode)) != C.DataAttributeModelType {
+		return fmt.Errorf("UpdateAttributeValue %q: not a data attribute", node.ObjectReference)
+	}
+	current := C.IedServer_getAttributeValue(is.server, da)
+	if current == nil {
+		return fmt.Errorf("UpdateAttributeValue %q: attribute has no value", node.ObjectReference)
+	}
+
+	mmsValue, err := toMmsValueLike(value.Type, value.Value, current)
+	if err != nil {
+		return fmt.Errorf("UpdateAttributeValue %q: %w", node.ObjectReference, err)
+	}
+	defer C.MmsValue_delete(mmsValue)
+
+	// libiec61850 requires the value to have the type, sizes and elements of the attribute
+	check := C.MmsValue_clone(current)
+	defer C.MmsValue_delete(check)
+	if C.MmsValue_getType(mmsValue) != C.MmsValue_getType(current) || !bool(C.MmsValue_update(check, mmsValue)) {
+		return fmt.Errorf("UpdateAttributeValue %q: value of type %d does not match the attribute: %w",
+			node.ObjectReference, value.Type, UserProvidedInvalidArgument)
+	}
+	C.IedServer_updateAttributeValue(is.server, da, mmsValue)
+	return nil
+}
+
+func (is *IedServer) updateAttributeValue(caller string, node *ModelNode, value any) error {
+	da := (*C.DataAttribute)(node._modelNode)
+	if C.ModelNode_getType((*C.ModelNode)(node._modelNode)) != C.DataAttributeModelType {
+		return fmt.Errorf("%s %q: not a data attribute", caller, node.ObjectReference)
+	}
+	mmsValue, err := newDataAttributeValue(DataAttributeType(C.DataAttribute_getType(da)), value)
+	if err != nil {
+		return fmt.Errorf("%s %q: %w", caller, node.ObjectReference, err)
+	}
+	defer C.MmsValue_delete(mmsValue)
+	C.IedServer_updateAttributeValue(is.server, da, mmsValue)
+	return nil
+}
+
+// UpdateDataObject sets the value, the quality q and the time stamp t of a status or measured value
+// data object together while the data model is locked. Clients never see the new value with the old
+// quality or time stamp and the changes are reported together.
+// The value attribute is stVal, or mag.f respectively mag.i for measured values. timestamp nil uses the
+// time of Now, including the quality of the TimeSource.
+func (is *IedServer) UpdateDataObject(node *ModelNode, value any, quality Quality, timestamp *Timestamp) error {
+	if node == nil || node._modelNode == nil {
+		return fmt.Errorf("UpdateDataObject: %w", UserProvidedInvalidArgument)
+	}
+	valueNode := modelNodeChild(node, "stVal", "mag$f", "mag$i")
+	if valueNode == nil {
+		return fmt.Errorf("UpdateDataObject %q: no stVal or mag attribute", node.ObjectReference)
+	}
+	qNode := modelNodeChild(node, "q")
+	tNode := modelNodeChild(node, "t")
+	if timestamp == nil {
+		timestamp = Now()
+	}
+
+	is.LockDataModel()
+	defer is.UnlockDataModel()
+
+	if err := is.updateAttributeValue("UpdateDataObject", valueNode, value); err != nil {
+		return err
+	}
+	if qNode != nil {
+		is.UpdateQuality(qNode, uint16(quality))
+	}
+	is.UpdateTimestampAttributeValue(tNode, timestamp)
+	return nil
+}
+
+// modelNodeChild returns the first existing child of names, which may refer to sub attributes
+// separated by $.
+func modelNodeChild(node *ModelNode, names ...string) *ModelNode {
+	for _, name := range names {
+		cName := C.CString(name)
+		child := C.ModelNode_getChild((*C.ModelNode)(node._modelNode), cName)
+		C.free(unsafe.Pointer(cName))
+		if child != nil {
+			return &ModelNode{ObjectReference: node.ObjectReference + "." + strings.ReplaceAll(name, "$", "."), _modelNode: unsafe.Pointer(child)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package iec61850
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Snapshot records the data model and the attribute values of a server at one point in time.
+// It can be stored as JSON or YAML and compared with an earlier snapshot by DiffSnapshots.
+type Snapshot struct {
+	Time   time.Time
+	Host   string
+	Port   int
+	Model  DataModel
+	Values []SnapshotValue
+}
+
+// SnapshotValue is the value of a leaf attribute, formatted as text.
+// Error is set instead of Value when the attribute could not be read.
+type SnapshotValue struct {
+	Ref   string
+	Type  MmsType
+	Value string
+	Error string
+}
+
+// GetSnapshot reads the data model and the values of all attributes of the server.
+func (c *Client) GetSnapshot() (*Snapshot, error) {
+	dataModel, err := c.GetDataModel()
+	if err != nil {
+		return nil, fmt.Errorf("GetSnapshot: %w", err)
+	}
+	values, err := c.GetVariableValues()
+	if err != nil {
+		return nil, fmt.Errorf("GetSnapshot: %w", err)
+	}
+
+	snapshot := &Snapshot{
+		Time:  time.Now(),
+		Host:  c.settings.Host,
+		Port:  c.settings.Port,
+		Model: dataModel,
+	}
+	for _, v := range values {
+		value := SnapshotValue{Ref: v.Ref, Type: v.Type}
+		if err, ok := v.Value.(error); ok {
+			value.Error = err.Error()
+		} else {
+			value.Value = MmsValue{Type: v.Type, Value: v.Value}.String()
+		}
+		snapshot.Values = append(snapshot.Values, value)
+	}
+	// values are read concurrently, sort them so snapshot files can be compared textually
+	slices.SortStableFunc(snapshot.Values, func(a, b SnapshotValue) int {
+		return strings.Compare(a.Ref, b.Ref)
+	})
+	return snapshot, nil
+}
+
+// WriteJSON writes the snapshot as indented JSON.
+func (s *Snapshot) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// WriteYAML writes the snapshot as YAML.
+func (s *Snapshot) WriteYAML(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(s); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// Save writes the snapshot to a file, as YAML if the name ends with .yaml or .yml and as JSON otherwise.
+func (s *Snapshot) Save(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("Save %q: %w", filename, err)
+	}
+	defer f.Close()
+
+	if isYAMLFile(filename) {
+		err = s.WriteYAML(f)
+	} else {
+		err = s.WriteJSON(f)
+	}
+	if err != nil {
+		return fmt.Errorf("Save %q: %w", filename, err)
+	}
+	return f.Close()
+}
+
+// LoadSnapshot reads a snapshot written by Save.
+func LoadSnapshot(filename string) (*Snapshot, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("LoadSnapshot %q: %w", filename, err)
+	}
+
+	snapshot := &Snapshot{}
+	if isYAMLFile(filename) {
+		err = yaml.Unmarshal(data, snapshot)
+	} else {
+		err = json.Unmarshal(data, snapshot)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("LoadSnapshot %q: %w", filename, err)
+	}
+	return snapshot, nil
+}
+
+func isYAMLFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ext == ".yaml" || ext == ".yml"
+}
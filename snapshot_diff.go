@@ -0,0 +1,231 @@
+package iec61850
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// ChangeKind is the kind of a difference between two snapshots.
+type ChangeKind int
+
+const (
+	NodeAdded ChangeKind = iota
+	NodeRemoved
+	TypeChanged
+	ValueChanged
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case NodeAdded:
+		return "added"
+	case NodeRemoved:
+		return "removed"
+	case TypeChanged:
+		return "type changed"
+	case ValueChanged:
+		return "value changed"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", int(k))
+	}
+}
+
+// SnapshotChange is a difference between two snapshots.
+type SnapshotChange struct {
+	Kind ChangeKind
+	// Node is the kind of the changed node: LD, LN, DO, DA, DS, URCB, BRCB, LCB, GoCB, MSVCB, USVCB,
+	// SGCB, Log, or Value for attribute values.
+	Node string
+	Ref  string
+	// Old and New describe the type or value before and after the change, empty for added and removed nodes
+	// without type.
+	Old string
+	New string
+}
+
+func (c SnapshotChange) String() string {
+	switch c.Kind {
+	case NodeAdded:
+		return strings.TrimSpace(fmt.Sprintf("+ %s %s %s", c.Node, c.Ref, c.New))
+	case NodeRemoved:
+		return strings.TrimSpace(fmt.Sprintf("- %s %s %s", c.Node, c.Ref, c.Old))
+	default:
+		return fmt.Sprintf("~ %s %s: %s -> %s", c.Node, c.Ref, c.Old, c.New)
+	}
+}
+
+// DiffOptions controls which differences DiffSnapshots reports.
+type DiffOptions struct {
+	// IgnoreValues only compares the data models.
+	IgnoreValues bool
+	// Ignore holds path.Match patterns, changes of matching references are not reported.
+	// "*" does not match the "/" behind the logical device, "*/*.t" ignores all time stamps.
+	Ignore []string
+}
+
+// DiffSnapshots reports the nodes added and removed between two snapshots, changed types of data objects
+// and attributes, changed data set members and control block attributes, and changed attribute values.
+// Values of added or removed attributes are not reported separately from the attributes.
+// The changes are sorted by reference.
+func DiffSnapshots(old, new *Snapshot, opts DiffOptions) []SnapshotChange {
+	var changes []SnapshotChange
+	add := func(change SnapshotChange) {
+		for _, pattern := range opts.Ignore {
+			if ok, _ := path.Match(pattern, change.Ref); ok {
+				return
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	oldKeys, oldNodes := flattenDataModel(old.Model)
+	newKeys, newNodes := flattenDataModel(new.Model)
+	for _, key := range oldKeys {
+		o := oldNodes[key]
+		n, ok := newNodes[key]
+		switch {
+		case !ok:
+			add(SnapshotChange{Kind: NodeRemoved, Node: o.node, Ref: o.ref, Old: o.typ})
+		case o.typ != n.typ:
+			add(SnapshotChange{Kind: TypeChanged, Node: o.node, Ref: o.ref, Old: o.typ, New: n.typ})
+		case o.value != n.value:
+			add(SnapshotChange{Kind: ValueChanged, Node: o.node, Ref: o.ref, Old: o.value, New: n.value})
+		}
+	}
+	for _, key := range newKeys {
+		if _, ok := oldNodes[key]; !ok {
+			n := newNodes[key]
+			add(SnapshotChange{Kind: NodeAdded, Node: n.node, Ref: n.ref, New: n.typ})
+		}
+	}
+
+	if !opts.IgnoreValues {
+		newValues := make(map[string]SnapshotValue, len(new.Values))
+		for _, v := range new.Values {
+			newValues[v.Ref] = v
+		}
+		for _, o := range old.Values {
+			n, ok := newValues[o.Ref]
+			switch {
+			case !ok:
+			case o.Type != n.Type:
+				add(SnapshotChange{Kind: TypeChanged, Node: "Value", Ref: o.Ref, Old: o.Type.String(), New: n.Type.String()})
+			case o.text() != n.text():
+				add(SnapshotChange{Kind: ValueChanged, Node: "Value", Ref: o.Ref, Old: o.text(), New: n.text()})
+			}
+		}
+	}
+
+	slices.SortStableFunc(changes, func(a, b SnapshotChange) int {
+		return strings.Compare(a.Ref, b.Ref)
+	})
+	return changes
+}
+
+func (v SnapshotValue) text() string {
+	if v.Error != "" {
+		return "error: " + v.Error
+	}
+	return v.Value
+}
+
+// snapshotNode is a node of a data model flattened for comparison.
+type snapshotNode struct {
+	node  string
+	ref   string
+	typ   string
+	value string
+}
+
+// flattenDataModel returns the nodes of a data model keyed by node kind and reference, and the keys in model order.
+func flattenDataModel(dataModel DataModel) ([]string, map[string]snapshotNode) {
+	var keys []string
+	nodes := make(map[string]snapshotNode)
+	add := func(n snapshotNode) {
+		key := n.node + " " + n.ref
+		if _, ok := nodes[key]; !ok {
+			keys = append(keys, key)
+		}
+		nodes[key] = n
+	}
+
+	var addDA func(da DA)
+	addDA = func(da DA) {
+		typ := da.BType
+		if typ == "" {
+			typ = da.Type.String()
+		}
+		if da.Count > 0 {
+			typ = fmt.Sprintf("%s[%d]", typ, da.Count)
+		}
+		add(snapshotNode{node: "DA", ref: da.Ref, typ: da.FC.String() + " " + typ})
+		for _, child := range da.DAs {
+			addDA(child)
+		}
+	}
+	var addDO func(do DO)
+	addDO = func(do DO) {
+		add(snapshotNode{node: "DO", ref: do.Ref, typ: do.CDC})
+		for _, da := range do.DAs {
+			addDA(da)
+		}
+		for _, sdo := range do.SDOs {
+			addDO(sdo)
+		}
+	}
+	// control blocks are compared by their attributes without name and reference
+	attributes := func(v any) string {
+		return fmt.Sprintf("%+v", v)
+	}
+
+	for _, ld := range dataModel.LDs {
+		add(snapshotNode{node: "LD", ref: ld.Data})
+		for _, ln := range ld.LNs {
+			add(snapshotNode{node: "LN", ref: ln.Ref})
+			for _, do := range ln.DOs {
+				addDO(do)
+			}
+			for _, ds := range ln.DSs {
+				members := make([]string, len(ds.DSRefs))
+				for i, member := range ds.DSRefs {
+					members[i] = member.Data
+				}
+				add(snapshotNode{node: "DS", ref: ln.Ref + "." + ds.Data, value: strings.Join(members, ", ")})
+			}
+			for _, r := range ln.URReports {
+				add(snapshotNode{node: "URCB", ref: r.Ref, value: attributes(r.ReportAttributes)})
+			}
+			for _, r := range ln.BRReports {
+				add(snapshotNode{node: "BRCB", ref: r.Ref, value: attributes(r.ReportAttributes)})
+			}
+			for _, cb := range ln.LCBs {
+				ref := cb.Ref
+				cb.Data, cb.Ref = "", ""
+				add(snapshotNode{node: "LCB", ref: ref, value: attributes(cb)})
+			}
+			for _, cb := range ln.GoCBs {
+				ref := cb.Ref
+				cb.Data, cb.Ref = "", ""
+				add(snapshotNode{node: "GoCB", ref: ref, value: attributes(cb)})
+			}
+			for _, cb := range ln.SVCBs {
+				node := "USVCB"
+				if cb.Multicast {
+					node = "MSVCB"
+				}
+				ref := cb.Ref
+				cb.Data, cb.Ref = "", ""
+				add(snapshotNode{node: node, ref: ref, value: attributes(cb)})
+			}
+			if ln.SGCB != nil {
+				add(snapshotNode{node: "SGCB", ref: ln.SGCB.Ref, value: attributes(ln.SGCB.SettingGroup)})
+			}
+			for _, l := range ln.Logs {
+				add(snapshotNode{node: "Log", ref: l.Ref})
+			}
+		}
+	}
+	return keys, nodes
+}
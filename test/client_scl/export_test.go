@@ -0,0 +1,88 @@
+package client_scl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/scl"
+)
+
+const port = 10102
+
+func TestExportSCL(t *testing.T) {
+	model, err := iec61850.CreateModelFromConfigFileEx("../tls_server/model.cfg")
+	if err != nil {
+		t.Fatalf("create model error %v\n", err)
+	}
+	defer model.Destroy()
+
+	server := iec61850.NewServerWithConfig(iec61850.NewServerConfig(), model)
+	server.Start(port)
+	defer server.Destroy()
+	defer server.Stop()
+
+	settings := iec61850.NewSettings()
+	settings.Port = port
+	client, err := iec61850.NewClient(settings)
+	if err != nil {
+		t.Fatalf("create client error %v\n", err)
+	}
+	defer client.Close()
+
+	filename := filepath.Join(t.TempDir(), "exported.icd")
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatalf("create %s error %v\n", filename, err)
+	}
+	if err := client.ExportSCL(f); err != nil {
+		t.Fatalf("export SCL error %v\n", err)
+	}
+	f.Close()
+
+	parsed, err := scl.NewParser(filename).Parse()
+	if err != nil {
+		t.Fatalf("parse exported SCL error %v\n", err)
+	}
+
+	// a single logical device is exported as instance of the TEMPLATE IED
+	ied := parsed.IEDs[0]
+	if ied.Name != "TEMPLATE" {
+		t.Errorf("IED name %q, want TEMPLATE\n", ied.Name)
+	}
+	lds := ied.AccessPoints[0].Server.LogicalDevices
+	if len(lds) != 1 || lds[0].Inst != "simpleIOGenericIO" {
+		t.Fatalf("unexpected logical devices %v\n", lds)
+	}
+
+	ld := lds[0]
+	if len(ld.LogicalNodes) != 3 {
+		t.Errorf("exported %d logical nodes, want 3\n", len(ld.LogicalNodes))
+	}
+	if n := len(ld.LN0.DataSets); n != 2 {
+		t.Errorf("exported %d data sets, want 2\n", n)
+	}
+	if n := len(ld.LN0.ReportControlBlocks); n != 2 {
+		t.Errorf("exported %d report control blocks, want 2\n", n)
+	}
+	if n := len(ld.LN0.GSEControlBlocks); n != 2 {
+		t.Errorf("exported %d GOOSE control blocks, want 2\n", n)
+	}
+	if n := len(ld.LN0.LogControlBlocks); n != 2 {
+		t.Errorf("exported %d log control blocks, want 2\n", n)
+	}
+
+	var anIn1 scl.DataModelNode
+	for _, ln := range ld.LogicalNodes {
+		if ln.GetName() == "GGIO1" {
+			anIn1 = ln.GetChildByName("AnIn1")
+		}
+	}
+	if anIn1 == nil {
+		t.Fatalf("GGIO1.AnIn1 missing\n")
+	}
+	if cdc := anIn1.GetSclType().(*scl.DataObjectType).Cdc; cdc != "MV" {
+		t.Errorf("GGIO1.AnIn1 cdc %q, want MV\n", cdc)
+	}
+}
@@ -47,3 +47,42 @@ func DoRead(t *testing.T, client *iec61850.Client, objectRef string, fc iec61850
 	}
 	t.Logf("read %s value -> %v", objectRef, value)
 }
+
+// NewModel creates the model name with the logical device Device1, whose LLN0 has the Mod data object,
+// and the logical node GGIO1 for the data of the test. The model is destroyed when the test finishes.
+func NewModel(t *testing.T, name string) (model *iec61850.IedModel, lln0 *iec61850.LogicalNode, ggio *iec61850.LogicalNode) {
+	model = iec61850.NewIedModel(name)
+	t.Cleanup(model.Destroy)
+
+	ld := model.CreateLogicalDevice("Device1")
+	lln0 = ld.CreateLogicalNode("LLN0")
+	lln0.CreateDataObjectCDC_ENS("Mod")
+	ggio = ld.CreateLogicalNode("GGIO1")
+	return model, lln0, ggio
+}
+
+// NewServer creates a server of model with the default configuration, destroyed when the test finishes.
+// Handlers are installed on the returned server before StartServer.
+func NewServer(t *testing.T, model *iec61850.IedModel) *iec61850.IedServer {
+	server := iec61850.NewServerWithConfig(iec61850.NewServerConfig(), model)
+	t.Cleanup(server.Destroy)
+	return server
+}
+
+// StartServer starts server on port and stops it when the test finishes.
+func StartServer(t *testing.T, server *iec61850.IedServer, port int) {
+	server.Start(port)
+	t.Cleanup(server.Stop)
+}
+
+// ConnectClient connects a client to the server on port of the local host, closed when the test finishes.
+func ConnectClient(t *testing.T, port int) *iec61850.Client {
+	settings := iec61850.NewSettings()
+	settings.Port = port
+	client, err := iec61850.NewClient(settings)
+	if err != nil {
+		t.Fatalf("create client error %v\n", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
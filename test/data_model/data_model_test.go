@@ -0,0 +1,127 @@
+package data_model
+
+import (
+	"testing"
+
+	"github.com/marrasen/iec61850"
+)
+
+const port = 10103
+
+func findLN(dataModel iec61850.DataModel, ref string) *iec61850.LN {
+	for i := range dataModel.LDs {
+		for j := range dataModel.LDs[i].LNs {
+			if dataModel.LDs[i].LNs[j].Ref == ref {
+				return &dataModel.LDs[i].LNs[j]
+			}
+		}
+	}
+	return nil
+}
+
+func findDO(ln *iec61850.LN, name string) *iec61850.DO {
+	for i := range ln.DOs {
+		if ln.DOs[i].Data == name {
+			return &ln.DOs[i]
+		}
+	}
+	return nil
+}
+
+func TestGetDataModel(t *testing.T) {
+	model, err := iec61850.CreateModelFromConfigFileEx("../tls_server/model.cfg")
+	if err != nil {
+		t.Fatalf("create model error %v\n", err)
+	}
+	defer model.Destroy()
+
+	server := iec61850.NewServerWithConfig(iec61850.NewServerConfig(), model)
+	server.Start(port)
+	defer server.Destroy()
+	defer server.Stop()
+
+	settings := iec61850.NewSettings()
+	settings.Port = port
+	client, err := iec61850.NewClient(settings)
+	if err != nil {
+		t.Fatalf("create client error %v\n", err)
+	}
+	defer client.Close()
+
+	dataModel, err := client.GetDataModelWithOptions(iec61850.DataModelOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatalf("get data model error %v\n", err)
+	}
+	if len(dataModel.LDs) != 1 || len(dataModel.LDs[0].LNs) != 3 {
+		t.Fatalf("unexpected logical devices %v\n", dataModel)
+	}
+	// the order of the server is kept although logical nodes are read concurrently
+	for i, name := range []string{"LLN0", "LPHD1", "GGIO1"} {
+		if got := dataModel.LDs[0].LNs[i].Data; got != name {
+			t.Errorf("logical node %d is %s, want %s\n", i, got, name)
+		}
+	}
+
+	ggio := findLN(dataModel, "simpleIOGenericIO/GGIO1")
+	anIn1 := findDO(ggio, "AnIn1")
+	if anIn1 == nil {
+		t.Fatalf("GGIO1.AnIn1 missing\n")
+	}
+	if anIn1.CDC != "MV" {
+		t.Errorf("GGIO1.AnIn1 cdc %q, want MV\n", anIn1.CDC)
+	}
+	var mag *iec61850.DA
+	for i := range anIn1.DAs {
+		if anIn1.DAs[i].Data == "mag" {
+			mag = &anIn1.DAs[i]
+		}
+	}
+	if mag == nil || len(mag.DAs) != 1 {
+		t.Fatalf("GGIO1.AnIn1.mag missing or without f: %v\n", anIn1)
+	}
+	if f := mag.DAs[0]; f.Ref != "simpleIOGenericIO/GGIO1.AnIn1.mag.f" || f.FC != iec61850.MX ||
+		f.Type != iec61850.Float || f.BType != "FLOAT32" {
+		t.Errorf("unexpected GGIO1.AnIn1.mag.f %v\n", f)
+	}
+	if spcso1 := findDO(ggio, "SPCSO1"); spcso1 == nil || spcso1.CDC != "SPC" {
+		t.Errorf("unexpected GGIO1.SPCSO1 %v\n", spcso1)
+	}
+
+	lln0 := findLN(dataModel, "simpleIOGenericIO/LLN0")
+	if len(lln0.DSs) != 2 || len(lln0.DSs[0].DSRefs) != 4 {
+		t.Fatalf("unexpected data sets %v\n", lln0.DSs)
+	}
+	if ref := lln0.DSs[0].DSRefs[0]; ref.Ref != "simpleIOGenericIO/GGIO1.SPCSO1.stVal" || ref.FC != iec61850.ST {
+		t.Errorf("unexpected data set member %v\n", ref)
+	}
+
+	if len(lln0.URReports) != 2 {
+		t.Fatalf("unexpected unbuffered reports %v\n", lln0.URReports)
+	}
+	rcb := lln0.URReports[0]
+	if rcb.RptID != "Events" || rcb.ConfRev != 1 || rcb.BufTm != 50 || rcb.IntgPd != 1000 ||
+		!rcb.TrgOps.TriggeredPeriodically || !rcb.TrgOps.Gi || rcb.TrgOps.DataChange {
+		t.Errorf("unexpected report control block %v\n", rcb)
+	}
+
+	if len(lln0.LCBs) != 2 {
+		t.Errorf("unexpected log control blocks %v\n", lln0.LCBs)
+	}
+	if len(lln0.Logs) != 2 {
+		t.Errorf("unexpected logs %v\n", lln0.Logs)
+	}
+
+	if len(lln0.GoCBs) != 2 {
+		t.Fatalf("unexpected GOOSE control blocks %v\n", lln0.GoCBs)
+	}
+	gocb := lln0.GoCBs[0]
+	want := iec61850.PhyComAddress{
+		VlanPriority: 4,
+		VlanId:       273,
+		AppId:        4096,
+		DstAddress:   [6]uint8{0x01, 0x0c, 0xcd, 0x01, 0x00, 0x01},
+	}
+	if gocb.GoID != "events" || gocb.ConfRev != 2 || gocb.DstAddress != want {
+		t.Errorf("unexpected GOOSE control block %v\n", gocb)
+	}
+}
@@ -0,0 +1,119 @@
+package dynamic_model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const controlBlocksPort = 10108
+
+func TestCreateControlBlocks(t *testing.T) {
+	model, lln0, ggio := test.NewModel(t, "dyn")
+	ggio.CreateDataObjectCDC_INS("IntIn1")
+	ggio.CreateDataObjectCDC_SPG("SPCSet1")
+
+	ds := lln0.CreateDataSet("Events")
+	ds.AddDataSetEntry("GGIO1$ST$IntIn1$stVal")
+	ds.AddDataSetEntryEx("GGIO1$ST$IntIn1", -1, "q")
+
+	lln0.CreateReportControlBlock(iec61850.ReportControlBlockConfig{
+		Name:    "EventsURCB01",
+		RptID:   "Events",
+		DataSet: "Events",
+		ConfRev: 1,
+		TrgOps:  iec61850.TrgOps{DataChange: true, QualityChange: true, Gi: true},
+		OptFlds: iec61850.OptFlds{SequenceNumber: true, DataSetName: true, ReasonForInclusion: true},
+		IntgPd:  1000,
+	})
+	lln0.CreateReportControlBlock(iec61850.ReportControlBlockConfig{
+		Name:     "EventsBRCB01",
+		Buffered: true,
+		DataSet:  "Events",
+		ConfRev:  1,
+		TrgOps:   iec61850.TrgOps{DataChange: true},
+		BufTm:    100,
+	})
+	lln0.CreateGSEControlBlock(iec61850.GSEControlBlockConfig{
+		Name:    "gcbEvents",
+		AppID:   "events",
+		DataSet: "Events",
+		ConfRev: 1,
+		MinTime: 10,
+		MaxTime: 1000,
+		Address: &iec61850.PhyComAddress{VlanPriority: 4, AppId: 0x1000, DstAddress: [6]uint8{0x01, 0x0c, 0xcd, 0x01, 0x00, 0x01}},
+	})
+	lln0.CreateSVControlBlock(iec61850.SVControlBlockConfig{
+		Name:    "MSVCB01",
+		SvID:    "sv01",
+		DataSet: "Events",
+		ConfRev: 1,
+		SmpRate: 80,
+		OptFlds: iec61850.SVOptFlds{SampleSynchronized: true},
+		Address: &iec61850.PhyComAddress{VlanPriority: 4, AppId: 0x4000, DstAddress: [6]uint8{0x01, 0x0c, 0xcd, 0x04, 0x00, 0x01}},
+	})
+	lln0.CreateLog("EventLog")
+	lln0.CreateLogControlBlock(iec61850.LogControlBlockConfig{
+		Name:    "EventLog",
+		DataSet: "Events",
+		LogRef:  "Device1/LLN0$EventLog",
+		TrgOps:  iec61850.TrgOps{DataChange: true},
+	})
+	lln0.CreateSettingGroupControlBlock(1, 2)
+
+	server := test.NewServer(t, model)
+	test.StartServer(t, server, controlBlocksPort)
+
+	client := test.ConnectClient(t, controlBlocksPort)
+
+	dataModel, err := client.GetDataModel()
+	if err != nil {
+		t.Fatalf("get data model error %v\n", err)
+	}
+	var ln *iec61850.LN
+	for i := range dataModel.LDs[0].LNs {
+		if dataModel.LDs[0].LNs[i].Ref == "dynDevice1/LLN0" {
+			ln = &dataModel.LDs[0].LNs[i]
+		}
+	}
+	if ln == nil {
+		t.Fatalf("LLN0 not found\n")
+	}
+	if len(ln.URReports) != 1 || len(ln.BRReports) != 1 || len(ln.GoCBs) != 1 || len(ln.SVCBs) != 1 ||
+		len(ln.LCBs) != 1 || ln.SGCB == nil {
+		t.Fatalf("unexpected control blocks %+v\n", ln)
+	}
+	if ln.GoCBs[0].DstAddress.AppId != 0x1000 {
+		t.Fatalf("unexpected GoCB address %v\n", ln.GoCBs[0].DstAddress)
+	}
+	if ln.SGCB.NumOfSG != 2 {
+		t.Fatalf("unexpected SGCB %+v\n", ln.SGCB)
+	}
+
+	// the server publishes reports of the created RCB
+	rcbRef := "dynDevice1/LLN0.RP.EventsURCB01"
+	rptIds := make(chan string, 10)
+	if err := client.InstallReportHandler(rcbRef, "Events", func(report iec61850.ClientReport) {
+		rptIds <- report.GetRptId()
+	}); err != nil {
+		t.Fatalf("install report handler error %v\n", err)
+	}
+	if err := client.SetRptEna(rcbRef, true); err != nil {
+		t.Fatalf("enable report error %v\n", err)
+	}
+
+	server.LockDataModel()
+	server.UpdateInt32AttributeValue(model.GetModelNodeByObjectReference("dynDevice1/GGIO1.IntIn1.stVal"), 7)
+	server.UnlockDataModel()
+
+	select {
+	case rptId := <-rptIds:
+		if rptId != "Events" {
+			t.Fatalf("unexpected report %s\n", rptId)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("no report received\n")
+	}
+}
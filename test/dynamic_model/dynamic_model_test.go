@@ -0,0 +1,70 @@
+package dynamic_model
+
+import (
+	"testing"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10107
+
+func TestCreateCDCs(t *testing.T) {
+	model, lln0, ggio := test.NewModel(t, "dyn")
+	lln0.CreateDataObjectCDC_LPL("NamPlt", iec61850.CDC_OPTION_AC_LN0_M)
+
+	ggio.CreateDataObjectCDC_SPS("Ind1", iec61850.CDC_OPTION_DESC)
+	ggio.CreateDataObjectCDC_MV("AnIn1", false, iec61850.CDC_OPTION_UNIT)
+	ggio.CreateDataObjectCDC_SPC("SPCSO1", iec61850.CONTROL_MODEL_DIRECT_NORMAL, iec61850.CDC_CTL_OPTION_ORIGIN)
+	ggio.CreateDataObjectCDC_WYE("PhV", iec61850.CDC_OPTION_PHASES_ABC)
+	ggio.CreateDataObjectCDC_APC("APCSO1", iec61850.CONTROL_MODEL_SBO_NORMAL, iec61850.CDC_CTL_OPTION_ORIGIN, false)
+	ggio.CreateDataObjectCDC_ALM("Alm1", iec61850.CONTROL_MODEL_STATUS_ONLY, 0, iec61850.CDC_OPTION_61400_TM_TOT, true)
+
+	// custom data object with explicit attribute types
+	custom := ggio.CreateDataObject("Custom", 0)
+	custom.CreateDataAttribute("stVal", iec61850.DA_TYPE_INT32, iec61850.ST, iec61850.TrgOps{DataChange: true}, 0, 0).SetValue(42)
+	custom.CreateDataAttribute("q", iec61850.DA_TYPE_QUALITY, iec61850.ST, iec61850.TrgOps{QualityChange: true}, 0, 0)
+	custom.CreateDataAttribute("t", iec61850.DA_TYPE_TIMESTAMP, iec61850.ST, iec61850.TrgOps{}, 0, 0)
+	if err := custom.CreateDataAttribute("d", iec61850.DA_TYPE_VISIBLE_STRING_255, iec61850.DC, iec61850.TrgOps{}, 0, 0).SetValue("custom"); err != nil {
+		t.Fatalf("set value error %v\n", err)
+	}
+
+	server := test.NewServer(t, model)
+	test.StartServer(t, server, port)
+
+	client := test.ConnectClient(t, port)
+
+	for _, read := range []struct {
+		ref string
+		fc  iec61850.FC
+	}{
+		{"dynDevice1/LLN0.NamPlt.configRev", iec61850.DC},
+		{"dynDevice1/GGIO1.Ind1.stVal", iec61850.ST},
+		{"dynDevice1/GGIO1.Ind1.d", iec61850.DC},
+		{"dynDevice1/GGIO1.AnIn1.mag.f", iec61850.MX},
+		{"dynDevice1/GGIO1.AnIn1.units", iec61850.CF},
+		{"dynDevice1/GGIO1.SPCSO1.ctlModel", iec61850.CF},
+		{"dynDevice1/GGIO1.SPCSO1.origin", iec61850.ST},
+		{"dynDevice1/GGIO1.PhV.phsA", iec61850.MX},
+		{"dynDevice1/GGIO1.APCSO1.ctlModel", iec61850.CF},
+	} {
+		if _, err := client.ReadObject(read.ref, read.fc); err != nil {
+			t.Fatalf("read %s error %v\n", read.ref, err)
+		}
+	}
+
+	value, err := client.ReadObject("dynDevice1/GGIO1.Custom.stVal", iec61850.ST)
+	if err != nil {
+		t.Fatalf("read custom error %v\n", err)
+	}
+	if value.Value != int64(42) {
+		t.Fatalf("expected 42, got %v\n", value.Value)
+	}
+	value, err = client.ReadObject("dynDevice1/GGIO1.SPCSO1.ctlModel", iec61850.CF)
+	if err != nil {
+		t.Fatalf("read ctlModel error %v\n", err)
+	}
+	if value.Value != int64(iec61850.CONTROL_MODEL_DIRECT_NORMAL) {
+		t.Fatalf("expected ctlModel %d, got %v\n", iec61850.CONTROL_MODEL_DIRECT_NORMAL, value.Value)
+	}
+}
@@ -0,0 +1,65 @@
+package dynamic_model
+
+import (
+	"testing"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+func TestModelNodeNavigation(t *testing.T) {
+	model, _, ggio := test.NewModel(t, "dyn")
+	ggio.CreateDataObjectCDC_SPS("Ind1")
+	ggio.CreateDataObjectCDC_MV("AnIn1", false)
+	ggio.CreateDataObjectCDC_MV("AnIn2", true)
+	op := ggio.CreateDataObject("Op", 0)
+	op.CreateDataAttribute("general", iec61850.DA_TYPE_BOOLEAN, iec61850.ST, iec61850.TrgOps{DataChange: true, Transient: true}, 0, 0)
+
+	devices := model.GetLogicalDevices()
+	if len(devices) != 1 || devices[0].GetName() != "dynDevice1" || devices[0].GetParent() != nil {
+		t.Fatalf("unexpected logical devices %v\n", devices)
+	}
+	lns := devices[0].GetChildren()
+	if len(lns) != 2 || lns[1].GetObjectReference() != "dynDevice1/GGIO1" || lns[1].GetType() != iec61850.MODEL_NODE_LOGICAL_NODE {
+		t.Fatalf("unexpected logical nodes %v\n", lns)
+	}
+
+	// find all measured values by their mag attribute
+	var measurements []string
+	model.Walk(func(node *iec61850.ModelNode) bool {
+		if node.GetType() != iec61850.MODEL_NODE_DATA_OBJECT {
+			return true
+		}
+		if node.GetChild("mag") != nil {
+			measurements = append(measurements, node.ObjectReference)
+		}
+		return false
+	})
+	if len(measurements) != 2 || measurements[0] != "dynDevice1/GGIO1.AnIn1" || measurements[1] != "dynDevice1/GGIO1.AnIn2" {
+		t.Fatalf("unexpected measurements %v\n", measurements)
+	}
+
+	stVal := model.GetModelNodeByObjectReference("dynDevice1/GGIO1.Ind1.stVal")
+	if stVal.GetType() != iec61850.MODEL_NODE_DATA_ATTRIBUTE || stVal.GetFC() != iec61850.ST ||
+		stVal.GetDataAttributeType() != iec61850.DA_TYPE_BOOLEAN || !stVal.GetTrgOps().DataChange {
+		t.Fatalf("unexpected stVal %s %d %d %+v\n", stVal.GetType(), stVal.GetFC(), stVal.GetDataAttributeType(), stVal.GetTrgOps())
+	}
+	if trgOps := stVal.GetTrgOps(); trgOps.Transient {
+		t.Fatalf("stVal is not transient %+v\n", trgOps)
+	}
+	general := model.GetModelNodeByObjectReference("dynDevice1/GGIO1.Op.general")
+	if trgOps := general.GetTrgOps(); trgOps != (iec61850.TrgOps{DataChange: true, Transient: true}) {
+		t.Fatalf("unexpected trigger options of transient attribute %+v\n", trgOps)
+	}
+	if parent := stVal.GetParent(); parent.GetObjectReference() != "dynDevice1/GGIO1.Ind1" || parent.GetFC() != iec61850.NONE {
+		t.Fatalf("unexpected parent %s\n", parent.ObjectReference)
+	}
+
+	mag := model.GetModelNodeByObjectReference("dynDevice1/GGIO1.AnIn2").GetChildWithFC("mag", iec61850.MX)
+	if mag == nil || mag.GetChild("i").GetDataAttributeType() != iec61850.DA_TYPE_INT32 {
+		t.Fatalf("unexpected mag %v\n", mag)
+	}
+	if ggio.ModelNode().GetChild("AnIn1$mag$f").ObjectReference != "dynDevice1/GGIO1.AnIn1.mag.f" {
+		t.Fatalf("unexpected child reference\n")
+	}
+}
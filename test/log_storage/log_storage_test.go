@@ -0,0 +1,204 @@
+package log_storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10113
+
+func TestLogStorage(t *testing.T) {
+	model, lln0, ggio := test.NewModel(t, "log")
+	ggio.CreateDataObjectCDC_INS("IntIn1")
+
+	ds := lln0.CreateDataSet("Events")
+	ds.AddDataSetEntry("GGIO1$ST$IntIn1$stVal")
+	lln0.CreateLog("EventLog")
+	lln0.CreateLogControlBlock(iec61850.LogControlBlockConfig{
+		Name:    "EventLog",
+		DataSet: "Events",
+		LogRef:  "Device1/LLN0$EventLog",
+		TrgOps:  iec61850.TrgOps{DataChange: true},
+		LogEna:  true,
+	})
+
+	server := test.NewServer(t, model)
+	storage := iec61850.NewMemoryLogStorage(10)
+	server.SetLogStorage("Device1/LLN0$EventLog", storage)
+	test.StartServer(t, server, port)
+
+	stVal := model.GetModelNodeByObjectReference("logDevice1/GGIO1.IntIn1.stVal")
+	server.LockDataModel()
+	server.UpdateInt32AttributeValue(stVal, 5)
+	server.UnlockDataModel()
+
+	var entries []iec61850.LogEntry
+	for deadline := time.Now().Add(time.Second); len(entries) == 0 && time.Now().Before(deadline); {
+		time.Sleep(50 * time.Millisecond)
+		entries = nil
+		if err := storage.GetEntries(0, 0, func(entry iec61850.LogEntry) bool {
+			entries = append(entries, entry)
+			return true
+		}); err != nil {
+			t.Fatalf("get entries error %v\n", err)
+		}
+	}
+	if len(entries) == 0 {
+		t.Fatalf("no log entry\n")
+	}
+	last := entries[len(entries)-1]
+	if len(last.Data) != 1 || last.Data[0].DataRef == "" {
+		t.Fatalf("unexpected entry %+v\n", last)
+	}
+	value, err := last.Data[0].Value()
+	if err != nil {
+		t.Fatalf("decode value error %v\n", err)
+	}
+	if value.Value != int64(5) {
+		t.Fatalf("unexpected value %v\n", value.Value)
+	}
+}
+
+func TestMemoryLogStorageRing(t *testing.T) {
+	storage := iec61850.NewMemoryLogStorage(3)
+	for i := uint64(1); i <= 5; i++ {
+		entryID, err := storage.AddEntry(i * 1000)
+		if err != nil || entryID != i {
+			t.Fatalf("add entry %d error %v\n", entryID, err)
+		}
+	}
+	oldest, newest, ok := storage.GetOldestAndNewestEntries()
+	if !ok || oldest.EntryID != 3 || newest.EntryID != 5 || newest.Timestamp != 5000 {
+		t.Fatalf("unexpected oldest %+v newest %+v\n", oldest, newest)
+	}
+	if err := storage.AddEntryData(1, iec61850.LogEntryData{DataRef: "x"}); err == nil {
+		t.Fatalf("data added to dropped entry\n")
+	}
+
+	var ids []uint64
+	storage.GetEntriesAfter(0, 3, func(entry iec61850.LogEntry) bool {
+		ids = append(ids, entry.EntryID)
+		return true
+	})
+	if len(ids) != 2 || ids[0] != 4 || ids[1] != 5 {
+		t.Fatalf("unexpected entries after 3 %v\n", ids)
+	}
+	ids = nil
+	storage.GetEntriesAfter(5000, 3, func(entry iec61850.LogEntry) bool {
+		ids = append(ids, entry.EntryID)
+		return true
+	})
+	if len(ids) != 1 || ids[0] != 5 {
+		t.Fatalf("unexpected entries after 3 from 5000 %v\n", ids)
+	}
+	ids = nil
+	storage.GetEntries(3500, 4500, func(entry iec61850.LogEntry) bool {
+		ids = append(ids, entry.EntryID)
+		return true
+	})
+	if len(ids) != 1 || ids[0] != 4 {
+		t.Fatalf("unexpected entries by time %v\n", ids)
+	}
+}
+
+func TestFileLogStorage(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "events.log")
+	storage, err := iec61850.NewFileLogStorage(name, 4)
+	if err != nil {
+		t.Fatalf("open error %v\n", err)
+	}
+	for i := uint64(1); i <= 10; i++ {
+		entryID, err := storage.AddEntry(i * 1000)
+		if err != nil {
+			t.Fatalf("add entry error %v\n", err)
+		}
+		if err := storage.AddEntryData(entryID, iec61850.LogEntryData{DataRef: "GGIO1$ST$IntIn1$stVal", Data: []byte{0x85, 0x01, byte(i)}, ReasonCode: 2}); err != nil {
+			t.Fatalf("add entry data error %v\n", err)
+		}
+	}
+	if err := storage.Close(); err != nil {
+		t.Fatalf("close error %v\n", err)
+	}
+
+	storage, err = iec61850.NewFileLogStorage(name, 4)
+	if err != nil {
+		t.Fatalf("reopen error %v\n", err)
+	}
+	defer storage.Close()
+	var entries []iec61850.LogEntry
+	storage.GetEntries(0, 0, func(entry iec61850.LogEntry) bool {
+		entries = append(entries, entry)
+		return true
+	})
+	if len(entries) != 4 || entries[0].EntryID != 7 || entries[3].EntryID != 10 {
+		t.Fatalf("unexpected entries %+v\n", entries)
+	}
+	data := entries[3].Data
+	if len(data) != 1 || data[0].ReasonCode != 2 || data[0].Data[2] != 10 {
+		t.Fatalf("unexpected data %+v\n", data)
+	}
+	var ids []uint64
+	storage.GetEntriesAfter(9000, 7, func(entry iec61850.LogEntry) bool {
+		ids = append(ids, entry.EntryID)
+		return true
+	})
+	if len(ids) != 2 || ids[0] != 9 || ids[1] != 10 {
+		t.Fatalf("unexpected entries after 7 from 9000 %v\n", ids)
+	}
+	if entryID, _ := storage.AddEntry(11000); entryID != 11 {
+		t.Fatalf("unexpected entry ID %d after reopen\n", entryID)
+	}
+}
+
+func TestFileLogStorageRecovery(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "events.log")
+	storage, err := iec61850.NewFileLogStorage(name, 4)
+	if err != nil {
+		t.Fatalf("open error %v\n", err)
+	}
+	if _, err := storage.AddEntry(1000); err != nil {
+		t.Fatalf("add entry error %v\n", err)
+	}
+	storage.Close()
+	info, err := os.Stat(name)
+	if err != nil {
+		t.Fatalf("stat error %v\n", err)
+	}
+
+	// an entry record cut off while writing is removed
+	appendFile(t, name, []byte{'E', 0, 0, 0})
+	storage, err = iec61850.NewFileLogStorage(name, 4)
+	if err != nil {
+		t.Fatalf("open with incomplete record error %v\n", err)
+	}
+	storage.Close()
+	if truncated, _ := os.Stat(name); truncated.Size() != info.Size() {
+		t.Fatalf("incomplete record not removed, size %d, expected %d\n", truncated.Size(), info.Size())
+	}
+
+	// unknown records are not dropped
+	appendFile(t, name, append([]byte{'X'}, make([]byte, 20)...))
+	if _, err := iec61850.NewFileLogStorage(name, 4); !errors.Is(err, iec61850.CorruptLogFile) {
+		t.Fatalf("open with unknown record error %v\n", err)
+	}
+	if corrupt, _ := os.Stat(name); corrupt.Size() != info.Size()+21 {
+		t.Fatalf("corrupt file changed, size %d\n", corrupt.Size())
+	}
+}
+
+func appendFile(t *testing.T, name string, data []byte) {
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("open error %v\n", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		t.Fatalf("write error %v\n", err)
+	}
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marrasen/iec61850"
+)
+
+const port = 10105
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of the client.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLoggerAndProgress(t *testing.T) {
+	model, err := iec61850.CreateModelFromConfigFileEx("../tls_server/model.cfg")
+	if err != nil {
+		t.Fatalf("create model error %v\n", err)
+	}
+	defer model.Destroy()
+
+	server := iec61850.NewServerWithConfig(iec61850.NewServerConfig(), model)
+	server.Start(port)
+	defer server.Destroy()
+	defer server.Stop()
+
+	logs := &syncBuffer{}
+	var progress []iec61850.Progress
+
+	settings := iec61850.NewSettings()
+	settings.Port = port
+	settings.Logger = slog.New(slog.NewJSONHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	settings.Progress = func(p iec61850.Progress) {
+		progress = append(progress, p)
+	}
+	client, err := iec61850.NewClient(settings)
+	if err != nil {
+		t.Fatalf("create client error %v\n", err)
+	}
+	defer client.Close()
+
+	if _, err := client.GetDataModel(); err != nil {
+		t.Fatalf("get data model error %v\n", err)
+	}
+	if len(progress) != 3 {
+		t.Fatalf("unexpected progress %v\n", progress)
+	}
+	for i, p := range progress {
+		if p.Operation != "GetDataModel" || p.Done != i+1 || p.Total != 3 {
+			t.Errorf("unexpected progress %v\n", p)
+		}
+	}
+
+	done := make(chan error, 1)
+	_, err = client.GetVariableSpecificationAsync("simpleIOGenericIO/GGIO1.Missing", iec61850.MX, func(invokeID uint32, spec *iec61850.MmsVariableSpec, err error) {
+		done <- err
+	})
+	if err != nil {
+		t.Fatalf("get variable specification error %v\n", err)
+	}
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatalf("missing variable specification returned no error\n")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no response to variable specification request\n")
+	}
+
+	output := logs.String()
+	if !strings.Contains(output, `"invokeID"`) || !strings.Contains(output, `"ref":"simpleIOGenericIO/GGIO1.Missing"`) {
+		t.Errorf("structured attributes missing in log output %s\n", output)
+	}
+}
@@ -0,0 +1,69 @@
+package scl_model
+
+import (
+	"testing"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/scl"
+)
+
+const port = 10106
+
+func TestBuildIedModel(t *testing.T) {
+	sclFile, err := scl.NewParser("simpleIO_control_tests.cid").Parse()
+	if err != nil {
+		t.Fatalf("parse scl error %v\n", err)
+	}
+	model, err := scl.BuildIedModel(sclFile, "", "")
+	if err != nil {
+		t.Fatalf("build model error %v\n", err)
+	}
+	defer model.Destroy()
+
+	server := iec61850.NewServerWithConfig(iec61850.NewServerConfig(), model)
+	server.Start(port)
+	defer server.Destroy()
+	defer server.Stop()
+
+	settings := iec61850.NewSettings()
+	settings.Port = port
+	client, err := iec61850.NewClient(settings)
+	if err != nil {
+		t.Fatalf("create client error %v\n", err)
+	}
+	defer client.Close()
+
+	// initial values of the DOI elements
+	value, err := client.ReadObject("simpleIOGenericIO/GGIO1.SPCSO2.ctlModel", iec61850.CF)
+	if err != nil {
+		t.Fatalf("read ctlModel error %v\n", err)
+	}
+	if value.Value != int64(iec61850.CONTROL_MODEL_SBO_NORMAL) {
+		t.Fatalf("expected ctlModel %d, got %v\n", iec61850.CONTROL_MODEL_SBO_NORMAL, value.Value)
+	}
+	value, err = client.ReadObject("simpleIOGenericIO/GGIO1.SPCSO2.sboTimeout", iec61850.CF)
+	if err != nil {
+		t.Fatalf("read sboTimeout error %v\n", err)
+	}
+	if value.Value != uint32(2000) {
+		t.Fatalf("expected sboTimeout 2000, got %v\n", value.Value)
+	}
+
+	// data set and indexed report control blocks
+	values, err := client.ReadDataSetValues("simpleIOGenericIO/LLN0.ControlEvents")
+	if err != nil {
+		t.Fatalf("read data set error %v\n", err)
+	}
+	if len(values) != 12 {
+		t.Fatalf("expected 12 data set members, got %d\n", len(values))
+	}
+	for _, name := range []string{"ControlEventsRCB01", "ControlEventsRCB02"} {
+		rcb, err := client.GetRCBValues("simpleIOGenericIO/LLN0.RP." + name)
+		if err != nil {
+			t.Fatalf("read %s error %v\n", name, err)
+		}
+		if rcb.RptId != "ControlEvents" || rcb.IntgPd != 1000 || !rcb.TrgOps.DataChange {
+			t.Fatalf("unexpected %s %+v\n", name, rcb)
+		}
+	}
+}
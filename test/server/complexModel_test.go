@@ -18,8 +18,8 @@ func TestCreateServerFromConfigFile2(t *testing.T) {
 	server := iec61850.NewServerWithConfig(iec61850.NewServerConfig(), model)
 
 	modelNode := model.GetModelNodeByObjectReference("ied1Inverter/ZINV1.OutVarSet.setMag.f")
-	server.SetHandleWriteAccess(modelNode, func(node *iec61850.ModelNode, mmsValue *iec61850.MmsValue) iec61850.MmsDataAccessError {
-		t.Logf("handle write access, value %#v\n", mmsValue)
+	server.SetHandleWriteAccess(modelNode, func(node *iec61850.ModelNode, mmsValue *iec61850.MmsValue, connection *iec61850.ClientConnection) iec61850.MmsDataAccessError {
+		t.Logf("handle write access from %s, value %#v\n", connection.PeerAddress, mmsValue)
 		return iec61850.DATA_ACCESS_ERROR_SUCCESS
 	})
 
@@ -0,0 +1,53 @@
+package server_access
+
+import (
+	"testing"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10117
+
+func TestReadAccessHandler(t *testing.T) {
+	model, _, ggio := test.NewModel(t, "acc")
+	ggio.CreateDataObjectCDC_INS("Cnt1")
+	ggio.CreateDataObjectCDC_SPS("Ind1")
+
+	server := test.NewServer(t, model)
+
+	cnt := model.GetModelNodeByObjectReference("accDevice1/GGIO1.Cnt1.stVal")
+	reads := 0
+	server.SetReadAccessHandler(func(node *iec61850.ModelNode, fc iec61850.FC, connection *iec61850.ClientConnection) iec61850.MmsDataAccessError {
+		if connection == nil {
+			t.Errorf("read without connection\n")
+		}
+		switch node.ObjectReference {
+		case "accDevice1/GGIO1.Ind1":
+			return iec61850.DATA_ACCESS_ERROR_TEMPORARILY_UNAVAILABLE
+		case "accDevice1/GGIO1.Cnt1":
+			// computed on read, the data model is locked by the server
+			reads++
+			server.UpdateInt64AttributeValue(cnt, int64(reads))
+		}
+		return iec61850.DATA_ACCESS_ERROR_SUCCESS
+	})
+
+	test.StartServer(t, server, port)
+
+	client := test.ConnectClient(t, port)
+
+	for i := int32(1); i <= 2; i++ {
+		value, err := client.ReadInt32Value("accDevice1/GGIO1.Cnt1.stVal", iec61850.ST)
+		if err != nil {
+			t.Fatalf("read error %v\n", err)
+		}
+		if value != i {
+			t.Fatalf("read %d, expected %d\n", value, i)
+		}
+	}
+
+	if _, err := client.ReadBoolValue("accDevice1/GGIO1.Ind1.stVal", iec61850.ST); err == nil {
+		t.Fatalf("denied read succeeded\n")
+	}
+}
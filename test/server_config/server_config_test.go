@@ -0,0 +1,44 @@
+package server_config
+
+import (
+	"testing"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10119
+
+func TestReportSettings(t *testing.T) {
+	model, lln0, ggio := test.NewModel(t, "cfg")
+	ggio.CreateDataObjectCDC_SPS("Ind1")
+	ds := lln0.CreateDataSet("Events")
+	ds.AddDataSetEntry("GGIO1$ST$Ind1$stVal")
+	lln0.CreateReportControlBlock(iec61850.ReportControlBlockConfig{
+		Name:    "EventsRCB01",
+		DataSet: "Events",
+		ConfRev: 1,
+		IntgPd:  1000,
+	})
+
+	config := iec61850.NewServerConfig()
+	config.SetReportSetting(iec61850.REPORT_SETTING_RPT_ID, false)
+	config.ReportSettings.ConfIntgPd = true
+
+	server := iec61850.NewServerWithConfig(config, model)
+	t.Cleanup(server.Destroy)
+	test.StartServer(t, server, port)
+
+	client := test.ConnectClient(t, port)
+
+	const rcbRef = "cfgDevice1/LLN0.EventsRCB01"
+	if err := client.WriteObject(rcbRef+".RptID", iec61850.RP, "events"); err == nil {
+		t.Fatalf("RptID written with Conf setting\n")
+	}
+	if err := client.WriteObject(rcbRef+".IntgPd", iec61850.RP, uint32(2000)); err == nil {
+		t.Fatalf("IntgPd written with Conf setting\n")
+	}
+	if err := client.WriteObject(rcbRef+".BufTm", iec61850.RP, uint32(100)); err != nil {
+		t.Fatalf("BufTm not written with Dyn setting: %v\n", err)
+	}
+}
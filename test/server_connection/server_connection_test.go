@@ -0,0 +1,64 @@
+package server_connection
+
+import (
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10111
+
+func TestClientConnectionInHandlers(t *testing.T) {
+	model, _, ggio := test.NewModel(t, "con")
+	ggio.CreateDataObjectCDC_SPG("SPCSet1")
+
+	server := test.NewServer(t, model)
+
+	role := "operator"
+	server.SetAuthenticator(func(securityToken *unsafe.Pointer, authParameter *iec61850.AcseAuthenticationParameter, appReference *iec61850.IsoApplicationReference) bool {
+		*securityToken = unsafe.Pointer(&role)
+		return true
+	})
+	connections := make(chan *iec61850.ClientConnection, 10)
+	server.SetConnectionIndicationHandler(func(s *iec61850.IedServer, connection *iec61850.ClientConnection, connected bool) {
+		connections <- connection
+	})
+	writers := make(chan *iec61850.ClientConnection, 10)
+	server.SetHandleWriteAccess(model.GetModelNodeByObjectReference("conDevice1/GGIO1.SPCSet1.setVal"),
+		func(node *iec61850.ModelNode, mmsValue *iec61850.MmsValue, connection *iec61850.ClientConnection) iec61850.MmsDataAccessError {
+			writers <- connection
+			return iec61850.DATA_ACCESS_ERROR_SUCCESS
+		})
+
+	test.StartServer(t, server, port)
+
+	client := test.ConnectClient(t, port)
+
+	var opened *iec61850.ClientConnection
+	select {
+	case opened = <-connections:
+	case <-time.After(time.Second):
+		t.Fatalf("connection not indicated\n")
+	}
+	if opened.ID == 0 || opened.PeerAddress == "" || opened.LocalAddress == "" {
+		t.Fatalf("unexpected connection %+v\n", opened)
+	}
+
+	if err := client.WriteObject("conDevice1/GGIO1.SPCSet1.setVal", iec61850.SP, true); err != nil {
+		t.Fatalf("write error %v\n", err)
+	}
+	select {
+	case writer := <-writers:
+		if writer.ID != opened.ID || writer.PeerAddress != opened.PeerAddress {
+			t.Fatalf("write from %+v, expected %+v\n", writer, opened)
+		}
+		if writer.SecurityToken == nil || *(*string)(writer.SecurityToken) != "operator" {
+			t.Fatalf("unexpected security token\n")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("write handler not called\n")
+	}
+}
@@ -0,0 +1,106 @@
+package server_control
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10110
+
+func TestControlHandlers(t *testing.T) {
+	model, _, ggio := test.NewModel(t, "ctl")
+	ggio.CreateDataObjectCDC_SPC("SPCSO1", iec61850.CONTROL_MODEL_SBO_NORMAL, 0)
+	ggio.CreateDataObjectCDC_SPC("SPCSO2", iec61850.CONTROL_MODEL_DIRECT_ENHANCED, 0)
+
+	server := test.NewServer(t, model)
+
+	spcso1 := model.GetModelNodeByObjectReference("ctlDevice1/GGIO1.SPCSO1")
+	selectStates := make(chan iec61850.SelectStateChangedReason, 10)
+	server.SetSelectStateChangedHandler(spcso1, func(node *iec61850.ModelNode, action *iec61850.ControlAction, isSelected bool, reason iec61850.SelectStateChangedReason) {
+		selectStates <- reason
+	})
+	server.SetPerformCheckHandler(spcso1, func(node *iec61850.ModelNode, action *iec61850.ControlAction, mmsValue *iec61850.MmsValue, test bool, interlockCheck bool) iec61850.CheckHandlerResult {
+		if interlockCheck {
+			action.SetAddCause(iec61850.ADD_CAUSE_BLOCKED_BY_INTERLOCKING)
+			return iec61850.CONTROL_OBJECT_ACCESS_DENIED
+		}
+		return iec61850.CONTROL_ACCEPTED
+	})
+	server.SetControlHandler(spcso1, func(node *iec61850.ModelNode, action *iec61850.ControlAction, mmsValue *iec61850.MmsValue, test bool) iec61850.ControlHandlerResult {
+		return iec61850.CONTROL_RESULT_OK
+	})
+
+	spcso2 := model.GetModelNodeByObjectReference("ctlDevice1/GGIO1.SPCSO2")
+	synchroChecks := make(chan bool, 10)
+	server.SetWaitForExecutionHandler(spcso2, func(node *iec61850.ModelNode, action *iec61850.ControlAction, mmsValue *iec61850.MmsValue, test bool, synchroCheck bool) iec61850.ControlHandlerResult {
+		synchroChecks <- synchroCheck
+		return iec61850.CONTROL_RESULT_OK
+	})
+	actions := make(chan *iec61850.ControlAction, 10)
+	server.SetControlHandler(spcso2, func(node *iec61850.ModelNode, action *iec61850.ControlAction, mmsValue *iec61850.MmsValue, test bool) iec61850.ControlHandlerResult {
+		actions <- action
+		// the operation finishes in the background
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			action.Complete(true, iec61850.ADD_CAUSE_UNKNOWN)
+		}()
+		return iec61850.CONTROL_RESULT_WAITING
+	})
+
+	test.StartServer(t, server, port)
+
+	client := test.ConnectClient(t, port)
+
+	if err := client.ControlForSboWithNormalSecurity("ctlDevice1/GGIO1.SPCSO1", true); err != nil {
+		t.Fatalf("control SPCSO1 error %v\n", err)
+	}
+	for _, expected := range []iec61850.SelectStateChangedReason{iec61850.SELECT_STATE_REASON_SELECTED, iec61850.SELECT_STATE_REASON_OPERATED} {
+		select {
+		case reason := <-selectStates:
+			if reason != expected {
+				t.Fatalf("expected select state %d, got %d\n", expected, reason)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("select state %d not reported\n", expected)
+		}
+	}
+
+	// the perform check handler blocks controls with interlock check
+	param := iec61850.NewControlObjectParam(false)
+	param.Check = true
+	if err := client.ControlByControlModel("ctlDevice1/GGIO1.SPCSO1", iec61850.CONTROL_MODEL_SBO_NORMAL, param); err == nil {
+		t.Fatalf("control with interlock check not rejected\n")
+	}
+
+	param = iec61850.NewControlObjectParam(true)
+	param.OrIdent = "tester"
+	param.Check = true
+	if err := client.ControlByControlModel("ctlDevice1/GGIO1.SPCSO2", iec61850.CONTROL_MODEL_DIRECT_ENHANCED, param); err != nil {
+		t.Fatalf("control SPCSO2 error %v\n", err)
+	}
+	select {
+	case synchroCheck := <-synchroChecks:
+		if !synchroCheck {
+			t.Fatalf("synchro check not requested\n")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("wait for execution handler not called\n")
+	}
+	select {
+	case action := <-actions:
+		if string(action.OrIdent) != "tester" || action.ClientConnection == nil || action.ClientConnection.PeerAddress == "" {
+			t.Fatalf("unexpected control action %+v\n", action)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("control handler not called\n")
+	}
+
+	// the handler is called only once while the operation is in progress
+	time.Sleep(500 * time.Millisecond)
+	if len(actions) != 0 {
+		t.Fatalf("control handler called again\n")
+	}
+}
@@ -0,0 +1,70 @@
+package server_file
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10118
+
+func TestVirtualFilestore(t *testing.T) {
+	model := iec61850.NewIedModel("file")
+	t.Cleanup(model.Destroy)
+
+	ld := model.CreateLogicalDevice("Device1")
+	lln0 := ld.CreateLogicalNode("LLN0")
+	lln0.CreateDataObjectCDC_ENS("Mod")
+
+	record := []byte("rec,1,1999\n")
+	server := test.NewServer(t, model)
+	err := server.SetVirtualFilestore(iec61850.VirtualFilestore{
+		FS: fstest.MapFS{
+			"COMTRADE/rec.cfg": {Data: record},
+			"secret.txt":       {Data: []byte("secret")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set virtual filestore error %v\n", err)
+	}
+
+	services := make(chan iec61850.FileService, 10)
+	server.SetFileAccessHandler(func(connection *iec61850.ClientConnection, service iec61850.FileService, name string, otherName string) iec61850.MmsError {
+		services <- service
+		if name == "secret.txt" {
+			return iec61850.MMS_ERROR_FILE_FILE_ACCESS_DENIED
+		}
+		return iec61850.MMS_ERROR_NONE
+	})
+
+	test.StartServer(t, server, port)
+
+	client := test.ConnectClient(t, port)
+
+	entries, err := client.GetFileDirectory("COMTRADE/")
+	if err != nil {
+		t.Fatalf("get file directory error %v\n", err)
+	}
+	if len(entries) != 1 || entries[0].Size != len(record) {
+		t.Fatalf("unexpected entries %+v\n", entries)
+	}
+	if service := <-services; service != iec61850.FILE_SERVICE_READ_DIRECTORY {
+		t.Fatalf("unexpected service %v\n", service)
+	}
+
+	var buffer bytes.Buffer
+	if err := client.GetFile(&buffer, "COMTRADE/rec.cfg"); err != nil {
+		t.Fatalf("get file error %v\n", err)
+	}
+	if !bytes.Equal(buffer.Bytes(), record) {
+		t.Fatalf("unexpected file %q\n", buffer.String())
+	}
+
+	buffer.Reset()
+	if err := client.GetFile(&buffer, "secret.txt"); err == nil {
+		t.Fatalf("denied file read\n")
+	}
+}
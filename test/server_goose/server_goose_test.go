@@ -0,0 +1,76 @@
+package server_goose
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10115
+
+func TestGoCBManagement(t *testing.T) {
+	model, lln0, ggio := test.NewModel(t, "goo")
+	ggio.CreateDataObjectCDC_SPS("Ind1")
+
+	ds := lln0.CreateDataSet("Events")
+	ds.AddDataSetEntry("GGIO1$ST$Ind1$stVal")
+	lln0.CreateGSEControlBlock(iec61850.GSEControlBlockConfig{
+		Name:    "gcbEvents",
+		AppID:   "events",
+		DataSet: "Events",
+		ConfRev: 1,
+		MinTime: 10,
+		MaxTime: 1000,
+		Address: &iec61850.PhyComAddress{VlanPriority: 4, AppId: 0x1000, DstAddress: [6]uint8{0x01, 0x0c, 0xcd, 0x01, 0x00, 0x01}},
+	})
+
+	server := test.NewServer(t, model)
+	server.SetGooseInterfaceId("eth0")
+	const gocbRef = "gooDevice1/LLN0.gcbEvents"
+	if err := server.SetGooseInterfaceIdForGoCB(gocbRef, "eth0"); err != nil {
+		t.Fatalf("set GoCB interface error %v\n", err)
+	}
+	if err := server.UseGooseVlanTag("gooDevice1/LLN0.gcbMissing", false); err == nil {
+		t.Fatalf("missing GoCB accepted\n")
+	}
+	events := make(chan *iec61850.GoCBState, 10)
+	server.SetGoCBEventHandler(func(state *iec61850.GoCBState, event iec61850.GoCBEvent) {
+		if event == iec61850.GOCB_EVENT_ENABLE {
+			events <- state
+		}
+	})
+
+	test.StartServer(t, server, port)
+
+	state, err := server.GetGoCBState(gocbRef)
+	if err != nil {
+		t.Fatalf("get GoCB state error %v\n", err)
+	}
+	if state.GoEna || state.DataSet != "gooDevice1/LLN0$Events" || state.ConfRev != 1 || state.MaxTime != 1000 {
+		t.Fatalf("unexpected state %+v\n", state)
+	}
+
+	client := test.ConnectClient(t, port)
+
+	if err := client.WriteObject(gocbRef+".GoEna", iec61850.GO, true); err != nil {
+		t.Fatalf("enable GoCB error %v\n", err)
+	}
+	select {
+	case state := <-events:
+		if state.Ref != gocbRef || !state.GoEna {
+			t.Fatalf("unexpected event state %+v\n", state)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("GoCB event not received\n")
+	}
+
+	server.DisableGoosePublishing()
+	if state, _ := server.GetGoCBState(gocbRef); state.GoEna {
+		t.Fatalf("GoCB still enabled\n")
+	}
+	if goEna, err := client.ReadBoolValue(gocbRef+".GoEna", iec61850.GO); err != nil || goEna {
+		t.Fatalf("unexpected GoEna %v error %v\n", goEna, err)
+	}
+}
@@ -0,0 +1,105 @@
+package server_rbac
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10112
+
+func TestRBAC(t *testing.T) {
+	model, _, ggio := test.NewModel(t, "rbac")
+	ggio.CreateDataObjectCDC_SPG("SPCSet1")
+	ggio.CreateDataObjectCDC_SPG("SPCSet2")
+	ggio.CreateDataObjectCDC_SPC("SPCSO1", iec61850.CONTROL_MODEL_DIRECT_NORMAL, 0)
+
+	server := test.NewServer(t, model)
+
+	written := make(chan bool, 10)
+	server.SetHandleWriteAccess(model.GetModelNodeByObjectReference("rbacDevice1/GGIO1.SPCSet1.setVal"),
+		func(node *iec61850.ModelNode, mmsValue *iec61850.MmsValue, connection *iec61850.ClientConnection) iec61850.MmsDataAccessError {
+			written <- mmsValue.Value.(bool)
+			return iec61850.DATA_ACCESS_ERROR_SUCCESS
+		})
+	operated := make(chan bool, 10)
+	server.SetControlHandler(model.GetModelNodeByObjectReference("rbacDevice1/GGIO1.SPCSO1"),
+		func(node *iec61850.ModelNode, action *iec61850.ControlAction, mmsValue *iec61850.MmsValue, test bool) iec61850.ControlHandlerResult {
+			operated <- true
+			return iec61850.CONTROL_RESULT_OK
+		})
+
+	denials := make(chan iec61850.AccessDenial, 10)
+	server.EnableRBAC(iec61850.RBACConfig{
+		Roles: func(connection *iec61850.ClientConnection) []string {
+			return []string{"operator"}
+		},
+		Rules: map[string][]iec61850.AccessRule{
+			"operator": {
+				{Services: []iec61850.AccessService{iec61850.ACCESS_SERVICE_READ}, References: []string{"rbacDevice1/GGIO1*"}},
+				{Services: []iec61850.AccessService{iec61850.ACCESS_SERVICE_WRITE}, References: []string{"rbacDevice1/GGIO1.SPCSet1.*"}, FCs: []iec61850.FC{iec61850.SP}},
+			},
+			"admin": {
+				{Services: []iec61850.AccessService{iec61850.ACCESS_SERVICE_CONTROL}},
+			},
+		},
+		Audit: func(denial iec61850.AccessDenial) {
+			denials <- denial
+		},
+	})
+
+	test.StartServer(t, server, port)
+
+	client := test.ConnectClient(t, port)
+
+	expectDenial := func(service iec61850.AccessService, reference string) {
+		t.Helper()
+		select {
+		case denial := <-denials:
+			if denial.Service != service || denial.Reference != reference || denial.Connection == nil {
+				t.Fatalf("unexpected denial %+v\n", denial)
+			}
+			if len(denial.Roles) != 1 || denial.Roles[0] != "operator" {
+				t.Fatalf("unexpected roles %v\n", denial.Roles)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s of %s not audited\n", service, reference)
+		}
+	}
+
+	if _, err := client.ReadBoolValue("rbacDevice1/GGIO1.SPCSet1.setVal", iec61850.SP); err != nil {
+		t.Fatalf("read error %v\n", err)
+	}
+	if _, err := client.ReadInt32Value("rbacDevice1/LLN0.Mod.stVal", iec61850.ST); err == nil {
+		t.Fatalf("read of LLN0 not denied\n")
+	}
+	expectDenial(iec61850.ACCESS_SERVICE_READ, "rbacDevice1/LLN0.Mod")
+
+	if err := client.WriteObject("rbacDevice1/GGIO1.SPCSet1.setVal", iec61850.SP, true); err != nil {
+		t.Fatalf("write error %v\n", err)
+	}
+	select {
+	case value := <-written:
+		if !value {
+			t.Fatalf("unexpected value written\n")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("write handler not called\n")
+	}
+	if err := client.WriteObject("rbacDevice1/GGIO1.SPCSet2.setVal", iec61850.SP, true); err == nil {
+		t.Fatalf("write of SPCSet2 not denied\n")
+	}
+	expectDenial(iec61850.ACCESS_SERVICE_WRITE, "rbacDevice1/GGIO1.SPCSet2.setVal")
+
+	if err := client.ControlForDirectWithNormalSecurity("rbacDevice1/GGIO1.SPCSO1", true); err == nil {
+		t.Fatalf("control not denied\n")
+	}
+	expectDenial(iec61850.ACCESS_SERVICE_CONTROL, "rbacDevice1/GGIO1.SPCSO1")
+	select {
+	case <-operated:
+		t.Fatalf("control handler called for denied control\n")
+	default:
+	}
+}
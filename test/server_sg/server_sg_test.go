@@ -0,0 +1,123 @@
+package server_sg
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10114
+
+func TestSettingGroupHandlers(t *testing.T) {
+	model := iec61850.NewIedModel("sg")
+	t.Cleanup(model.Destroy)
+
+	ld := model.CreateLogicalDevice("Device1")
+	lln0 := ld.CreateLogicalNode("LLN0")
+	lln0.CreateDataObjectCDC_ENS("Mod")
+	ptoc := ld.CreateLogicalNode("PTOC1")
+	strVal := ptoc.CreateDataObject("StrVal", 0)
+	strVal.CreateDataAttribute("setVal", iec61850.DA_TYPE_INT32, iec61850.SG, iec61850.TrgOps{DataChange: true}, 0, 0)
+	strVal.CreateDataAttribute("setVal", iec61850.DA_TYPE_INT32, iec61850.SE, iec61850.TrgOps{}, 0, 0)
+	lln0.CreateSettingGroupControlBlock(1, 2)
+
+	server := test.NewServer(t, model)
+	sgcb := model.GetSettingGroupControlBlock("sgDevice1")
+	if sgcb == nil || sgcb.NumOfSGs() != 2 {
+		t.Fatalf("setting group control block not found\n")
+	}
+
+	// the setting groups of the device, the active group is loaded into SG and the edit group into SE
+	const ref = "sgDevice1/PTOC1.StrVal.setVal"
+	var mu sync.Mutex
+	groups := map[uint8]iec61850.SettingValues{
+		1: {ref: {Type: iec61850.Integer, Value: int64(1)}},
+		2: {ref: {Type: iec61850.Integer, Value: int64(2)}},
+	}
+	var events []string
+	server.SetActiveSettingGroupChangedHandler(sgcb, func(sgcb *iec61850.SettingGroupControlBlock, newActSG uint8, connection *iec61850.ClientConnection) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, "act")
+		if err := server.SetSettingValues(sgcb, iec61850.SG, groups[newActSG]); err != nil {
+			t.Errorf("set SG values error %v\n", err)
+		}
+		return connection != nil
+	})
+	server.SetEditSettingGroupChangedHandler(sgcb, func(sgcb *iec61850.SettingGroupControlBlock, newEditSG uint8, connection *iec61850.ClientConnection) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, "edit")
+		if err := server.SetSettingValues(sgcb, iec61850.SE, groups[newEditSG]); err != nil {
+			t.Errorf("set SE values error %v\n", err)
+		}
+		return true
+	})
+	server.SetEditSettingGroupConfirmationHandler(sgcb, func(sgcb *iec61850.SettingGroupControlBlock, editSG uint8) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, "confirm")
+		values, err := server.GetSettingValues(sgcb, iec61850.SE)
+		if err != nil {
+			t.Errorf("get SE values error %v\n", err)
+			return
+		}
+		groups[editSG] = values
+		if editSG == server.GetActiveSettingGroup(sgcb) {
+			if err := server.CopyEditSettingsToActive(sgcb); err != nil {
+				t.Errorf("copy settings error %v\n", err)
+			}
+		}
+	})
+
+	test.StartServer(t, server, port)
+
+	client := test.ConnectClient(t, port)
+
+	if err := client.WriteSG("sgDevice1", "LLN0", ref, iec61850.SE, 2, int32(7)); err != nil {
+		t.Fatalf("write setting group error %v\n", err)
+	}
+
+	mu.Lock()
+	if len(events) != 3 || events[0] != "act" || events[1] != "edit" || events[2] != "confirm" {
+		t.Fatalf("unexpected events %v\n", events)
+	}
+	if v := groups[2][ref].Value; v != int64(7) {
+		t.Fatalf("unexpected stored value %v\n", v)
+	}
+	mu.Unlock()
+	if sg := server.GetActiveSettingGroup(sgcb); sg != 2 {
+		t.Fatalf("unexpected active setting group %d\n", sg)
+	}
+	active := model.GetModelNodeByObjectReference("sgDevice1/PTOC1.StrVal").GetChildWithFC("setVal", iec61850.SG)
+	value, err := server.GetAttributeValue(active)
+	if err != nil || value.Value != int64(7) {
+		t.Fatalf("unexpected active value %v error %v\n", value, err)
+	}
+
+	// the device switches back to group 1 by itself
+	server.LockDataModel()
+	err = server.SetSettingValues(sgcb, iec61850.SG, groups[1])
+	server.UnlockDataModel()
+	if err != nil {
+		t.Fatalf("set SG values error %v\n", err)
+	}
+	if err := server.ChangeActiveSettingGroup(sgcb, 1); err != nil {
+		t.Fatalf("change active setting group error %v\n", err)
+	}
+	sgInfo, err := client.GetSG("sgDevice1/LLN0.SGCB")
+	if err != nil {
+		t.Fatalf("get setting group error %v\n", err)
+	}
+	if sgInfo.ActSG != 1 {
+		t.Fatalf("unexpected setting group %+v\n", sgInfo)
+	}
+	if v, err := client.ReadInt32Value(ref, iec61850.SG); err != nil || v != 1 {
+		t.Fatalf("unexpected SG value %d error %v\n", v, err)
+	}
+	if err := server.ChangeActiveSettingGroup(sgcb, 3); err == nil {
+		t.Fatalf("change to missing setting group accepted\n")
+	}
+}
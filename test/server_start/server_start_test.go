@@ -0,0 +1,81 @@
+package server_start
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/scl"
+	"github.com/marrasen/iec61850/test"
+)
+
+const (
+	port          = 10123
+	sclPort       = 10125
+	secondSclPort = 10126
+)
+
+func readMod(t *testing.T, host string, port int, ref string) {
+	settings := iec61850.NewSettings()
+	settings.Host = host
+	settings.Port = port
+	client, err := iec61850.NewClient(settings)
+	if err != nil {
+		t.Fatalf("connect to %s:%d error %v\n", host, port, err)
+	}
+	defer client.Close()
+	if _, err := client.ReadObject(ref, iec61850.ST); err != nil {
+		t.Fatalf("read from %s:%d error %v\n", host, port, err)
+	}
+}
+
+func TestStartWithOptions(t *testing.T) {
+	model, _, _ := test.NewModel(t, "start")
+	server := test.NewServer(t, model)
+
+	if err := server.StartWithOptions(iec61850.StartOptions{Port: port, LocalAddress: "::1"}); !errors.Is(err, iec61850.IPv6NotSupported) {
+		t.Fatalf("IPv6 address not rejected: %v\n", err)
+	}
+	if err := server.StartWithOptions(iec61850.StartOptions{
+		Port:         port,
+		LocalAddress: "127.0.0.1",
+		AccessPoints: []iec61850.AccessPointAddress{{LocalAddress: "127.0.0.2"}},
+	}); err != nil {
+		t.Fatalf("start error %v\n", err)
+	}
+	t.Cleanup(server.Stop)
+
+	readMod(t, "127.0.0.1", port, "startDevice1/LLN0.Mod.stVal")
+	readMod(t, "127.0.0.2", port, "startDevice1/LLN0.Mod.stVal")
+}
+
+func TestStartServersOfSCL(t *testing.T) {
+	sclFile, err := scl.NewParser("../scl_model/simpleIO_control_tests.cid").Parse()
+	if err != nil {
+		t.Fatalf("parse scl error %v\n", err)
+	}
+	address, err := scl.ServerIPAddress(sclFile, "simpleIO", "accessPoint1")
+	if err != nil || address != "0.0.0.0" {
+		t.Fatalf("unexpected address %q %v\n", address, err)
+	}
+
+	// two instances of the IED, like in a redundant configuration
+	for _, options := range []iec61850.StartOptions{
+		{Port: sclPort, LocalAddress: address},
+		{Port: secondSclPort, LocalAddress: "127.0.0.1"},
+	} {
+		model, err := scl.BuildIedModel(sclFile, "simpleIO", "accessPoint1")
+		if err != nil {
+			t.Fatalf("build model error %v\n", err)
+		}
+		t.Cleanup(model.Destroy)
+		server := test.NewServer(t, model)
+		if err := server.StartWithOptions(options); err != nil {
+			t.Fatalf("start error %v\n", err)
+		}
+		t.Cleanup(server.Stop)
+	}
+
+	readMod(t, "127.0.0.1", sclPort, "simpleIOGenericIO/LLN0.Mod.stVal")
+	readMod(t, "127.0.0.1", secondSclPort, "simpleIOGenericIO/LLN0.Mod.stVal")
+}
@@ -0,0 +1,96 @@
+package server_state
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10120
+
+const (
+	rcbRef     = "stateDevice1/LLN0.EventsRCB01"
+	settingRef = "stateDevice1/GGIO1.Tmms.setVal"
+	statusRef  = "stateDevice1/GGIO1.Ind1.stVal"
+)
+
+// createModel creates the model of the server, each run of the server has its own model.
+func createModel(t *testing.T) *iec61850.IedModel {
+	model, lln0, ggio := test.NewModel(t, "state")
+	ggio.CreateDataObjectCDC_SPS("Ind1")
+	tmms := ggio.CreateDataObject("Tmms", 0)
+	tmms.CreateDataAttribute("setVal", iec61850.DA_TYPE_INT32, iec61850.SP, iec61850.TrgOps{DataChange: true}, 0, 0)
+	ds := lln0.CreateDataSet("Events")
+	ds.AddDataSetEntry("GGIO1$ST$Ind1$stVal")
+	lln0.CreateReportControlBlock(iec61850.ReportControlBlockConfig{
+		Name:    "EventsRCB01",
+		DataSet: "Events",
+		ConfRev: 1,
+		IntgPd:  1000,
+	})
+	lln0.CreateSettingGroupControlBlock(1, 2)
+	return model
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	var snapshot bytes.Buffer
+
+	// the first run of the server is configured by a client and the application
+	if !t.Run("snapshot", func(t *testing.T) {
+		model := createModel(t)
+		server := test.NewServer(t, model)
+		test.StartServer(t, server, port)
+
+		client := test.ConnectClient(t, port)
+		if err := client.WriteObject(rcbRef+".RptID", iec61850.RP, "events"); err != nil {
+			t.Fatalf("write RptID error %v\n", err)
+		}
+		if err := client.SetIntgPd(rcbRef, 5000); err != nil {
+			t.Fatalf("write IntgPd error %v\n", err)
+		}
+
+		server.LockDataModel()
+		server.UpdateInt32AttributeValue(model.GetModelNodeByObjectReference(settingRef), 42)
+		server.UpdateBooleanAttributeValue(model.GetModelNodeByObjectReference(statusRef), true)
+		server.UnlockDataModel()
+		if err := server.ChangeActiveSettingGroup(model.GetSettingGroupControlBlock("stateDevice1"), 2); err != nil {
+			t.Fatalf("change setting group error %v\n", err)
+		}
+		if err := server.Snapshot(&snapshot, iec61850.ALL); err != nil {
+			t.Fatalf("snapshot error %v\n", err)
+		}
+	}) {
+		return
+	}
+
+	// the second run restores the settings and the configuration, but not the status
+	t.Run("restore", func(t *testing.T) {
+		model := createModel(t)
+		server := test.NewServer(t, model)
+		if err := server.Restore(bytes.NewReader(snapshot.Bytes())); err != nil {
+			t.Fatalf("restore error %v\n", err)
+		}
+		test.StartServer(t, server, port)
+
+		if value, err := server.GetAttributeValue(model.GetModelNodeByObjectReference(settingRef)); err != nil || value.Value != int64(42) {
+			t.Fatalf("setting not restored: %v %v\n", value, err)
+		}
+		if value, err := server.GetAttributeValue(model.GetModelNodeByObjectReference(statusRef)); err != nil || value.Value != false {
+			t.Fatalf("status restored: %v %v\n", value, err)
+		}
+		if actSG := server.GetActiveSettingGroup(model.GetSettingGroupControlBlock("stateDevice1")); actSG != 2 {
+			t.Fatalf("active setting group %d instead of 2\n", actSG)
+		}
+
+		client := test.ConnectClient(t, port)
+		rcb, err := client.GetRCBValues(rcbRef)
+		if err != nil {
+			t.Fatalf("read RCB error %v\n", err)
+		}
+		if rcb.RptId != "events" || rcb.IntgPd != 5000 || rcb.DatSet != "stateDevice1/LLN0$Events" {
+			t.Fatalf("RCB not restored: %+v\n", rcb)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package server_stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10122
+
+func TestStats(t *testing.T) {
+	model, lln0, ggio := test.NewModel(t, "stats")
+	ggio.CreateDataObjectCDC_SPS("Ind1")
+	ggio.CreateDataObjectCDC_SPG("SPCSet1")
+	ggio.CreateDataObjectCDC_SPC("SPCSO1", iec61850.CONTROL_MODEL_SBO_NORMAL, 0)
+	ds := lln0.CreateDataSet("Events")
+	ds.AddDataSetEntry("GGIO1$ST$Ind1$stVal")
+	lln0.CreateReportControlBlock(iec61850.ReportControlBlockConfig{
+		Name:    "EventsRCB01",
+		DataSet: "Events",
+		ConfRev: 1,
+		TrgOps:  iec61850.TrgOps{DataChange: true},
+	})
+
+	server := test.NewServer(t, model)
+	server.SetControlHandler(model.GetModelNodeByObjectReference("statsDevice1/GGIO1.SPCSO1"), func(node *iec61850.ModelNode, action *iec61850.ControlAction, mmsValue *iec61850.MmsValue, test bool) iec61850.ControlHandlerResult {
+		return iec61850.CONTROL_RESULT_OK
+	})
+	server.SetWriteAccessPolicy(iec61850.SP, iec61850.ACCESS_POLICY_DENY)
+	server.EnableStats()
+	test.StartServer(t, server, port)
+
+	client := test.ConnectClient(t, port)
+
+	const rcbRef = "statsDevice1/LLN0.EventsRCB01"
+	reports := make(chan struct{}, 10)
+	if err := client.InstallReportHandler(rcbRef, "Events", func(report iec61850.ClientReport) {
+		reports <- struct{}{}
+	}); err != nil {
+		t.Fatalf("install report handler error %v\n", err)
+	}
+	if err := client.SetRptEna(rcbRef, true); err != nil {
+		t.Fatalf("enable report error %v\n", err)
+	}
+
+	if _, err := client.ReadObject("statsDevice1/GGIO1.Ind1.stVal", iec61850.ST); err != nil {
+		t.Fatalf("read error %v\n", err)
+	}
+	if err := client.WriteObject("statsDevice1/GGIO1.SPCSet1.setVal", iec61850.SP, true); err == nil {
+		t.Fatalf("write of a denied setting not rejected\n")
+	}
+	if err := client.ControlForSboWithNormalSecurity("statsDevice1/GGIO1.SPCSO1", true); err != nil {
+		t.Fatalf("control error %v\n", err)
+	}
+
+	server.LockDataModel()
+	server.UpdateBooleanAttributeValue(model.GetModelNodeByObjectReference("statsDevice1/GGIO1.Ind1.stVal"), true)
+	server.UnlockDataModel()
+	select {
+	case <-reports:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("no report received\n")
+	}
+
+	stats := server.Stats()
+	if stats.OpenConnections != 1 || stats.Connections != 1 || len(stats.ClientConnections) != 1 {
+		t.Fatalf("unexpected connections %+v\n", stats)
+	}
+	if read := stats.Services[iec61850.ACCESS_SERVICE_READ]; read.Requests == 0 {
+		t.Fatalf("reads not counted %+v\n", read)
+	}
+	if write := stats.Services[iec61850.ACCESS_SERVICE_WRITE]; write.Requests == 0 || write.Rejected != 1 {
+		t.Fatalf("unexpected writes %+v\n", write)
+	}
+	if controls := stats.Controls; controls.Selects != 1 || controls.Operates != 1 || controls.SelectsFailed != 0 || controls.OperatesFailed != 0 {
+		t.Fatalf("unexpected controls %+v\n", controls)
+	}
+	if rcb := stats.ReportControlBlocks[rcbRef]; rcb.Reports == 0 || rcb.Overflows != 0 {
+		t.Fatalf("unexpected reports %+v\n", rcb)
+	}
+	if read := stats.ClientConnections[0].Services[iec61850.ACCESS_SERVICE_READ]; read.Requests == 0 {
+		t.Fatalf("reads of the client not counted %+v\n", read)
+	}
+
+	client.Close()
+	time.Sleep(200 * time.Millisecond)
+	if stats := server.Stats(); stats.OpenConnections != 0 || stats.Connections != 1 {
+		t.Fatalf("closed connection still counted %+v\n", stats)
+	}
+}
@@ -0,0 +1,96 @@
+//go:build linux && amd64
+
+package server_sv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10116
+
+func TestSVCBPublisher(t *testing.T) {
+	model := iec61850.NewIedModel("sv")
+	t.Cleanup(model.Destroy)
+
+	ld := model.CreateLogicalDevice("Device1")
+	lln0 := ld.CreateLogicalNode("LLN0")
+	lln0.CreateDataObjectCDC_ENS("Mod")
+	tctr := ld.CreateLogicalNode("TCTR1")
+	tctr.CreateDataObjectCDC_SAV("Amp", true)
+
+	ds := lln0.CreateDataSet("Currents")
+	ds.AddDataSetEntry("TCTR1$MX$Amp$instMag$i")
+	ds.AddDataSetEntry("TCTR1$MX$Amp$q")
+	lln0.CreateSVControlBlock(iec61850.SVControlBlockConfig{
+		Name:    "MSVCB01",
+		SvID:    "sv01",
+		DataSet: "Currents",
+		ConfRev: 1,
+		SmpMod:  1,
+		SmpRate: 100,
+		Address: &iec61850.PhyComAddress{VlanPriority: 4, AppId: 0x4000, DstAddress: [6]uint8{0x01, 0x0c, 0xcd, 0x04, 0x00, 0x01}},
+	})
+
+	const svcbRef = "svDevice1/LLN0.MSVCB01"
+	config, err := model.GetSVCBConfig(svcbRef)
+	if err != nil {
+		t.Fatalf("get SVCB config error %v\n", err)
+	}
+	if config.SvID != "sv01" || config.DataSet != "svDevice1/LLN0$Currents" || config.Address == nil || config.Address.AppId != 0x4000 {
+		t.Fatalf("unexpected SVCB config %+v\n", config)
+	}
+	if _, err := model.GetSVCBConfig("svDevice1/LLN0.MSVCB02"); err == nil {
+		t.Fatalf("missing SVCB found\n")
+	}
+
+	server := test.NewServer(t, model)
+	events := make(chan iec61850.SVCBEvent, 10)
+	if err := server.SetSVCBEventHandler(svcbRef, func(config *iec61850.SVCBConfig, event iec61850.SVCBEvent) {
+		events <- event
+	}); err != nil {
+		t.Fatalf("set SVCB handler error %v\n", err)
+	}
+	publisher, err := server.NewSVCBPublisher(svcbRef, iec61850.SVCBPublisherOptions{InterfaceID: "eth0"})
+	if err != nil {
+		t.Fatalf("create SVCB publisher error %v\n", err)
+	}
+	defer publisher.Close()
+
+	test.StartServer(t, server, port)
+
+	client := test.ConnectClient(t, port)
+
+	if err := client.WriteObject(svcbRef+".SvEna", iec61850.MS, true); err != nil {
+		t.Fatalf("enable SVCB error %v\n", err)
+	}
+	select {
+	case event := <-events:
+		if event != iec61850.SVCB_EVENT_ENABLE {
+			t.Fatalf("unexpected event %v\n", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("SVCB event not received\n")
+	}
+	if !publisher.IsRunning() {
+		t.Fatalf("publisher not started\n")
+	}
+
+	if err := client.WriteObject(svcbRef+".SvEna", iec61850.MS, false); err != nil {
+		t.Fatalf("disable SVCB error %v\n", err)
+	}
+	select {
+	case event := <-events:
+		if event != iec61850.SVCB_EVENT_DISABLE {
+			t.Fatalf("unexpected event %v\n", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("SVCB event not received\n")
+	}
+	if publisher.IsRunning() {
+		t.Fatalf("publisher not stopped\n")
+	}
+}
@@ -0,0 +1,72 @@
+package server_threadless
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10127
+
+func TestThreadless(t *testing.T) {
+	model, _, ggio := test.NewModel(t, "poll")
+	tmms := ggio.CreateDataObject("Tmms", 0)
+	tmms.CreateDataAttribute("setVal", iec61850.DA_TYPE_INT32, iec61850.SP, iec61850.TrgOps{}, 0, 0)
+
+	server := test.NewServer(t, model)
+
+	// the handlers run only inside of Poll
+	var polling, outsidePoll atomic.Bool
+	var writes atomic.Int32
+	server.SetHandleWriteAccess(model.GetModelNodeByObjectReference("pollDevice1/GGIO1.Tmms.setVal"), func(node *iec61850.ModelNode, mmsValue *iec61850.MmsValue, connection *iec61850.ClientConnection) iec61850.MmsDataAccessError {
+		if !polling.Load() {
+			outsidePoll.Store(true)
+		}
+		writes.Add(1)
+		return iec61850.DATA_ACCESS_ERROR_SUCCESS
+	})
+
+	if err := server.StartThreadless(port); err != nil {
+		t.Fatalf("start error %v\n", err)
+	}
+	defer server.Stop()
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			polling.Store(true)
+			server.Poll(10 * time.Millisecond)
+			polling.Store(false)
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	defer func() {
+		close(done)
+		<-stopped
+	}()
+
+	settings := iec61850.NewSettings()
+	settings.Port = port
+	client, err := iec61850.NewClient(settings)
+	if err != nil {
+		t.Fatalf("create client error %v\n", err)
+	}
+	defer client.Close()
+
+	if err := client.WriteObject("pollDevice1/GGIO1.Tmms.setVal", iec61850.SP, 5); err != nil {
+		t.Fatalf("write error %v\n", err)
+	}
+	if writes.Load() != 1 || outsidePoll.Load() {
+		t.Fatalf("write handler called %d times, outside of Poll %v\n", writes.Load(), outsidePoll.Load())
+	}
+}
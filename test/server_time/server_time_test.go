@@ -0,0 +1,98 @@
+package server_time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10121
+
+func TestTimeSource(t *testing.T) {
+	// the clock of the simulated IED is an hour behind and lost its synchronization
+	source := iec61850.NewSystemTimeSource(iec61850.TimeQuality{ClockNotSynchronized: true, TimeAccuracy: 10})
+	source.SetOffset(-time.Hour)
+	iec61850.SetProcessTimeSource(source)
+	defer iec61850.SetProcessTimeSource(nil)
+
+	model, lln0, ggio := test.NewModel(t, "time")
+	ggio.CreateDataObjectCDC_SPS("Ind1")
+	ggio.CreateDataObjectCDC_SPC("SPCSO1", iec61850.CONTROL_MODEL_DIRECT_NORMAL, iec61850.CDC_CTL_MODEL_IS_TIME_ACTIVATED)
+	ds := lln0.CreateDataSet("Events")
+	ds.AddDataSetEntry("GGIO1$ST$Ind1$stVal")
+	lln0.CreateReportControlBlock(iec61850.ReportControlBlockConfig{
+		Name:    "EventsRCB01",
+		DataSet: "Events",
+		ConfRev: 1,
+		TrgOps:  iec61850.TrgOps{DataChange: true},
+		OptFlds: iec61850.OptFlds{TimeOfEntry: true},
+	})
+
+	server := test.NewServer(t, model)
+	operated := make(chan time.Time, 1)
+	server.SetControlHandler(model.GetModelNodeByObjectReference("timeDevice1/GGIO1.SPCSO1"), func(node *iec61850.ModelNode, action *iec61850.ControlAction, mmsValue *iec61850.MmsValue, test bool) iec61850.ControlHandlerResult {
+		operated <- time.Now()
+		return iec61850.CONTROL_RESULT_OK
+	})
+	test.StartServer(t, server, port)
+
+	client := test.ConnectClient(t, port)
+
+	const rcbRef = "timeDevice1/LLN0.EventsRCB01"
+	reports := make(chan int64, 10)
+	if err := client.InstallReportHandler(rcbRef, "Events", func(report iec61850.ClientReport) {
+		if report.HasTimestamp() {
+			reports <- report.GetTimestamp()
+		}
+	}); err != nil {
+		t.Fatalf("install report handler error %v\n", err)
+	}
+	if err := client.SetRptEna(rcbRef, true); err != nil {
+		t.Fatalf("enable report error %v\n", err)
+	}
+
+	ind1 := model.GetModelNodeByObjectReference("timeDevice1/GGIO1.Ind1")
+	if err := server.UpdateDataObject(ind1, true, iec61850.QUALITY_VALIDITY_GOOD, nil); err != nil {
+		t.Fatalf("update data object error %v\n", err)
+	}
+
+	expected := time.Now().Add(-time.Hour)
+	server.LockDataModel()
+	timestamp := server.GetTimestampAttributeValue(model.GetModelNodeByObjectReference("timeDevice1/GGIO1.Ind1.t"))
+	server.UnlockDataModel()
+	if timestamp == nil || !timestamp.IsClockNotSynchronized() || timestamp.HasClockFailure() || timestamp.GetSubSecondPrecision() != 10 {
+		t.Fatalf("unexpected quality of t\n")
+	}
+	if diff := timestamp.GetTime().Sub(expected).Abs(); diff > time.Minute {
+		t.Fatalf("t %v not from the time source\n", timestamp.GetTime())
+	}
+
+	// the time of entry of the report is created by libiec61850
+	select {
+	case ms := <-reports:
+		if diff := time.UnixMilli(ms).Sub(expected).Abs(); diff > time.Minute {
+			t.Fatalf("time of entry %v not from the time source\n", time.UnixMilli(ms))
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("no report received\n")
+	}
+
+	// the timers of the controls run on the time source, the activation time is an hour behind
+	now, _ := source.Now()
+	param := iec61850.NewControlObjectParam(true)
+	param.OperateTime = uint64(now.Add(500 * time.Millisecond).UnixMilli())
+	sent := time.Now()
+	if err := client.ControlByControlModel("timeDevice1/GGIO1.SPCSO1", iec61850.CONTROL_MODEL_DIRECT_NORMAL, param); err != nil {
+		t.Fatalf("time activated control error %v\n", err)
+	}
+	select {
+	case at := <-operated:
+		if wait := at.Sub(sent); wait < 300*time.Millisecond {
+			t.Fatalf("control operated after %v, before its activation time\n", wait)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("time activated control not operated\n")
+	}
+}
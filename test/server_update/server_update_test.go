@@ -0,0 +1,165 @@
+package server_update
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/marrasen/iec61850"
+	"github.com/marrasen/iec61850/test"
+)
+
+const port = 10109
+
+func TestUpdateAttributeValues(t *testing.T) {
+	model, lln0, ggio := test.NewModel(t, "upd")
+	ggio.CreateDataObjectCDC_SPS("Ind1")
+	ggio.CreateDataObjectCDC_DPS("Pos1")
+	ggio.CreateDataObjectCDC_MV("AnIn1", false)
+
+	custom := ggio.CreateDataObject("Custom", 0)
+	custom.CreateDataAttribute("cnt", iec61850.DA_TYPE_INT64, iec61850.ST, iec61850.TrgOps{DataChange: true}, 0, 0)
+	custom.CreateDataAttribute("num", iec61850.DA_TYPE_INT32U, iec61850.ST, iec61850.TrgOps{DataChange: true}, 0, 0)
+	custom.CreateDataAttribute("mode", iec61850.DA_TYPE_ENUMERATED, iec61850.ST, iec61850.TrgOps{DataChange: true}, 0, 0)
+	custom.CreateDataAttribute("raw", iec61850.DA_TYPE_OCTET_STRING_64, iec61850.ST, iec61850.TrgOps{DataChange: true}, 0, 0)
+	custom.CreateDataAttribute("trg", iec61850.DA_TYPE_TRGOPS, iec61850.ST, iec61850.TrgOps{DataChange: true}, 0, 0)
+	sts := custom.CreateDataAttribute("sts", iec61850.DA_TYPE_CONSTRUCTED, iec61850.ST, iec61850.TrgOps{DataChange: true}, 0, 0)
+	sts.CreateDataAttribute("val", iec61850.DA_TYPE_BOOLEAN, iec61850.ST, iec61850.TrgOps{DataChange: true}, 0, 0)
+	sts.CreateDataAttribute("q", iec61850.DA_TYPE_QUALITY, iec61850.ST, iec61850.TrgOps{QualityChange: true}, 0, 0)
+
+	ds := lln0.CreateDataSet("Events")
+	ds.AddDataSetEntry("GGIO1$ST$Ind1")
+	lln0.CreateReportControlBlock(iec61850.ReportControlBlockConfig{
+		Name:    "EventsURCB01",
+		RptID:   "Events",
+		DataSet: "Events",
+		ConfRev: 1,
+		TrgOps:  iec61850.TrgOps{DataChange: true, QualityChange: true},
+		BufTm:   200,
+	})
+
+	server := test.NewServer(t, model)
+	test.StartServer(t, server, port)
+
+	client := test.ConnectClient(t, port)
+
+	server.LockDataModel()
+	server.UpdateBooleanAttributeValue(model.GetModelNodeByObjectReference("updDevice1/GGIO1.Ind1.stVal"), true)
+	server.UpdateDbposValue(model.GetModelNodeByObjectReference("updDevice1/GGIO1.Pos1.stVal"), iec61850.DBPOS_ON)
+	server.UpdateInt64AttributeValue(model.GetModelNodeByObjectReference("updDevice1/GGIO1.Custom.cnt"), 1<<40)
+	server.UpdateUnsignedAttributeValue(model.GetModelNodeByObjectReference("updDevice1/GGIO1.Custom.num"), 17)
+	server.UpdateEnumAttributeValue(model.GetModelNodeByObjectReference("updDevice1/GGIO1.Custom.mode"), 3)
+	octetErr := server.UpdateOctetStringAttributeValue(model.GetModelNodeByObjectReference("updDevice1/GGIO1.Custom.raw"), []byte{1, 2, 3})
+	anyErr := server.UpdateAttributeValue(model.GetModelNodeByObjectReference("updDevice1/GGIO1.AnIn1.mag.f"),
+		&iec61850.MmsValue{Type: iec61850.Float, Value: 12.5})
+	bitStringErr := server.UpdateAttributeValue(model.GetModelNodeByObjectReference("updDevice1/GGIO1.Custom.trg"),
+		&iec61850.MmsValue{Type: iec61850.BitString, Value: 0x12})
+	// the quality of the structure keeps its 13 bits
+	structureErr := server.UpdateAttributeValue(model.GetModelNodeByObjectReference("updDevice1/GGIO1.Custom.sts"),
+		&iec61850.MmsValue{Type: iec61850.Structure, Value: []*iec61850.MmsValue{
+			{Type: iec61850.Boolean, Value: true},
+			{Type: iec61850.BitString, Value: uint32(iec61850.QUALITY_VALIDITY_QUESTIONABLE)},
+		}})
+	mismatchErr := server.UpdateAttributeValue(model.GetModelNodeByObjectReference("updDevice1/GGIO1.AnIn1.mag.f"),
+		&iec61850.MmsValue{Type: iec61850.Boolean, Value: true})
+	server.UnlockDataModel()
+	if bitStringErr != nil {
+		t.Fatalf("update bit string error %v\n", bitStringErr)
+	}
+	if structureErr != nil {
+		t.Fatalf("update structure error %v\n", structureErr)
+	}
+	if mismatchErr == nil {
+		t.Fatalf("update with boolean value of float attribute succeeded\n")
+	}
+	if octetErr != nil {
+		t.Fatalf("update octet string error %v\n", octetErr)
+	}
+	if anyErr != nil {
+		t.Fatalf("update attribute value error %v\n", anyErr)
+	}
+
+	for _, read := range []struct {
+		ref      string
+		expected any
+	}{
+		{"updDevice1/GGIO1.Ind1.stVal", true},
+		// bit strings are read with the first bit as least significant bit, ON (10) reads as 1
+		{"updDevice1/GGIO1.Pos1.stVal", uint32(1)},
+		{"updDevice1/GGIO1.Custom.cnt", int64(1 << 40)},
+		{"updDevice1/GGIO1.Custom.num", uint32(17)},
+		{"updDevice1/GGIO1.Custom.mode", int64(3)},
+		{"updDevice1/GGIO1.Custom.trg", uint32(0x12)},
+		{"updDevice1/GGIO1.Custom.sts.val", true},
+		{"updDevice1/GGIO1.Custom.sts.q", uint32(iec61850.QUALITY_VALIDITY_QUESTIONABLE)},
+	} {
+		value, err := client.ReadObject(read.ref, iec61850.ST)
+		if err != nil {
+			t.Fatalf("read %s error %v\n", read.ref, err)
+		}
+		if value.Value != read.expected {
+			t.Fatalf("read %s expected %v, got %v\n", read.ref, read.expected, value.Value)
+		}
+	}
+	raw, err := client.ReadObject("updDevice1/GGIO1.Custom.raw", iec61850.ST)
+	if err != nil {
+		t.Fatalf("read raw error %v\n", err)
+	}
+	if !bytes.Equal(raw.Value.([]byte), []byte{1, 2, 3}) {
+		t.Fatalf("unexpected raw %v\n", raw.Value)
+	}
+	mag, err := client.ReadFloatValue("updDevice1/GGIO1.AnIn1.mag.f", iec61850.MX)
+	if err != nil || mag != 12.5 {
+		t.Fatalf("read mag %v error %v\n", mag, err)
+	}
+
+	// stVal, q and t of the data object change together in a single report
+	rcbRef := "updDevice1/LLN0.RP.EventsURCB01"
+	reports := make(chan string, 10)
+	if err := client.InstallReportHandler(rcbRef, "Events", func(report iec61850.ClientReport) {
+		reports <- report.GetRptId()
+	}); err != nil {
+		t.Fatalf("install report handler error %v\n", err)
+	}
+	if err := client.SetRptEna(rcbRef, true); err != nil {
+		t.Fatalf("enable report error %v\n", err)
+	}
+
+	timestamp := iec61850.NewTimestamp(time.Now()).SetClockNotSynchronized(true)
+	if err := server.UpdateDataObject(model.GetModelNodeByObjectReference("updDevice1/GGIO1.Ind1"), false,
+		iec61850.QUALITY_VALIDITY_QUESTIONABLE, timestamp); err != nil {
+		t.Fatalf("update data object error %v\n", err)
+	}
+
+	select {
+	case <-reports:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("no report received\n")
+	}
+	select {
+	case <-reports:
+		t.Fatalf("unexpected second report\n")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	stVal, err := client.ReadBoolValue("updDevice1/GGIO1.Ind1.stVal", iec61850.ST)
+	if err != nil || stVal {
+		t.Fatalf("read stVal %v error %v\n", stVal, err)
+	}
+	q, err := client.ReadObject("updDevice1/GGIO1.Ind1.q", iec61850.ST)
+	if err != nil {
+		t.Fatalf("read q error %v\n", err)
+	}
+	if q.Value != uint32(iec61850.QUALITY_VALIDITY_QUESTIONABLE) {
+		t.Fatalf("unexpected q %v\n", q.Value)
+	}
+
+	if err := server.UpdateDataObject(model.GetModelNodeByObjectReference("updDevice1/GGIO1.AnIn1"), 3.5,
+		iec61850.QUALITY_VALIDITY_GOOD, nil); err != nil {
+		t.Fatalf("update measured value error %v\n", err)
+	}
+	mag, err = client.ReadFloatValue("updDevice1/GGIO1.AnIn1.mag.f", iec61850.MX)
+	if err != nil || mag != 3.5 {
+		t.Fatalf("read mag %v error %v\n", mag, err)
+	}
+}
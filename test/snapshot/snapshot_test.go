@@ -0,0 +1,120 @@
+package snapshot
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/marrasen/iec61850"
+)
+
+const (
+	port           = 10104
+	AnIn1ObjectRef = "simpleIOGenericIO/GGIO1.AnIn1.mag.f"
+)
+
+func TestSnapshotDiff(t *testing.T) {
+	model, err := iec61850.CreateModelFromConfigFileEx("../tls_server/model.cfg")
+	if err != nil {
+		t.Fatalf("create model error %v\n", err)
+	}
+	defer model.Destroy()
+
+	server := iec61850.NewServerWithConfig(iec61850.NewServerConfig(), model)
+	server.Start(port)
+	defer server.Destroy()
+	defer server.Stop()
+
+	settings := iec61850.NewSettings()
+	settings.Port = port
+	client, err := iec61850.NewClient(settings)
+	if err != nil {
+		t.Fatalf("create client error %v\n", err)
+	}
+	defer client.Close()
+
+	node := model.GetModelNodeByObjectReference(AnIn1ObjectRef)
+	server.UpdateFloatAttributeValue(node, 1.5)
+
+	before, err := client.GetSnapshot()
+	if err != nil {
+		t.Fatalf("get snapshot error %v\n", err)
+	}
+	server.UpdateFloatAttributeValue(node, 2.5)
+	after, err := client.GetSnapshot()
+	if err != nil {
+		t.Fatalf("get snapshot error %v\n", err)
+	}
+
+	// both formats survive a round trip
+	dir := t.TempDir()
+	for _, name := range []string{"before.json", "before.yaml"} {
+		filename := filepath.Join(dir, name)
+		if err := before.Save(filename); err != nil {
+			t.Fatalf("save %s error %v\n", name, err)
+		}
+		loaded, err := iec61850.LoadSnapshot(filename)
+		if err != nil {
+			t.Fatalf("load %s error %v\n", name, err)
+		}
+		if changes := iec61850.DiffSnapshots(before, loaded, iec61850.DiffOptions{}); len(changes) != 0 {
+			t.Errorf("%s differs after round trip: %v\n", name, changes)
+		}
+	}
+
+	changes := iec61850.DiffSnapshots(before, after, iec61850.DiffOptions{})
+	if len(changes) != 1 {
+		t.Fatalf("unexpected changes %v\n", changes)
+	}
+	if c := changes[0]; c.Kind != iec61850.ValueChanged || c.Ref != AnIn1ObjectRef {
+		t.Errorf("unexpected change %v\n", c)
+	}
+
+	if changes := iec61850.DiffSnapshots(before, after, iec61850.DiffOptions{Ignore: []string{"*/GGIO1.AnIn1.*"}}); len(changes) != 0 {
+		t.Errorf("ignored change reported %v\n", changes)
+	}
+}
+
+func TestDiffSnapshotsModel(t *testing.T) {
+	do := func(name, cdc string, das ...iec61850.DA) iec61850.DO {
+		return iec61850.DO{Data: name, Ref: "LD/GGIO1." + name, CDC: cdc, DAs: das}
+	}
+	da := func(ref string, fc iec61850.FC, bType string) iec61850.DA {
+		return iec61850.DA{Data: filepath.Ext(ref)[1:], Ref: ref, FC: fc, BType: bType}
+	}
+	snapshot := func(dos ...iec61850.DO) *iec61850.Snapshot {
+		return &iec61850.Snapshot{Model: iec61850.DataModel{LDs: []iec61850.LD{{
+			Data: "LD",
+			LNs:  []iec61850.LN{{Data: "GGIO1", Ref: "LD/GGIO1", DOs: dos}},
+		}}}}
+	}
+
+	old := snapshot(
+		do("Ind1", "SPS", da("LD/GGIO1.Ind1.stVal", iec61850.ST, "BOOLEAN")),
+		do("Ind2", "SPS", da("LD/GGIO1.Ind2.stVal", iec61850.ST, "BOOLEAN")),
+	)
+	new := snapshot(
+		do("Ind1", "INS", da("LD/GGIO1.Ind1.stVal", iec61850.ST, "INT32")),
+		do("Ind3", "SPS", da("LD/GGIO1.Ind3.stVal", iec61850.ST, "BOOLEAN")),
+	)
+
+	want := []struct {
+		kind iec61850.ChangeKind
+		ref  string
+	}{
+		{iec61850.TypeChanged, "LD/GGIO1.Ind1"},
+		{iec61850.TypeChanged, "LD/GGIO1.Ind1.stVal"},
+		{iec61850.NodeRemoved, "LD/GGIO1.Ind2"},
+		{iec61850.NodeRemoved, "LD/GGIO1.Ind2.stVal"},
+		{iec61850.NodeAdded, "LD/GGIO1.Ind3"},
+		{iec61850.NodeAdded, "LD/GGIO1.Ind3.stVal"},
+	}
+	changes := iec61850.DiffSnapshots(old, new, iec61850.DiffOptions{})
+	if len(changes) != len(want) {
+		t.Fatalf("unexpected changes %v\n", changes)
+	}
+	for i, w := range want {
+		if changes[i].Kind != w.kind || changes[i].Ref != w.ref {
+			t.Errorf("change %d is %v, want %s %s\n", i, changes[i], w.kind, w.ref)
+		}
+	}
+}
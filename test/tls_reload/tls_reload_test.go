@@ -0,0 +1,218 @@
+package tls_reload
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/marrasen/iec61850"
+)
+
+const AnIn1ObjectRef = "simpleIOGenericIO/GGIO1.AnIn1.mag.f"
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *rsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, dir string) *testCA {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate CA key error %v\n", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "root_CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create CA certificate error %v\n", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	writePem(t, filepath.Join(dir, "root_CA.pem"), "CERTIFICATE", der)
+	return &testCA{cert: cert, key: key}
+}
+
+// issue writes a certificate with the given serial number and its key to <name>.pem and <name>.key.
+func (ca *testCA) issue(t *testing.T, dir, name string, serial int64) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key error %v\n", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("create certificate error %v\n", err)
+	}
+	writePem(t, filepath.Join(dir, name+".pem"), "CERTIFICATE", der)
+	writePem(t, filepath.Join(dir, name+".key"), "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+}
+
+func writePem(t *testing.T, filename, blockType string, der []byte) {
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(filename, data, 0o600); err != nil {
+		t.Fatalf("write %s error %v\n", filename, err)
+	}
+}
+
+// peerSerial opens a new TLS connection to the server and returns the serial number of its certificate.
+func peerSerial(t *testing.T, dir string) int64 {
+	clientCert, err := tls.LoadX509KeyPair(filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key"))
+	if err != nil {
+		t.Fatalf("load client certificate error %v\n", err)
+	}
+	conn, err := tls.Dial("tcp", "localhost:3782", &tls.Config{
+		Certificates:       []tls.Certificate{clientCert},
+		InsecureSkipVerify: true,
+		MaxVersion:         tls.VersionTLS12,
+	})
+	if err != nil {
+		t.Fatalf("tls dial error %v\n", err)
+	}
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+}
+
+func newTLSConfig(dir, name string) *iec61850.TLSConfig {
+	tlsConfig := iec61850.NewTLSConfig()
+	tlsConfig.KeyFile = filepath.Join(dir, name+".key")
+	tlsConfig.CertFile = filepath.Join(dir, name+".pem")
+	tlsConfig.ChainValidation = true
+	tlsConfig.AddCACertificateFromFile(filepath.Join(dir, "root_CA.pem"))
+	return tlsConfig
+}
+
+func TestServerRestartWithTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir)
+	ca.issue(t, dir, "server", 100)
+	ca.issue(t, dir, "client", 300)
+
+	model, err := iec61850.CreateModelFromConfigFileEx("../tls_server/model.cfg")
+	if err != nil {
+		t.Fatalf("create model error %v\n", err)
+	}
+	defer model.Destroy()
+
+	tlsConfig := newTLSConfig(dir, "server")
+	server, err := iec61850.NewServerWithTlsSupport(iec61850.NewServerConfig(), tlsConfig, model)
+	if err != nil {
+		t.Fatalf("create server error %v\n", err)
+	}
+	defer server.Destroy()
+
+	server.Start(-1)
+	defer server.Stop()
+
+	node := model.GetModelNodeByObjectReference(AnIn1ObjectRef)
+	server.UpdateFloatAttributeValue(node, 42.5)
+
+	if serial := peerSerial(t, dir); serial != 100 {
+		t.Fatalf("initial server certificate serial %d, want 100\n", serial)
+	}
+
+	watcher := iec61850.WatchTLSFiles(tlsConfig, 50*time.Millisecond)
+	defer watcher.Stop()
+
+	// rotate the certificate on disk
+	time.Sleep(10 * time.Millisecond)
+	ca.issue(t, dir, "server", 200)
+
+	select {
+	case change := <-watcher.C:
+		if change.CRLOnly {
+			t.Fatalf("certificate change reported as CRL only\n")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("certificate change not detected\n")
+	}
+	// stopping again by the deferred call is a no-op
+	watcher.Stop()
+
+	// the identity is replaced, not replayed once per call
+	server.SetServerIdentity("vendor", "model", "1.0")
+	server.SetServerIdentity("vendor", "model", "2.0")
+
+	if err := server.RestartWithTLSConfig(tlsConfig); err != nil {
+		t.Fatalf("restart with TLS config error %v\n", err)
+	}
+	if !server.IsRunning() {
+		t.Fatalf("server not running after restart\n")
+	}
+	if serial := peerSerial(t, dir); serial != 200 {
+		t.Fatalf("reloaded server certificate serial %d, want 200\n", serial)
+	}
+
+	value, err := server.GetAttributeValue(node)
+	if err != nil {
+		t.Fatalf("read %s error %v\n", AnIn1ObjectRef, err)
+	}
+	if value.Value != float32(42.5) {
+		t.Errorf("value of %s after reload %v, want 42.5\n", AnIn1ObjectRef, value.Value)
+	}
+
+	broken := newTLSConfig(dir, "missing")
+	if err := server.RestartWithTLSConfig(broken); err == nil {
+		t.Errorf("restart with missing certificate succeeded\n")
+	}
+	if serial := peerSerial(t, dir); serial != 200 {
+		t.Errorf("server certificate serial %d after failed reload, want 200\n", serial)
+	}
+}
+
+func TestClientReloadTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir)
+	ca.issue(t, dir, "server", 100)
+	ca.issue(t, dir, "client", 300)
+
+	model, err := iec61850.CreateModelFromConfigFileEx("../tls_server/model.cfg")
+	if err != nil {
+		t.Fatalf("create model error %v\n", err)
+	}
+	defer model.Destroy()
+
+	server, err := iec61850.NewServerWithTlsSupport(iec61850.NewServerConfig(), newTLSConfig(dir, "server"), model)
+	if err != nil {
+		t.Fatalf("create server error %v\n", err)
+	}
+	defer server.Destroy()
+	server.Start(-1)
+	defer server.Stop()
+
+	settings := iec61850.NewSettings()
+	settings.Port = -1
+	client, err := iec61850.NewClientWithTlsSupport(settings, newTLSConfig(dir, "client"))
+	if err != nil {
+		t.Fatalf("create client error %v\n", err)
+	}
+	defer client.Close()
+
+	ca.issue(t, dir, "client", 400)
+	if err := client.ReloadTLSConfig(newTLSConfig(dir, "client")); err != nil {
+		t.Fatalf("reload client TLS config error %v\n", err)
+	}
+	if _, err := client.ReadObject(AnIn1ObjectRef, iec61850.MX); err != nil {
+		t.Errorf("read %s after reload error %v\n", AnIn1ObjectRef, err)
+	}
+}
@@ -30,6 +30,7 @@ type TLSConfig struct {
 	MaxTlsVersion                TLSConfigVersion
 	caCerts                      []string
 	allowedCertificates          []string
+	crls                         []string
 	tlsConfigurationEventHandler *TLSConfigurationEventHandler
 }
 
@@ -41,6 +42,7 @@ func NewTLSConfig() *TLSConfig {
 		MaxTlsVersion:              TLS_VERSION_NOT_SELECTED,
 		caCerts:                    make([]string, 0),
 		allowedCertificates:        make([]string, 0),
+		crls:                       make([]string, 0),
 	}
 }
 
@@ -52,6 +54,19 @@ func (that *TLSConfig) AddAllowedCertificateFromFile(filename string) {
 	that.allowedCertificates = append(that.allowedCertificates, filename)
 }
 
+// AddCRLFromFile adds a certificate revocation list that is checked when validating peer certificates.
+func (that *TLSConfig) AddCRLFromFile(filename string) {
+	that.crls = append(that.crls, filename)
+}
+
+// files returns every file the configuration is loaded from.
+func (that *TLSConfig) files() []string {
+	files := []string{that.KeyFile, that.CertFile}
+	files = append(files, that.caCerts...)
+	files = append(files, that.allowedCertificates...)
+	return files
+}
+
 func (that *TLSConfig) SetEventHandler(handler *TLSConfigurationEventHandler) {
 	that.tlsConfigurationEventHandler = handler
 }
@@ -74,15 +89,18 @@ func (that *TLSConfig) createCTlsConfig() (C.TLSConfiguration, error) {
 
 	if that.KeyPassword == "" {
 		if !bool(C.TLSConfiguration_setOwnKeyFromFile(tlsConfig, cKeyFile, nil)) {
+			C.TLSConfiguration_destroy(tlsConfig)
 			return nil, fmt.Errorf("failed to load private key %s", that.KeyFile)
 		}
 	} else {
 		if !bool(C.TLSConfiguration_setOwnKeyFromFile(tlsConfig, cKeyFile, cKeyPassword)) {
+			C.TLSConfiguration_destroy(tlsConfig)
 			return nil, fmt.Errorf("failed to load private key %s", that.KeyFile)
 		}
 	}
 
 	if !bool(C.TLSConfiguration_setOwnCertificateFromFile(tlsConfig, cCertFile)) {
+		C.TLSConfiguration_destroy(tlsConfig)
 		return nil, fmt.Errorf("failed to load own certificate %s", that.CertFile)
 	}
 
@@ -90,6 +108,7 @@ func (that *TLSConfig) createCTlsConfig() (C.TLSConfiguration, error) {
 		cCACert := C.CString(caCert)
 		if !bool(C.TLSConfiguration_addCACertificateFromFile(tlsConfig, cCACert)) {
 			C.free(unsafe.Pointer(cCACert))
+			C.TLSConfiguration_destroy(tlsConfig)
 			return nil, fmt.Errorf("failed to load CA certificate %s", caCert)
 		}
 		C.free(unsafe.Pointer(cCACert))
@@ -99,10 +118,30 @@ func (that *TLSConfig) createCTlsConfig() (C.TLSConfiguration, error) {
 		cCert := C.CString(cert)
 		if !bool(C.TLSConfiguration_addAllowedCertificateFromFile(tlsConfig, cCert)) {
 			C.free(unsafe.Pointer(cCert))
+			C.TLSConfiguration_destroy(tlsConfig)
 			return nil, fmt.Errorf("failed to load allowed certificate %s", cert)
 		}
 		C.free(unsafe.Pointer(cCert))
 	}
 
+	if err := that.loadCRLs(tlsConfig); err != nil {
+		C.TLSConfiguration_destroy(tlsConfig)
+		return nil, err
+	}
+
 	return tlsConfig, nil
 }
+
+// loadCRLs replaces the revocation lists of an existing C configuration with the configured CRL files.
+func (that *TLSConfig) loadCRLs(tlsConfig C.TLSConfiguration) error {
+	C.TLSConfiguration_resetCRL(tlsConfig)
+	for _, crl := range that.crls {
+		cCrl := C.CString(crl)
+		if !bool(C.TLSConfiguration_addCRLFromFile(tlsConfig, cCrl)) {
+			C.free(unsafe.Pointer(cCrl))
+			return fmt.Errorf("failed to load CRL %s", crl)
+		}
+		C.free(unsafe.Pointer(cCrl))
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package iec61850
+
+/*
+#include <iec61850_server.h>
+#include <iec61850_client.h>
+
+extern void connectionClosedHandlerBridge(void* parameter, IedConnection connection);
+
+// detachAttributeValues replaces the value of every data attribute below node with a private copy,
+// so the values survive when the server owning the value cache is destroyed.
+static void detachAttributeValues(ModelNode* node) {
+    while (node != NULL) {
+        if (node->modelType == DataAttributeModelType) {
+            DataAttribute* da = (DataAttribute*) node;
+            if (da->mmsValue != NULL) {
+                da->mmsValue = MmsValue_clone(da->mmsValue);
+            }
+        }
+        detachAttributeValues(node->firstChild);
+        node = node->sibling;
+    }
+}
+
+static void IedModel_detachAttributeValues(IedModel* model) {
+    detachAttributeValues((ModelNode*) model->firstChild);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// RestartWithTLSConfig replaces own certificate, key, CA list, allowed certificates and CRLs of a server
+// by restarting it. libiec61850 1.5.3 can only add certificates and keys to a TLS configuration in use,
+// so the underlying server instance is rebuilt on the same model with a new one: attribute values and
+// every handler or policy registered through this type are carried over, and a running server is
+// restarted on the same addresses.
+// All established connections are dropped, clients have to connect again with the new configuration.
+// Use ReloadCRLs for changed CRLs, which keeps the connections.
+// If the new configuration cannot be loaded the server keeps running with the current one.
+// It must not be called concurrently with other methods of the server.
+func (is *IedServer) RestartWithTLSConfig(tlsConfig *TLSConfig) error {
+	if is.tlsConfig == nil {
+		return fmt.Errorf("RestartWithTLSConfig: %w", TLSNotEnabled)
+	}
+	cTlsConfig, err := tlsConfig.createCTlsConfig()
+	if err != nil {
+		return fmt.Errorf("RestartWithTLSConfig: %w", err)
+	}
+	setTLSEventLogger(cTlsConfig, is.serverConfig.Logger)
+
+	running := is.IsRunning()
+	if running {
+		is.Stop()
+	}
+
+	is.rebuild(cTlsConfig)
+
+	if running {
+		if err := is.start(); err != nil {
+			return fmt.Errorf("RestartWithTLSConfig: restart failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// rebuild creates the underlying server instance again on the same model with the TLS configuration.
+// Attribute values are kept and the setup is replayed, the server must not be running.
+func (is *IedServer) rebuild(cTlsConfig C.TLSConfiguration) {
+	C.IedModel_detachAttributeValues(is.model.Model)
+	C.IedServer_destroy(is.server)
+	// the GOOSE control blocks of the new server are disabled
+	is.resetGoCBs()
+	if is.tlsConfig != nil && is.tlsConfig != cTlsConfig {
+		C.TLSConfiguration_destroy(is.tlsConfig)
+	}
+
+	// servers of NewServer have the defaults of libiec61850
+	var config C.IedServerConfig
+	if is.serverConfig != (ServerConfig{}) {
+		config = is.serverConfig.createIedServerConfig(is.serverConfig)
+		defer C.IedServerConfig_destroy(config)
+	}
+	is.server = C.IedServer_createWithConfig(is.model.Model, cTlsConfig, config)
+	is.tlsConfig = cTlsConfig
+
+	for _, fn := range is.setup {
+		fn()
+	}
+}
+
+// ReloadCRLs replaces the certificate revocation lists of the server with the CRL files of tlsConfig.
+// Unlike RestartWithTLSConfig this happens in place and keeps established connections open.
+func (is *IedServer) ReloadCRLs(tlsConfig *TLSConfig) error {
+	if is.tlsConfig == nil {
+		return fmt.Errorf("ReloadCRLs: %w", TLSNotEnabled)
+	}
+	if err := tlsConfig.loadCRLs(is.tlsConfig); err != nil {
+		return fmt.Errorf("ReloadCRLs: %w", err)
+	}
+	return nil
+}
+
+// ReloadTLSConfig reconnects the client with a new TLS configuration.
+// The old connection is only closed after the new one is established, so on error the client stays
+// connected with its current configuration. An installed connection closed handler is carried over,
+// report handlers and other per-connection state have to be installed again.
+// It must not be called concurrently with other methods of the client.
+func (c *Client) ReloadTLSConfig(tlsConfig *TLSConfig) error {
+	if c.tlsConfig == nil {
+		return fmt.Errorf("ReloadTLSConfig: %w", TLSNotEnabled)
+	}
+
+	reloaded := &Client{}
+	if err := reloaded.connect(c.settings, tlsConfig); err != nil {
+		return fmt.Errorf("ReloadTLSConfig: %w", err)
+	}
+
+	// the old connection must not report itself as closed
+	C.IedConnection_installConnectionClosedHandler(c.conn, nil, nil)
+	C.IedConnection_destroy(c.conn)
+	C.TLSConfiguration_destroy(c.tlsConfig)
+
+	c.conn = reloaded.conn
+	c.tlsConfig = reloaded.tlsConfig
+	if c.closedHandlerId != 0 {
+		cPtr := intToPointerBug58625(c.closedHandlerId)
+		C.IedConnection_installConnectionClosedHandler(c.conn, (*[0]byte)(C.connectionClosedHandlerBridge), cPtr)
+	}
+	return nil
+}
+
+// TLSFileChange describes a change of the files a TLSConfig is loaded from.
+type TLSFileChange struct {
+	// CRLOnly is true if only CRL files changed, which can be applied with ReloadCRLs.
+	CRLOnly bool
+}
+
+// TLSFileWatcher polls the files of a TLSConfig and reports changes on C.
+// Changes are delivered to the application instead of being applied by the watcher,
+// so the reload runs on a goroutine that owns the server or client.
+type TLSFileWatcher struct {
+	C        <-chan TLSFileChange
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+type tlsFileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// WatchTLSFiles starts polling the key, certificate, CA, allowed certificate and CRL files of tlsConfig.
+// Missing files are skipped until they appear again.
+func WatchTLSFiles(tlsConfig *TLSConfig, interval time.Duration) *TLSFileWatcher {
+	changes := make(chan TLSFileChange, 1)
+	w := &TLSFileWatcher{C: changes, stop: make(chan struct{})}
+
+	certFiles := tlsConfig.files()
+	crlFiles := append([]string(nil), tlsConfig.crls...)
+	certStamps := statTLSFiles(certFiles)
+	crlStamps := statTLSFiles(crlFiles)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+			}
+
+			certChanged := updateTLSFileStamps(certFiles, certStamps)
+			crlChanged := updateTLSFileStamps(crlFiles, crlStamps)
+			if !certChanged && !crlChanged {
+				continue
+			}
+
+			change := TLSFileChange{CRLOnly: !certChanged}
+			select {
+			case changes <- change:
+			case <-w.stop:
+				return
+			default:
+				// a change is still pending, widen it if necessary
+				select {
+				case pending := <-changes:
+					change.CRLOnly = change.CRLOnly && pending.CRLOnly
+				default:
+				}
+				changes <- change
+			}
+		}
+	}()
+	return w
+}
+
+// Stop ends the polling. It may be called more than once.
+func (w *TLSFileWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+}
+
+func statTLSFiles(files []string) map[string]tlsFileStamp {
+	stamps := make(map[string]tlsFileStamp, len(files))
+	updateTLSFileStamps(files, stamps)
+	return stamps
+}
+
+// updateTLSFileStamps refreshes stamps and reports whether a readable file changed since the last call.
+func updateTLSFileStamps(files []string, stamps map[string]tlsFileStamp) bool {
+	changed := false
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		stamp := tlsFileStamp{modTime: info.ModTime(), size: info.Size()}
+		if old, ok := stamps[file]; !ok || old != stamp {
+			stamps[file] = stamp
+			changed = true
+		}
+	}
+	return changed
+}
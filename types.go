@@ -73,6 +73,92 @@ const (
 	CONTROL_RESULT_WAITING
 )
 
+// CheckHandlerResult is the result of a ControlPerformCheckHandler.
+// Values must match the C enum.
+type CheckHandlerResult int
+
+const (
+	// CONTROL_ACCEPTED check passed
+	CONTROL_ACCEPTED CheckHandlerResult = -1
+	// CONTROL_WAITING_FOR_SELECT select operation in progress, the handler is called again later
+	CONTROL_WAITING_FOR_SELECT CheckHandlerResult = 0
+	// CONTROL_HARDWARE_FAULT check failed due to hardware fault
+	CONTROL_HARDWARE_FAULT CheckHandlerResult = 1
+	// CONTROL_TEMPORARILY_UNAVAILABLE control is already selected or operated
+	CONTROL_TEMPORARILY_UNAVAILABLE CheckHandlerResult = 2
+	// CONTROL_OBJECT_ACCESS_DENIED check failed due to access control reasons
+	CONTROL_OBJECT_ACCESS_DENIED CheckHandlerResult = 3
+	// CONTROL_OBJECT_UNDEFINED object not visible in this security context
+	CONTROL_OBJECT_UNDEFINED CheckHandlerResult = 4
+	// CONTROL_VALUE_INVALID ctlVal out of range
+	CONTROL_VALUE_INVALID CheckHandlerResult = 11
+)
+
+// ControlAddCause is the AddCause sent to the client when a control fails.
+// Values must match the C enum ordering.
+type ControlAddCause int
+
+const (
+	ADD_CAUSE_UNKNOWN ControlAddCause = iota
+	ADD_CAUSE_NOT_SUPPORTED
+	ADD_CAUSE_BLOCKED_BY_SWITCHING_HIERARCHY
+	ADD_CAUSE_SELECT_FAILED
+	ADD_CAUSE_INVALID_POSITION
+	ADD_CAUSE_POSITION_REACHED
+	ADD_CAUSE_PARAMETER_CHANGE_IN_EXECUTION
+	ADD_CAUSE_STEP_LIMIT
+	ADD_CAUSE_BLOCKED_BY_MODE
+	ADD_CAUSE_BLOCKED_BY_PROCESS
+	ADD_CAUSE_BLOCKED_BY_INTERLOCKING
+	ADD_CAUSE_BLOCKED_BY_SYNCHROCHECK
+	ADD_CAUSE_COMMAND_ALREADY_IN_EXECUTION
+	ADD_CAUSE_BLOCKED_BY_HEALTH
+	ADD_CAUSE_1_OF_N_CONTROL
+	ADD_CAUSE_ABORTION_BY_CANCEL
+	ADD_CAUSE_TIME_LIMIT_OVER
+	ADD_CAUSE_ABORTION_BY_TRIP
+	ADD_CAUSE_OBJECT_NOT_SELECTED
+	ADD_CAUSE_OBJECT_ALREADY_SELECTED
+	ADD_CAUSE_NO_ACCESS_AUTHORITY
+	ADD_CAUSE_ENDED_WITH_OVERSHOOT
+	ADD_CAUSE_ABORTION_DUE_TO_DEVIATION
+	ADD_CAUSE_ABORTION_BY_COMMUNICATION_LOSS
+	ADD_CAUSE_ABORTION_BY_COMMAND
+	ADD_CAUSE_NONE
+	ADD_CAUSE_INCONSISTENT_PARAMETERS
+	ADD_CAUSE_LOCKED_BY_OTHER_CLIENT
+)
+
+// ControlLastApplError is the error code of the LastApplError sent to the client when a control fails.
+// Values must match the C enum ordering.
+type ControlLastApplError int
+
+const (
+	CONTROL_ERROR_NO_ERROR ControlLastApplError = iota
+	CONTROL_ERROR_UNKNOWN
+	CONTROL_ERROR_TIMEOUT_TEST
+	CONTROL_ERROR_OPERATOR_TEST
+)
+
+// SelectStateChangedReason tells why the select state of a control object changed.
+// Values must match the C enum ordering.
+type SelectStateChangedReason int
+
+const (
+	// SELECT_STATE_REASON_SELECTED control has been selected
+	SELECT_STATE_REASON_SELECTED SelectStateChangedReason = iota
+	// SELECT_STATE_REASON_CANCELED cancel received for the control
+	SELECT_STATE_REASON_CANCELED
+	// SELECT_STATE_REASON_TIMEOUT unselected due to timeout (sboTimeout)
+	SELECT_STATE_REASON_TIMEOUT
+	// SELECT_STATE_REASON_OPERATED unselected due to successful operate
+	SELECT_STATE_REASON_OPERATED
+	// SELECT_STATE_REASON_OPERATE_FAILED unselected due to failed operate
+	SELECT_STATE_REASON_OPERATE_FAILED
+	// SELECT_STATE_REASON_DISCONNECTED unselected due to disconnection of selecting client
+	SELECT_STATE_REASON_DISCONNECTED
+)
+
 type ControlModel int
 
 const (
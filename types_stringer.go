@@ -161,3 +161,19 @@ func mmsTypeName(t MmsType) string {
 func (mt MmsType) String() string {
 	return mmsTypeName(mt)
 }
+
+// MarshalText encodes the MmsType as its name, so serialized data models stay readable.
+func (mt MmsType) MarshalText() ([]byte, error) {
+	return []byte(mmsTypeName(mt)), nil
+}
+
+// UnmarshalText decodes a name written by MarshalText.
+func (mt *MmsType) UnmarshalText(text []byte) error {
+	for t := Array; t <= Uint32; t++ {
+		if mmsTypeName(t) == string(text) {
+			*mt = t
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown MMS type %q", text)
+}